@@ -0,0 +1,300 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// add-on templates installed on most clusters
+const (
+	AddonCoreDNS       = "coredns"
+	AddonEverest       = "everest"
+	AddonAutoscaler    = "autoscaler"
+	AddonMetricsServer = "metrics-server"
+)
+
+const (
+	AddonStatusInstalling     = "installing"
+	AddonStatusInstallFailed  = "installFailed"
+	AddonStatusRunning        = "running"
+	AddonStatusAvailable      = "available"
+	AddonStatusAbnormal       = "abnormal"
+	AddonStatusUpgrading      = "upgrading"
+	AddonStatusUpgradeFailed  = "upgradeFailed"
+	AddonStatusRollbacking    = "rollbacking"
+	AddonStatusRollbackFailed = "rollbackFailed"
+	AddonStatusDeleting       = "deleting"
+	AddonStatusDeleteFailed   = "deleteFailed"
+)
+
+const (
+	// AddonPollInterval and DefaultAddonTimeout are used when AddonWaitOptions
+	// leaves them empty
+	AddonPollInterval   = 10 * time.Second
+	DefaultAddonTimeout = 15 * time.Minute
+)
+
+type AddonWaitOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type addonTemplateFilter struct {
+	Name string `json:"addon_template_name,omitempty"`
+}
+
+type addonFilter struct {
+	ClusterID string `json:"cluster_id"`
+}
+
+type addonRollbackRequest struct {
+	ClusterID string `json:"clusterID"`
+}
+
+// addonURL returns the url of the add-on apis, which are not project scoped
+func (c *Client) addonURL(paths ...string) string {
+	return fmt.Sprintf("%s%s/%s", c.GetAPIEndpointFunc(), c.GetAPIPrefixFunc(), strings.Join(paths, "/"))
+}
+
+// ListAddonTemplates returns the add-on templates, all of them when name is empty
+func (c *Client) ListAddonTemplates(ctx context.Context, name string) (*common.AddonTemplateList, error) {
+	rtn := common.AddonTemplateList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		common.WithQuery(c.addonURL("addontemplates"), &addonTemplateFilter{Name: name}),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// ListAddonVersions returns the versions of the add-on template which can be
+// installed on clusterVersion, all versions when clusterVersion is empty
+func (c *Client) ListAddonVersions(ctx context.Context, name, clusterVersion string) ([]common.AddonVersion, error) {
+	if name == "" {
+		return nil, errors.New("add-on template name is required")
+	}
+	templates, err := c.ListAddonTemplates(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var rtn []common.AddonVersion
+	for _, template := range templates.Items {
+		if template.MetaData.Name != name {
+			continue
+		}
+		for _, version := range template.Spec.Versions {
+			if clusterVersion == "" || addonSupports(version, clusterVersion) {
+				rtn = append(rtn, version)
+			}
+		}
+	}
+	return rtn, nil
+}
+
+func addonSupports(version common.AddonVersion, clusterVersion string) bool {
+	for _, support := range version.SupportVersions {
+		for _, pattern := range support.ClusterVersion {
+			if ok, _ := regexp.MatchString("^"+pattern+"$", clusterVersion); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DefaultAddonValues returns the basic, custom and first flavor values from
+// the input of the template version, callers change Custom and Flavor as needed
+func DefaultAddonValues(version *common.AddonVersion) common.AddonValues {
+	values := common.AddonValues{Basic: map[string]interface{}{}}
+	if basic, ok := version.Input["basic"].(map[string]interface{}); ok {
+		values.Basic = basic
+	}
+	if parameters, ok := version.Input["parameters"].(map[string]interface{}); ok {
+		if custom, ok := parameters["custom"].(map[string]interface{}); ok {
+			values.Custom = custom
+		}
+		if flavor, ok := parameters["flavor1"].(map[string]interface{}); ok {
+			values.Flavor = flavor
+		}
+	}
+	return values
+}
+
+func validateAddon(clusterID, name, version string) error {
+	if clusterID == "" || name == "" || version == "" {
+		return errors.New("cluster id, add-on template name and version are required")
+	}
+	return nil
+}
+
+// InstallAddon installs the version of the add-on template on the cluster,
+// see WaitForAddonRunning
+func (c *Client) InstallAddon(ctx context.Context, clusterID, name, version string, values common.AddonValues) (*common.AddonInstance, error) {
+	if err := validateAddon(clusterID, name, version); err != nil {
+		return nil, err
+	}
+	rtn := common.AddonInstance{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.addonURL("addons"),
+		&common.AddonInstance{
+			Kind:       "Addon",
+			APIVersion: "v3",
+			MetaData:   common.AddonMetaData{Annotations: map[string]string{"addon.install/type": "install"}},
+			Spec: common.AddonInstanceSpec{
+				ClusterID:         clusterID,
+				Version:           version,
+				AddonTemplateName: name,
+				Values:            values,
+			},
+		},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) GetAddon(ctx context.Context, clusterID, id string) (*common.AddonInstance, error) {
+	if clusterID == "" || id == "" {
+		return nil, errors.New("add-on id and cluster id are required")
+	}
+	rtn := common.AddonInstance{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		common.WithQuery(c.addonURL("addons", id), &addonFilter{ClusterID: clusterID}),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) ListAddons(ctx context.Context, clusterID string) (*common.AddonInstanceList, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.AddonInstanceList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		common.WithQuery(c.addonURL("addons"), &addonFilter{ClusterID: clusterID}),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// UpgradeAddon moves the add-on to version with the values, which can also
+// be the current version to change the values only
+func (c *Client) UpgradeAddon(ctx context.Context, clusterID, id, name, version string, values common.AddonValues) (*common.AddonInstance, error) {
+	if id == "" {
+		return nil, errors.New("add-on id is required")
+	}
+	if err := validateAddon(clusterID, name, version); err != nil {
+		return nil, err
+	}
+	rtn := common.AddonInstance{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.addonURL("addons", id),
+		&common.AddonInstance{
+			Kind:       "Addon",
+			APIVersion: "v3",
+			MetaData:   common.AddonMetaData{Annotations: map[string]string{"addon.upgrade/type": "upgrade"}},
+			Spec: common.AddonInstanceSpec{
+				ClusterID:         clusterID,
+				Version:           version,
+				AddonTemplateName: name,
+				Values:            values,
+			},
+		},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// RollbackAddon restores the version and values of the add-on before its last upgrade
+func (c *Client) RollbackAddon(ctx context.Context, clusterID, id string) (*common.AddonInstance, error) {
+	if clusterID == "" || id == "" {
+		return nil, errors.New("add-on id and cluster id are required")
+	}
+	rtn := common.AddonInstance{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.addonURL("addons", id, "operation", "rollback"),
+		&addonRollbackRequest{ClusterID: clusterID},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) UninstallAddon(ctx context.Context, clusterID, id string) error {
+	if clusterID == "" || id == "" {
+		return errors.New("add-on id and cluster id are required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		common.WithQuery(c.addonURL("addons", id), &addonFilter{ClusterID: clusterID}),
+		nil,
+		nil,
+	)
+	return err
+}
+
+// WaitForAddonRunning waits until all pods of the add-on are running, an
+// error is returned when the last install, upgrade or rollback failed. opts
+// can be nil.
+func (c *Client) WaitForAddonRunning(ctx context.Context, clusterID, id string, opts *AddonWaitOptions) (*common.AddonInstance, error) {
+	interval, timeout := AddonPollInterval, DefaultAddonTimeout
+	if opts != nil {
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+	}
+	var last *common.AddonInstance
+	err := common.CustomWaitForCompleteUntilTrue(ctx, interval, timeout, func(ictx context.Context) (bool, error) {
+		addon, err := c.GetAddon(ictx, clusterID, id)
+		if err != nil {
+			return false, err
+		}
+		last = addon
+		if addon.Status == nil {
+			return false, nil
+		}
+		switch addon.Status.Status {
+		case AddonStatusRunning:
+			return true, nil
+		case AddonStatusInstallFailed, AddonStatusUpgradeFailed, AddonStatusRollbackFailed, AddonStatusDeleteFailed:
+			return false, fmt.Errorf("add-on %s of cluster %s is %s: %s %s", id, clusterID, addon.Status.Status, addon.Status.Reason, addon.Status.Message)
+		}
+		return false, nil
+	})
+	return last, err
+}
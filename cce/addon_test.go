@@ -0,0 +1,178 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+var corednsTemplate = common.AddonTemplate{
+	MetaData: common.AddonMetaData{Name: AddonCoreDNS},
+	Spec: common.AddonTemplateSpec{Versions: []common.AddonVersion{
+		{
+			Version:         "1.17.4",
+			SupportVersions: []common.AddonSupportVersion{{ClusterType: "VirtualMachine", ClusterVersion: []string{"v1.15.*", "v1.17.*"}}},
+		},
+		{
+			Version: "1.23.1",
+			Input: map[string]interface{}{
+				"basic":      map[string]interface{}{"swr_addr": "swr.example.com"},
+				"parameters": map[string]interface{}{"custom": map[string]interface{}{"stub_domains": ""}, "flavor1": map[string]interface{}{"replicas": 2}},
+			},
+			SupportVersions: []common.AddonSupportVersion{{ClusterType: "VirtualMachine", ClusterVersion: []string{"v1.19.*", "v1.21.*"}}},
+		},
+	}},
+}
+
+// addonServer installs, upgrades and rolls back its add-ons on the next GET
+type addonServer struct {
+	sync.Mutex
+	addons   map[string]*common.AddonInstance
+	previous map[string]common.AddonInstanceSpec
+	pending  map[string]string
+}
+
+func (s *addonServer) serve(r *http.Request) (int, interface{}) {
+	s.Lock()
+	defer s.Unlock()
+	if r.URL.Path == "/api/v3/addontemplates" {
+		return 200, common.AddonTemplateList{Items: []common.AddonTemplate{corednsTemplate}}
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/v3/addons") {
+		return 404, cceNotFound
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v3/addons"), "/")
+	if id == "" && r.Method == http.MethodPost {
+		addon := &common.AddonInstance{}
+		json.NewDecoder(r.Body).Decode(addon)
+		addon.MetaData.UID = "addon-1"
+		addon.Status = &common.AddonInstanceStatus{Status: AddonStatusInstalling}
+		s.addons[addon.MetaData.UID] = addon
+		s.pending[addon.MetaData.UID] = AddonStatusRunning
+		return 201, addon
+	}
+	if r.URL.Query().Get("cluster_id") != "cluster-1" && !strings.HasSuffix(id, "/operation/rollback") && r.Method != http.MethodPut {
+		return 400, cceError("CCE.01400001", "cluster_id is required")
+	}
+	if id == "" {
+		list := common.AddonInstanceList{}
+		for _, addon := range s.addons {
+			list.Items = append(list.Items, *addon)
+		}
+		return 200, list
+	}
+	if strings.HasSuffix(id, "/operation/rollback") {
+		id = strings.TrimSuffix(id, "/operation/rollback")
+		addon, ok := s.addons[id]
+		if !ok {
+			return 404, cceNotFound
+		}
+		addon.Spec = s.previous[id]
+		addon.Status.Status = AddonStatusRollbacking
+		s.pending[id] = AddonStatusRunning
+		return 200, addon
+	}
+	addon, ok := s.addons[id]
+	if !ok {
+		return 404, cceNotFound
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if next, ok := s.pending[id]; ok {
+			addon.Status.Status = next
+			delete(s.pending, id)
+		}
+		return 200, addon
+	case http.MethodPut:
+		update := &common.AddonInstance{}
+		json.NewDecoder(r.Body).Decode(update)
+		s.previous[id] = addon.Spec
+		addon.Spec = update.Spec
+		addon.Status.Status = AddonStatusUpgrading
+		s.pending[id] = AddonStatusUpgradeFailed
+		return 200, addon
+	case http.MethodDelete:
+		delete(s.addons, id)
+		return 200, nil
+	}
+	return 405, cceError("CCE.01405001", r.Method)
+}
+
+func TestAddon(t *testing.T) {
+	server := &addonServer{
+		addons:   map[string]*common.AddonInstance{},
+		previous: map[string]common.AddonInstanceSpec{},
+		pending:  map[string]string{},
+	}
+	c := newTestClient(cceHandler(server.serve))
+	root := context.Background()
+	wait := &AddonWaitOptions{Interval: time.Millisecond}
+
+	versions, err := c.ListAddonVersions(root, AddonCoreDNS, "v1.19.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Version != "1.23.1" {
+		t.Fatalf("unexpected versions %#v", versions)
+	}
+	values := DefaultAddonValues(&versions[0])
+	if values.Basic["swr_addr"] != "swr.example.com" || values.Flavor["replicas"] != float64(2) {
+		t.Fatalf("unexpected default values %#v", values)
+	}
+	values.Custom["stub_domains"] = `{"example.com":["10.0.0.1"]}`
+
+	if _, err := c.InstallAddon(root, "cluster-1", AddonCoreDNS, "", values); err == nil {
+		t.Fatal("expected error without version")
+	}
+	installed, err := c.InstallAddon(root, "cluster-1", AddonCoreDNS, "1.23.1", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed.MetaData.Annotations["addon.install/type"] != "install" || installed.Spec.Values.Custom["stub_domains"] == "" {
+		t.Fatalf("unexpected install request %#v", installed)
+	}
+	running, err := c.WaitForAddonRunning(root, "cluster-1", installed.MetaData.UID, wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running.Status.Status != AddonStatusRunning {
+		t.Fatalf("expected running add-on, got %s", running.Status.Status)
+	}
+
+	if _, err := c.UpgradeAddon(root, "cluster-1", installed.MetaData.UID, AddonCoreDNS, "1.25.1", values); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WaitForAddonRunning(root, "cluster-1", installed.MetaData.UID, wait); err == nil || !strings.Contains(err.Error(), AddonStatusUpgradeFailed) {
+		t.Fatalf("expected upgrade to fail, got %v", err)
+	}
+	if _, err := c.RollbackAddon(root, "cluster-1", installed.MetaData.UID); err != nil {
+		t.Fatal(err)
+	}
+	rolledBack, err := c.WaitForAddonRunning(root, "cluster-1", installed.MetaData.UID, wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Spec.Version != "1.23.1" {
+		t.Fatalf("expected version 1.23.1 after rollback, got %s", rolledBack.Spec.Version)
+	}
+
+	list, err := c.ListAddons(root, "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected one add-on, got %d", len(list.Items))
+	}
+	if err := c.UninstallAddon(root, "cluster-1", installed.MetaData.UID); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.addons) != 0 {
+		t.Fatal("expected add-on to be uninstalled")
+	}
+}
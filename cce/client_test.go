@@ -1,12 +1,44 @@
 package cce
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
 )
 
+// cceHandler serves the requests of the fake cce servers, it returns the
+// status and the body of the response which is encoded as json
+type cceHandler func(r *http.Request) (int, interface{})
+
+func (h cceHandler) RoundTrip(r *http.Request) (*http.Response, error) {
+	return cceResponse(h(r))
+}
+
+func cceResponse(status int, body interface{}) (*http.Response, error) {
+	b, _ := json.Marshal(body)
+	return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+// cceError is the body of a cce error response
+func cceError(code, reason string) map[string]string {
+	return map[string]string{"errorCode": code, "reason": reason}
+}
+
+// cceNotFound is returned for unknown paths and resources
+var cceNotFound = cceError("CCE.01404001", "not found")
+
+// newTestClient returns a cce client whose requests are sent to transport
+func newTestClient(transport http.RoundTripper) *Client {
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = transport
+	return NewClient(base)
+}
+
 func Test_CCEGetURL(t *testing.T) {
 	baseClient := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
 	c := NewClient(baseClient)
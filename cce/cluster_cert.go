@@ -25,3 +25,26 @@ func (c *Client) GetClusterCert(ctx context.Context, clusterid string) (*common.
 	}
 	return &rtn, nil
 }
+
+// GetClusterCertWithDuration returns the cluster cert with a client
+// certificate valid for duration days, -1 is the maximum
+func (c *Client) GetClusterCertWithDuration(ctx context.Context, clusterid string, duration int64) (*common.ClusterCert, error) {
+	if clusterid == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	if duration == 0 || duration < -1 {
+		return nil, errors.New("duration has to be -1 or a number of days")
+	}
+	rtn := common.ClusterCert{}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterid, "clustercert"),
+		&common.ClusterCertRequest{Duration: duration},
+		&rtn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
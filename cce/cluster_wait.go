@@ -0,0 +1,181 @@
+package cce
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ClusterPhaseAvailable   = "Available"
+	ClusterPhaseUnavailable = "Unavailable"
+	ClusterPhaseCreating    = "Creating"
+	ClusterPhaseDeleting    = "Deleting"
+	ClusterPhaseUpgrading   = "Upgrading"
+	ClusterPhaseError       = "Error"
+)
+
+const (
+	// ClusterPollInterval and DefaultClusterTimeout are used when ClusterWaitOptions leaves them empty,
+	// creating a cluster takes more than 10 minutes
+	ClusterPollInterval   = 15 * time.Second
+	DefaultClusterTimeout = 30 * time.Minute
+)
+
+// ClusterProgress is passed to ClusterWaitOptions.OnProgress on every poll
+type ClusterProgress struct {
+	ClusterID string
+	Phase     string
+	// JobID and JobPhase are set while the job of CreateClusterAndWait is tracked
+	JobID    string
+	JobPhase string
+	Elapsed  time.Duration
+	Cluster  *common.ClusterInfo
+}
+
+type ClusterWaitOptions struct {
+	Interval   time.Duration
+	Timeout    time.Duration
+	OnProgress func(ClusterProgress)
+}
+
+// ClusterFailedError is returned when the cluster or its job ends in a phase
+// which is not waited for any more
+type ClusterFailedError struct {
+	ClusterID  string
+	Phase      string
+	Reason     string
+	Message    string
+	Conditions *common.Conditions
+}
+
+func (e *ClusterFailedError) Error() string {
+	msg := fmt.Sprintf("cluster %s is %s", e.ClusterID, e.Phase)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Conditions != nil {
+		msg += fmt.Sprintf(" (condition %s=%s, reason %s: %s)", e.Conditions.Type, e.Conditions.Status, e.Conditions.Reason, e.Conditions.Message)
+	}
+	return msg
+}
+
+func clusterTerminal(phase string) bool {
+	return phase == ClusterPhaseError || phase == ClusterPhaseUnavailable
+}
+
+// WaitForClusterPhase polls the cluster until it is in phase. Error and
+// Unavailable end the wait with a *ClusterFailedError unless they are waited for.
+func (c *Client) WaitForClusterPhase(ctx context.Context, id, phase string, opts *ClusterWaitOptions) (*common.ClusterInfo, error) {
+	return c.waitForCluster(ctx, id, "", phase, opts)
+}
+
+// CreateClusterAndWait creates the cluster and waits until it is Available.
+// The creation job is polled as well, so that a failed job ends the wait
+// before the cluster reports it. A paused job ends the wait with an error
+// wrapping common.ErrJobPaused.
+func (c *Client) CreateClusterAndWait(ctx context.Context, cluster *common.ClusterInfo, opts *ClusterWaitOptions) (*common.ClusterInfo, error) {
+	created, err := c.CreateCluster(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	jobID := ""
+	if created.Status != nil {
+		jobID = created.Status.JobID
+	}
+	return c.waitForCluster(ctx, created.MetaData.UID, jobID, ClusterPhaseAvailable, opts)
+}
+
+func (c *Client) waitForCluster(ctx context.Context, id, jobID, phase string, opts *ClusterWaitOptions) (*common.ClusterInfo, error) {
+	if id == "" {
+		return nil, fmt.Errorf("cluster id is required")
+	}
+	interval, timeout := ClusterPollInterval, DefaultClusterTimeout
+	var onProgress func(ClusterProgress)
+	if opts != nil {
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+		onProgress = opts.OnProgress
+	}
+
+	start := time.Now()
+	var last *common.ClusterInfo
+	lastPhase := ""
+	err := common.CustomWaitForCompleteUntilTrue(ctx, interval, timeout, func(ictx context.Context) (bool, error) {
+		progress := ClusterProgress{ClusterID: id, JobID: jobID}
+		if jobID != "" {
+			job, err := c.GetJobV3(ictx, jobID)
+			if err != nil {
+				return false, err
+			}
+			progress.JobPhase = job.Status.Phase
+			if job.Status.Phase != "" {
+				done, err := common.JobPhaseDone(job.Status.Phase)
+				if errors.Cause(err) == common.ErrJobPaused {
+					return false, errors.Wrapf(err, "job %s of cluster %s", jobID, id)
+				}
+				if err != nil {
+					return false, &ClusterFailedError{
+						ClusterID: id,
+						Phase:     job.Status.Phase,
+						Reason:    job.Status.Reason,
+						Message:   job.Status.Message,
+					}
+				}
+				if done {
+					jobID = ""
+				}
+			}
+		}
+
+		cluster, err := c.GetCluster(ictx, id)
+		if err != nil {
+			return false, err
+		}
+		last = cluster
+		status := cluster.Status
+		if status == nil {
+			status = &common.StatusInfo{}
+		}
+		lastPhase = status.Phase
+		progress.Phase = status.Phase
+		progress.Elapsed = time.Since(start)
+		progress.Cluster = cluster
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		logrus.Debugf("cluster %s is %s, waiting for %s", id, status.Phase, phase)
+
+		if status.Phase == phase {
+			return true, nil
+		}
+		if clusterTerminal(status.Phase) {
+			return false, &ClusterFailedError{
+				ClusterID:  id,
+				Phase:      status.Phase,
+				Reason:     status.Reason,
+				Message:    status.Message,
+				Conditions: status.Conditions,
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		if _, ok := err.(*ClusterFailedError); ok || errors.Cause(err) == common.ErrJobPaused {
+			return last, err
+		}
+		return last, fmt.Errorf("error waiting for cluster %s to be %s, last phase %q: %v", id, phase, lastPhase, err)
+	}
+	return last, nil
+}
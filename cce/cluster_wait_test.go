@@ -0,0 +1,152 @@
+package cce
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/pkg/errors"
+)
+
+// clusterTransport answers every path with the next of its responses, the
+// last one is repeated
+type clusterTransport struct {
+	sync.Mutex
+	responses map[string][]interface{}
+}
+
+func (c *clusterTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.Lock()
+	defer c.Unlock()
+	key := r.Method + " " + r.URL.Path
+	list := c.responses[key]
+	if len(list) == 0 {
+		return cceResponse(404, cceNotFound)
+	}
+	if len(list) > 1 {
+		c.responses[key] = list[1:]
+	}
+	return cceResponse(200, list[0])
+}
+
+func clusterInPhase(phase, reason string) common.ClusterInfo {
+	return common.ClusterInfo{
+		MetaData: common.MetaInfo{UID: "cluster-1"},
+		Status:   &common.StatusInfo{Phase: phase, Reason: reason},
+	}
+}
+
+func jobInPhase(phase, reason string) common.JobInfo {
+	return common.JobInfo{Status: common.JobStatus{Phase: phase, Reason: reason}}
+}
+
+func newWaitClient(responses map[string][]interface{}) *Client {
+	return newTestClient(&clusterTransport{responses: responses})
+}
+
+func TestCreateClusterAndWait(t *testing.T) {
+	created := clusterInPhase(ClusterPhaseCreating, "")
+	created.Status.JobID = "job-1"
+	c := newWaitClient(map[string][]interface{}{
+		"POST /api/v3/projects/test/clusters":          {created},
+		"GET /api/v3/projects/test/jobs/job-1":         {jobInPhase("Init", ""), jobInPhase("Running", ""), jobInPhase("Success", "")},
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseCreating, ""), clusterInPhase(ClusterPhaseCreating, ""), clusterInPhase(ClusterPhaseAvailable, "")},
+	})
+
+	var progress []ClusterProgress
+	cluster, err := c.CreateClusterAndWait(context.Background(), &common.ClusterInfo{}, &ClusterWaitOptions{
+		Interval:   time.Millisecond,
+		OnProgress: func(p ClusterProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Status.Phase != ClusterPhaseAvailable {
+		t.Fatalf("expected available cluster, got %s", cluster.Status.Phase)
+	}
+	if len(progress) != 3 || progress[0].JobPhase != "Init" || progress[2].JobPhase != "Success" || progress[2].Phase != ClusterPhaseAvailable {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestCreateClusterAndWaitJobFailed(t *testing.T) {
+	created := clusterInPhase(ClusterPhaseCreating, "")
+	created.Status.JobID = "job-1"
+	c := newWaitClient(map[string][]interface{}{
+		"POST /api/v3/projects/test/clusters":          {created},
+		"GET /api/v3/projects/test/jobs/job-1":         {jobInPhase("Running", ""), jobInPhase("Failed", "subnet has no free ip")},
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseCreating, "")},
+	})
+	_, err := c.CreateClusterAndWait(context.Background(), &common.ClusterInfo{}, &ClusterWaitOptions{Interval: time.Millisecond})
+	failed, ok := err.(*ClusterFailedError)
+	if !ok || failed.Reason != "subnet has no free ip" {
+		t.Fatalf("expected job failure, got %v", err)
+	}
+}
+
+func TestCreateClusterAndWaitJobQueuing(t *testing.T) {
+	created := clusterInPhase(ClusterPhaseCreating, "")
+	created.Status.JobID = "job-1"
+	c := newWaitClient(map[string][]interface{}{
+		"POST /api/v3/projects/test/clusters":          {created},
+		"GET /api/v3/projects/test/jobs/job-1":         {jobInPhase("Queuing", ""), jobInPhase("Queuing", ""), jobInPhase("Success", "")},
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseCreating, ""), clusterInPhase(ClusterPhaseCreating, ""), clusterInPhase(ClusterPhaseAvailable, "")},
+	})
+	cluster, err := c.CreateClusterAndWait(context.Background(), &common.ClusterInfo{}, &ClusterWaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("a queuing job should be waited for: %v", err)
+	}
+	if cluster.Status.Phase != ClusterPhaseAvailable {
+		t.Fatalf("expected available cluster, got %s", cluster.Status.Phase)
+	}
+}
+
+func TestCreateClusterAndWaitJobPaused(t *testing.T) {
+	created := clusterInPhase(ClusterPhaseCreating, "")
+	created.Status.JobID = "job-1"
+	c := newWaitClient(map[string][]interface{}{
+		"POST /api/v3/projects/test/clusters":          {created},
+		"GET /api/v3/projects/test/jobs/job-1":         {jobInPhase("Running", ""), jobInPhase("Pause", "")},
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseCreating, "")},
+	})
+	_, err := c.CreateClusterAndWait(context.Background(), &common.ClusterInfo{}, &ClusterWaitOptions{Interval: time.Millisecond})
+	if _, ok := err.(*ClusterFailedError); ok || errors.Cause(err) != common.ErrJobPaused {
+		t.Fatalf("expected paused job, got %v", err)
+	}
+}
+
+func TestWaitForClusterPhase(t *testing.T) {
+	errored := clusterInPhase(ClusterPhaseError, "InternalError")
+	errored.Status.Message = "master creation failed"
+	errored.Status.Conditions = &common.Conditions{Type: "Ready", Status: "False", Reason: "EtcdUnhealthy"}
+	c := newWaitClient(map[string][]interface{}{
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseUpgrading, ""), errored},
+	})
+	cluster, err := c.WaitForClusterPhase(context.Background(), "cluster-1", ClusterPhaseAvailable, &ClusterWaitOptions{Interval: time.Millisecond})
+	if _, ok := err.(*ClusterFailedError); !ok {
+		t.Fatalf("expected cluster failure, got %v", err)
+	}
+	for _, s := range []string{"InternalError", "master creation failed", "EtcdUnhealthy"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("expected %q in error %q", s, err.Error())
+		}
+	}
+	if cluster == nil || cluster.Status.Phase != ClusterPhaseError {
+		t.Fatalf("expected the last cluster to be returned, got %#v", cluster)
+	}
+
+	c = newWaitClient(map[string][]interface{}{
+		"GET /api/v3/projects/test/clusters/cluster-1": {clusterInPhase(ClusterPhaseUpgrading, "")},
+	})
+	_, err = c.WaitForClusterPhase(context.Background(), "cluster-1", ClusterPhaseAvailable, &ClusterWaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), ClusterPhaseUpgrading) {
+		t.Fatalf("expected timeout with last phase, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package credential provides the client certificates of CCE clusters to
+// kubectl as an exec credential plugin, see cmd/cce-credential. The
+// certificates are short-lived and cached until they are about to expire.
+package credential
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/cce"
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1alpha1"
+)
+
+// ExecInfoEnv has the ExecCredential which kubectl passes to the plugin
+const ExecInfoEnv = "KUBERNETES_EXEC_INFO"
+
+// RefreshBefore is how long before its expiration a cached certificate is renewed
+var RefreshBefore = 5 * time.Minute
+
+// ExecCredential is the v1alpha1 ExecCredential with the client certificate
+// fields of kubectl 1.11 and newer, which the vendored client-go does not
+// have yet
+type ExecCredential struct {
+	metav1.TypeMeta `json:",inline"`
+	Status          *ExecCredentialStatus `json:"status,omitempty"`
+}
+
+type ExecCredentialStatus struct {
+	v1alpha1.ExecCredentialStatus `json:",inline"`
+	ClientCertificateData         string `json:"clientCertificateData,omitempty"`
+	ClientKeyData                 string `json:"clientKeyData,omitempty"`
+}
+
+// Profile has the credentials of the cce client, the json names match the
+// environment variables of common.GetBaseClientFromENV
+type Profile struct {
+	AccessKey   string `json:"access_key"`
+	SecretKey   string `json:"secret_key"`
+	Region      string `json:"region"`
+	ProjectID   string `json:"project_id"`
+	APIEndpoint string `json:"api_endpoint,omitempty"`
+}
+
+// LoadBaseClient returns the client of the environment, or of the yaml
+// profile at path when the environment has no credentials
+func LoadBaseClient(path string) (*common.Client, error) {
+	if client, err := common.GetBaseClientFromENV(); err == nil {
+		return client, nil
+	}
+	if path == "" {
+		return nil, errors.New("ACCESS_KEY, SECRET_KEY, REGION and PROJECT_ID are not set and no profile is given")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profile := Profile{}
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("error parsing profile %s: %v", path, err)
+	}
+	if profile.AccessKey == "" || profile.SecretKey == "" || profile.Region == "" || profile.ProjectID == "" {
+		return nil, fmt.Errorf("profile %s needs access_key, secret_key, region and project_id", path)
+	}
+	endpoint := profile.APIEndpoint
+	if endpoint == "" {
+		endpoint = common.DefaultAPIEndpoint
+	}
+	return common.NewClient(profile.AccessKey, profile.SecretKey, endpoint, profile.Region, profile.ProjectID), nil
+}
+
+type Provider struct {
+	Client    *cce.Client
+	ClusterID string
+	// Duration is the validity of new certificates in days, -1 is the maximum
+	Duration int64
+	// Endpoint is the endpoint of the kubeconfig the certificate is taken
+	// from, see cce.KubeconfigOptions
+	Endpoint string
+	// CacheDir keeps the certificates, nothing is cached when it is empty.
+	// The certificates of different access keys, projects and durations are
+	// cached separately.
+	CacheDir string
+
+	now func() time.Time
+}
+
+// Credential returns the cached certificate of the cluster or a new one when
+// it is about to expire or kubectl got a 401 with it. info is the
+// ExecCredential passed by kubectl and can be nil.
+func (p *Provider) Credential(ctx context.Context, info *v1alpha1.ExecCredential) (*ExecCredential, error) {
+	if p.ClusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	rejected := info != nil && info.Spec.Response != nil && info.Spec.Response.Code == http.StatusUnauthorized
+	if cached := p.load(); cached != nil && !rejected {
+		if cached.Status.ExpirationTimestamp.Time.Sub(now()) > RefreshBefore {
+			return cached, nil
+		}
+	}
+
+	kubeconfig, err := p.Client.GetKubeconfig(ctx, p.ClusterID, &cce.KubeconfigOptions{Endpoint: p.Endpoint, Duration: p.Duration})
+	if err != nil {
+		return nil, err
+	}
+	cert, err := base64.StdEncoding.DecodeString(kubeconfig.Users[0].User.ClientCertificateData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding client certificate of cluster %s: %v", p.ClusterID, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(kubeconfig.Users[0].User.ClientKeyData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding client key of cluster %s: %v", p.ClusterID, err)
+	}
+	expiration, err := certificateExpiration(cert)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client certificate of cluster %s: %v", p.ClusterID, err)
+	}
+	rtn := &ExecCredential{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ExecCredential"},
+		Status: &ExecCredentialStatus{
+			ExecCredentialStatus:  v1alpha1.ExecCredentialStatus{ExpirationTimestamp: &metav1.Time{Time: expiration}},
+			ClientCertificateData: string(cert),
+			ClientKeyData:         string(key),
+		},
+	}
+	return rtn, p.store(rtn)
+}
+
+func certificateExpiration(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, errors.New("no pem data")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// cachePath is named after the cluster and a hash of the identity which
+// requests the certificate, so that switching credentials does not reuse the
+// certificate of another user
+func (p *Provider) cachePath() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", p.Client.AccessKey, p.Client.ProjectID, p.Duration)))
+	return filepath.Join(p.CacheDir, fmt.Sprintf("%s-%x.json", p.ClusterID, sum[:8]))
+}
+
+// load returns nil when there is no usable cached credential
+func (p *Provider) load() *ExecCredential {
+	if p.CacheDir == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(p.cachePath())
+	if err != nil {
+		return nil
+	}
+	cached := &ExecCredential{}
+	if err := json.Unmarshal(data, cached); err != nil || cached.Status == nil || cached.Status.ExpirationTimestamp == nil {
+		return nil
+	}
+	return cached
+}
+
+func (p *Provider) store(credential *ExecCredential) error {
+	if p.CacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(p.CacheDir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.cachePath(), data, 0600)
+}
@@ -0,0 +1,181 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/cce"
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1alpha1"
+)
+
+// certTransport serves cluster certs with a new client certificate valid
+// for a day on every request
+type certTransport struct {
+	t        *testing.T
+	requests int
+}
+
+func (c *certTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.requests++
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(c.requests)),
+		Subject:      pkix.Name{CommonName: "user"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	encode := func(kind string, der []byte) string {
+		return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}))
+	}
+	cert := common.ClusterCert{
+		Clusters: []common.ClusterConfig{
+			{Name: "internalCluster", Cluster: common.Cluster{Server: "https://192.168.0.73:5443"}},
+			{Name: "externalCluster", Cluster: common.Cluster{Server: "https://100.64.1.1:5443"}},
+		},
+		Users: []common.UserConfig{{Name: "user", User: common.User{ClientCertificateData: encode("CERTIFICATE", der), ClientKeyData: encode("EC PRIVATE KEY", keyDER)}}},
+		Contexts: []common.ContextConfig{
+			{Name: "internal", Context: common.Context{Cluster: "internalCluster", User: "user"}},
+			{Name: "external", Context: common.Context{Cluster: "externalCluster", User: "user"}},
+		},
+	}
+	b, _ := json.Marshal(cert)
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func TestProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cce-credential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	transport := &certTransport{t: t}
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = transport
+	provider := &Provider{Client: cce.NewClient(base), ClusterID: "cluster-1", Duration: 1, CacheDir: dir}
+	root := context.Background()
+
+	first, err := provider.Credential(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Kind != "ExecCredential" || first.APIVersion != "client.authentication.k8s.io/v1alpha1" {
+		t.Fatalf("unexpected type %#v", first.TypeMeta)
+	}
+	if expires := time.Until(first.Status.ExpirationTimestamp.Time); expires < 23*time.Hour || expires > 24*time.Hour {
+		t.Fatalf("expected the certificate to expire in a day, got %s", expires)
+	}
+	if first.Status.ClientKeyData == "" || first.Status.ClientCertificateData[:27] != "-----BEGIN CERTIFICATE-----" {
+		t.Fatalf("unexpected certificate %q", first.Status.ClientCertificateData)
+	}
+
+	cached, err := provider.Credential(root, &v1alpha1.ExecCredential{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 || cached.Status.ClientCertificateData != first.Status.ClientCertificateData {
+		t.Fatalf("expected the cached certificate, got %d requests", transport.requests)
+	}
+
+	rejected := &v1alpha1.ExecCredential{Spec: v1alpha1.ExecCredentialSpec{Response: &v1alpha1.Response{Code: http.StatusUnauthorized}}}
+	renewed, err := provider.Credential(root, rejected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 2 || renewed.Status.ClientCertificateData == first.Status.ClientCertificateData {
+		t.Fatal("expected a new certificate after a 401")
+	}
+
+	provider.now = func() time.Time { return renewed.Status.ExpirationTimestamp.Time.Add(-time.Minute) }
+	if _, err := provider.Credential(root, nil); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 3 {
+		t.Fatal("expected a new certificate before the cached one expires")
+	}
+}
+
+func TestProviderCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cce-credential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	transport := &certTransport{t: t}
+	newProvider := func(accessKey, projectID string, duration int64) *Provider {
+		base := common.NewClient(accessKey, "def", "myhuawei.com", "cn-north-1", projectID)
+		base.GetSigner().NextTransport = transport
+		return &Provider{Client: cce.NewClient(base), ClusterID: "cluster-1", Duration: duration, Endpoint: cce.KubeconfigEndpointExternal, CacheDir: dir}
+	}
+	root := context.Background()
+	for i, provider := range []*Provider{
+		newProvider("abcd", "test", 1),
+		newProvider("other", "test", 1),
+		newProvider("abcd", "other", 1),
+		newProvider("abcd", "test", 2),
+	} {
+		if _, err := provider.Credential(root, nil); err != nil {
+			t.Fatal(err)
+		}
+		if transport.requests != i+1 {
+			t.Fatalf("provider %d got the cached certificate of another identity", i)
+		}
+	}
+	if _, err := newProvider("abcd", "test", 1).Credential(root, nil); err != nil || transport.requests != 4 {
+		t.Fatalf("expected the cached certificate, got %d requests: %v", transport.requests, err)
+	}
+}
+
+func TestLoadBaseClient(t *testing.T) {
+	if os.Getenv("ACCESS_KEY") != "" {
+		t.Skip("credentials are set in the environment")
+	}
+	if _, err := LoadBaseClient(""); err == nil {
+		t.Fatal("expected error without credentials")
+	}
+	profile, err := ioutil.TempFile("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(profile.Name())
+	profile.WriteString("access_key: abcd\nsecret_key: def\nregion: cn-north-1\n")
+	profile.Close()
+	if _, err := LoadBaseClient(profile.Name()); err == nil {
+		t.Fatal("expected error without project id")
+	}
+	ioutil.WriteFile(profile.Name(), []byte("access_key: abcd\nsecret_key: def\nregion: cn-north-1\nproject_id: test\n"), 0600)
+	client, err := LoadBaseClient(profile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Region != "cn-north-1" || client.ProjectID != "test" {
+		t.Fatalf("unexpected client %#v", client)
+	}
+}
@@ -1,6 +1,7 @@
 package cce
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -11,30 +12,105 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// authentication of the kubernetes clients
+const (
+	// K8sAuthSigner signs the requests with the AK/SK of the cce client and
+	// sends them through the cce proxy at https://<cluster uid>.<cce host>
+	K8sAuthSigner = "signer"
+	// K8sAuthCertificate uses the client certificate of the cluster cert
+	// against the api server endpoint of the cluster
+	K8sAuthCertificate = "certificate"
+)
+
 type K8sClient struct {
 	cceClient    *Client
 	CoreV1Client *corev1.CoreV1Client
 	AppsV1Client *appsv1.AppsV1Client
+	// Dynamic serves all other api groups
+	Dynamic dynamic.ClientPool
+	// Config is the rest config the clients above are built from
+	Config *rest.Config
+}
+
+type K8sClientOptions struct {
+	// Auth is K8sAuthSigner or K8sAuthCertificate, it defaults to K8sAuthSigner
+	Auth string
+	// Endpoint and Duration select the cluster cert with K8sAuthCertificate,
+	// see KubeconfigOptions
+	Endpoint string
+	Duration int64
 }
 
 func GetClusterClient(cluster *common.ClusterInfo, cceClient *Client) (*K8sClient, error) {
-	if cluster == nil || cceClient == nil {
-		return nil, errors.New("cluster or cce client is nil")
+	return NewK8sClient(context.Background(), cluster, cceClient, nil)
+}
+
+// NewK8sClient returns the kubernetes clients of the cluster which
+// authenticate as configured by opts
+func NewK8sClient(ctx context.Context, cluster *common.ClusterInfo, cceClient *Client, opts *K8sClientOptions) (*K8sClient, error) {
+	conf, err := K8sRESTConfig(ctx, cluster, cceClient, opts)
+	if err != nil {
+		return nil, err
 	}
-	var err error
 	rtn := &K8sClient{
 		cceClient: cceClient,
+		Dynamic:   dynamic.NewDynamicClientPool(conf),
+		Config:    conf,
 	}
-	conf := rest.Config{
-		Host:          fmt.Sprintf("https://%s.%s", cluster.MetaData.UID, cceClient.GetAPIHostnameFunc()),
-		Transport:     cceClient.GetSigner(),
-		ContentConfig: dynamic.ContentConfig(),
-	}
-	if rtn.CoreV1Client, err = corev1.NewForConfig(&conf); err != nil {
+	if rtn.CoreV1Client, err = corev1.NewForConfig(conf); err != nil {
 		return nil, err
 	}
-	if rtn.AppsV1Client, err = appsv1.NewForConfig(&conf); err != nil {
+	if rtn.AppsV1Client, err = appsv1.NewForConfig(conf); err != nil {
 		return nil, err
 	}
 	return rtn, nil
 }
+
+// K8sRESTConfig returns the client-go config for the api server of the cluster
+func K8sRESTConfig(ctx context.Context, cluster *common.ClusterInfo, cceClient *Client, opts *K8sClientOptions) (*rest.Config, error) {
+	if cluster == nil || cceClient == nil {
+		return nil, errors.New("cluster or cce client is nil")
+	}
+	if opts == nil {
+		opts = &K8sClientOptions{}
+	}
+	switch opts.Auth {
+	case "", K8sAuthSigner:
+		return &rest.Config{
+			Host:          fmt.Sprintf("https://%s.%s", cluster.MetaData.UID, cceClient.GetAPIHostnameFunc()),
+			Transport:     cceClient.GetSigner(),
+			ContentConfig: dynamic.ContentConfig(),
+		}, nil
+	case K8sAuthCertificate:
+	default:
+		return nil, fmt.Errorf("unknown kubernetes client auth %q", opts.Auth)
+	}
+
+	kubeconfig, err := cceClient.GetKubeconfig(ctx, cluster.MetaData.UID, &KubeconfigOptions{
+		Endpoint: opts.Endpoint,
+		Duration: opts.Duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	config, err := KubeconfigToConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	current := config.Contexts[config.CurrentContext]
+	server, user := config.Clusters[current.Cluster], config.AuthInfos[current.AuthInfo]
+	conf := &rest.Config{
+		Host: server.Server,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: server.InsecureSkipTLSVerify,
+			CertData: user.ClientCertificateData,
+			KeyData:  user.ClientKeyData,
+		},
+		ContentConfig: dynamic.ContentConfig(),
+	}
+	// client-go refuses a root certificate together with the insecure flag
+	if !conf.Insecure {
+		conf.CAData = server.CertificateAuthorityData
+	}
+	return conf, nil
+}
@@ -2,10 +2,16 @@ package cce
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestCCEClient(t *testing.T) {
@@ -31,3 +37,81 @@ func TestCCEClient(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// apiServerTransport answers every kubernetes api request with an object named
+// by the last element of the path and records the hosts
+type apiServerTransport struct {
+	hosts []string
+}
+
+func (a *apiServerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	a.hosts = append(a.hosts, r.URL.Host)
+	parts := strings.Split(r.URL.Path, "/")
+	body := fmt.Sprintf(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":%q}}`, parts[len(parts)-1])
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func TestK8sClientSigner(t *testing.T) {
+	transport := &apiServerTransport{}
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = transport
+	cluster := &common.ClusterInfo{MetaData: common.MetaInfo{UID: "cluster-1"}}
+
+	k8sClient, err := NewK8sClient(context.Background(), cluster, NewClient(base), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := k8sClient.CoreV1Client.Namespaces().Get("kube-system", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns.Name != "kube-system" {
+		t.Fatalf("unexpected namespace %#v", ns)
+	}
+	gv := schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1"}
+	dynamicClient, err := k8sClient.Dynamic.ClientForGroupVersionKind(gv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := dynamicClient.Resource(&metav1.APIResource{Name: "networkpolicies", Namespaced: true}, "default").Get("deny-all", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetName() != "deny-all" {
+		t.Fatalf("unexpected object %#v", obj)
+	}
+	for _, host := range transport.hosts {
+		if host != "cluster-1.cce.cn-north-1.myhuawei.com" {
+			t.Fatalf("expected requests through the cce proxy, got %s", host)
+		}
+	}
+}
+
+func TestK8sRESTConfigCertificate(t *testing.T) {
+	c := newWaitClient(map[string][]interface{}{
+		"GET /api/v3/projects/test/clusters/cluster-1/clustercert": {json.RawMessage(clusterCertJSON)},
+	})
+	cluster := &common.ClusterInfo{MetaData: common.MetaInfo{UID: "cluster-1"}}
+
+	if _, err := K8sRESTConfig(context.Background(), cluster, c, &K8sClientOptions{Auth: "token"}); err == nil {
+		t.Fatal("expected error for an unknown auth")
+	}
+	conf, err := K8sRESTConfig(context.Background(), cluster, c, &K8sClientOptions{Auth: K8sAuthCertificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Host != "https://192.168.0.73:5443" || conf.Transport != nil || string(conf.CAData) != "ca" || string(conf.CertData) != "cert" || string(conf.KeyData) != "key" {
+		t.Fatalf("unexpected internal config %#v", conf)
+	}
+	conf, err = K8sRESTConfig(context.Background(), cluster, c, &K8sClientOptions{Auth: K8sAuthCertificate, Endpoint: KubeconfigEndpointExternal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Host != "https://100.64.1.1:5443" || !conf.Insecure {
+		t.Fatalf("unexpected external config %#v", conf)
+	}
+}
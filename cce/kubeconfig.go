@@ -0,0 +1,247 @@
+package cce
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/ghodss/yaml"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// endpoints of the cluster cert, each is the name of one of its contexts
+const (
+	KubeconfigEndpointInternal    = "internal"
+	KubeconfigEndpointExternal    = "external"
+	KubeconfigEndpointExternalOTC = "externalOTC"
+)
+
+type KubeconfigOptions struct {
+	// Endpoint defaults to KubeconfigEndpointInternal, the external endpoints
+	// need an EIP bound to the cluster, see BindMasterEIP
+	Endpoint string
+	// Duration is the validity of the client certificate in days, -1 is the
+	// maximum and 0 the default of the api
+	Duration int64
+	// ContextName names the context, cluster and user, it defaults to the cluster id
+	ContextName string
+}
+
+// GetKubeconfig returns a kubeconfig with the one endpoint of the cluster, see
+// KubeconfigToConfig and MarshalKubeconfig
+func (c *Client) GetKubeconfig(ctx context.Context, clusterID string, opts *KubeconfigOptions) (*common.ClusterCert, error) {
+	if opts == nil {
+		opts = &KubeconfigOptions{}
+	}
+	var cert *common.ClusterCert
+	var err error
+	if opts.Duration == 0 {
+		cert, err = c.GetClusterCert(ctx, clusterID)
+	} else {
+		cert, err = c.GetClusterCertWithDuration(ctx, clusterID, opts.Duration)
+	}
+	if err != nil {
+		return nil, err
+	}
+	name := opts.ContextName
+	if name == "" {
+		name = clusterID
+	}
+	return SelectKubeconfig(cert, opts.Endpoint, name)
+}
+
+// SelectKubeconfig returns a kubeconfig with the context of the endpoint and
+// its cluster and user only, all of them are renamed to name so that it can
+// be merged with the kubeconfigs of other clusters
+func SelectKubeconfig(cert *common.ClusterCert, endpoint, name string) (*common.ClusterCert, error) {
+	if endpoint == "" {
+		endpoint = KubeconfigEndpointInternal
+	}
+	if name == "" {
+		return nil, errors.New("kubeconfig context name is required")
+	}
+	var available []string
+	for _, c := range cert.Contexts {
+		available = append(available, c.Name)
+		if c.Name != endpoint {
+			continue
+		}
+		rtn := &common.ClusterCert{
+			Kind:           "Config",
+			APIVersion:     "v1",
+			Contexts:       []common.ContextConfig{{Name: name, Context: common.Context{Cluster: name, User: name}}},
+			CurrentContext: name,
+		}
+		for _, cluster := range cert.Clusters {
+			if cluster.Name == c.Context.Cluster {
+				rtn.Clusters = []common.ClusterConfig{{Name: name, Cluster: cluster.Cluster}}
+			}
+		}
+		for _, user := range cert.Users {
+			if user.Name == c.Context.User {
+				rtn.Users = []common.UserConfig{{Name: name, User: user.User}}
+			}
+		}
+		if rtn.Clusters == nil || rtn.Users == nil {
+			return nil, fmt.Errorf("cluster %q or user %q of endpoint %s not found in cluster cert", c.Context.Cluster, c.Context.User, endpoint)
+		}
+		return rtn, nil
+	}
+	return nil, fmt.Errorf("endpoint %s not found in cluster cert, available endpoints are %v", endpoint, available)
+}
+
+// KubeconfigToConfig converts the kubeconfig to the client-go config
+func KubeconfigToConfig(kubeconfig *common.ClusterCert) (*clientcmdapi.Config, error) {
+	config := clientcmdapi.NewConfig()
+	config.CurrentContext = kubeconfig.CurrentContext
+	for _, c := range kubeconfig.Clusters {
+		cluster := clientcmdapi.NewCluster()
+		cluster.Server = c.Cluster.Server
+		cluster.InsecureSkipTLSVerify = c.Cluster.InsecureSkipTLSVerify
+		ca, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding certificate authority of cluster %s: %v", c.Name, err)
+		}
+		if len(ca) > 0 {
+			cluster.CertificateAuthorityData = ca
+		}
+		config.Clusters[c.Name] = cluster
+	}
+	for _, u := range kubeconfig.Users {
+		user := clientcmdapi.NewAuthInfo()
+		cert, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding client certificate of user %s: %v", u.Name, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding client key of user %s: %v", u.Name, err)
+		}
+		user.ClientCertificateData, user.ClientKeyData = cert, key
+		config.AuthInfos[u.Name] = user
+	}
+	for _, c := range kubeconfig.Contexts {
+		ctx := clientcmdapi.NewContext()
+		ctx.Cluster, ctx.AuthInfo = c.Context.Cluster, c.Context.User
+		config.Contexts[c.Name] = ctx
+	}
+	return config, nil
+}
+
+// MarshalKubeconfig returns the kubeconfig as yaml which kubectl can read
+func MarshalKubeconfig(kubeconfig *common.ClusterCert) ([]byte, error) {
+	return yaml.Marshal(kubeconfig)
+}
+
+// MergeKubeconfig adds the clusters, users and contexts of the kubeconfig to
+// the kubeconfig file at path, entries with the same names are replaced and
+// all other content of the file is kept. The current context is switched to
+// the one of kubeconfig. The file is created when it does not exist,
+// otherwise it is copied to a backup file first, whose path is returned.
+func MergeKubeconfig(path string, kubeconfig *common.ClusterCert) (string, error) {
+	merged := map[string]interface{}{"apiVersion": "v1", "kind": "Config"}
+	backup := ""
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	default:
+		if err := yaml.Unmarshal(data, &merged); err != nil {
+			return "", fmt.Errorf("error parsing kubeconfig %s: %v", path, err)
+		}
+		if merged == nil {
+			merged = map[string]interface{}{"apiVersion": "v1", "kind": "Config"}
+		}
+		if backup, err = writeBackup(path, data); err != nil {
+			return "", err
+		}
+	}
+
+	b, err := json.Marshal(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+	added := map[string]interface{}{}
+	if err := json.Unmarshal(b, &added); err != nil {
+		return "", err
+	}
+	for _, key := range []string{"clusters", "users", "contexts"} {
+		merged[key] = mergeNamed(merged[key], added[key])
+	}
+	if kubeconfig.CurrentContext != "" {
+		merged["current-context"] = kubeconfig.CurrentContext
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".kubeconfig")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return "", err
+	}
+	return backup, os.Rename(tmp.Name(), path)
+}
+
+// writeBackup writes data to a new file next to path, the random part of its
+// name keeps earlier backups from being overwritten
+func writeBackup(path string, data []byte) (string, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.bak")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// mergeNamed replaces the entries of existing with the entries of added
+// which have the same name and appends the others
+func mergeNamed(existing, added interface{}) []interface{} {
+	rtn, _ := existing.([]interface{})
+	entries, _ := added.([]interface{})
+	for _, entry := range entries {
+		name := entryName(entry)
+		replaced := false
+		for i := range rtn {
+			if entryName(rtn[i]) == name {
+				rtn[i], replaced = entry, true
+			}
+		}
+		if !replaced {
+			rtn = append(rtn, entry)
+		}
+	}
+	return rtn
+}
+
+func entryName(entry interface{}) interface{} {
+	if m, ok := entry.(map[string]interface{}); ok {
+		return m["name"]
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/ghodss/yaml"
+)
+
+const clusterCertJSON = `{
+	"kind": "Config",
+	"apiVersion": "v1",
+	"clusters": [
+		{"name": "internalCluster", "cluster": {"server": "https://192.168.0.73:5443", "certificate-authority-data": "Y2E="}},
+		{"name": "externalCluster", "cluster": {"server": "https://100.64.1.1:5443", "insecure-skip-tls-verify": true}}
+	],
+	"users": [{"name": "user", "user": {"client-certificate-data": "Y2VydA==", "client-key-data": "a2V5"}}],
+	"contexts": [
+		{"name": "internal", "context": {"cluster": "internalCluster", "user": "user"}},
+		{"name": "external", "context": {"cluster": "externalCluster", "user": "user"}}
+	],
+	"current-context": "internal"
+}`
+
+func TestKubeconfig(t *testing.T) {
+	c := newWaitClient(map[string][]interface{}{
+		"POST /api/v3/projects/test/clusters/cluster-1/clustercert": {json.RawMessage(clusterCertJSON)},
+	})
+
+	if _, err := c.GetKubeconfig(context.Background(), "cluster-1", &KubeconfigOptions{Endpoint: KubeconfigEndpointExternalOTC, Duration: 30}); err == nil || !strings.Contains(err.Error(), "[internal external]") {
+		t.Fatalf("expected error listing the endpoints, got %v", err)
+	}
+	kubeconfig, err := c.GetKubeconfig(context.Background(), "cluster-1", &KubeconfigOptions{Endpoint: KubeconfigEndpointExternal, Duration: 30, ContextName: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeconfig.Clusters) != 1 || kubeconfig.Clusters[0].Cluster.Server != "https://100.64.1.1:5443" || kubeconfig.Contexts[0].Context.User != "prod" {
+		t.Fatalf("unexpected kubeconfig %#v", kubeconfig)
+	}
+
+	config, err := KubeconfigToConfig(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "prod" || !config.Clusters["prod"].InsecureSkipTLSVerify || string(config.AuthInfos["prod"].ClientKeyData) != "key" {
+		t.Fatalf("unexpected config %#v", config)
+	}
+
+	cert := &common.ClusterCert{}
+	if err := json.Unmarshal([]byte(clusterCertJSON), cert); err != nil {
+		t.Fatal(err)
+	}
+	internal, err := SelectKubeconfig(cert, "", "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = KubeconfigToConfig(internal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config.Clusters["cluster-1"].CertificateAuthorityData) != "ca" || config.Contexts["cluster-1"].Cluster != "cluster-1" {
+		t.Fatalf("unexpected internal config %#v", config)
+	}
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".kube", "config")
+
+	cert := &common.ClusterCert{}
+	if err := json.Unmarshal([]byte(clusterCertJSON), cert); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig, err := SelectKubeconfig(cert, KubeconfigEndpointInternal, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := MergeKubeconfig(path, kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup != "" {
+		t.Fatalf("expected no backup of a new file, got %s", backup)
+	}
+
+	existing := `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev:6443
+users:
+- name: dev
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1alpha1
+      command: cce-credential
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+    namespace: team
+- name: prod
+  context:
+    cluster: old
+    user: old
+current-context: dev
+`
+	if err := ioutil.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	backup, err = MergeKubeconfig(path, kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(backup); err != nil || string(b) != existing {
+		t.Fatalf("expected backup of the old file, got %v", err)
+	}
+	again, err := MergeKubeconfig(path, kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(backup); again == backup || err != nil || string(b) != existing {
+		t.Fatalf("backup %s is overwritten by %s: %v", backup, again, err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := common.ClusterCert{}
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.CurrentContext != "prod" || len(merged.Clusters) != 2 || len(merged.Users) != 2 || len(merged.Contexts) != 2 {
+		t.Fatalf("unexpected merged kubeconfig\n%s", data)
+	}
+	if merged.Contexts[1].Context.Cluster != "prod" {
+		t.Fatalf("expected context prod to be replaced\n%s", data)
+	}
+	if !strings.Contains(string(data), "command: cce-credential") || !strings.Contains(string(data), "namespace: team") {
+		t.Fatalf("expected the other entries to be kept\n%s", data)
+	}
+}
@@ -0,0 +1,81 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	MasterEIPActionBind   = "bind"
+	MasterEIPActionUnbind = "unbind"
+)
+
+func (c *Client) updateMasterEIP(ctx context.Context, clusterID string, spec common.BindInfoSpec) (*common.BindInfoStatus, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.CCEClusterIPBindInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("clusters", clusterID, "mastereip"),
+		&common.CCEClusterIPBindInfo{Spec: spec},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	if rtn.Status == nil {
+		return &common.BindInfoStatus{}, nil
+	}
+	return rtn.Status, nil
+}
+
+// BindMasterEIP binds the EIP to the api server of the cluster and returns
+// the endpoints, PublicEndpoint is the address of the EIP
+func (c *Client) BindMasterEIP(ctx context.Context, clusterID, eipID string) (*common.BindInfoStatus, error) {
+	if eipID == "" {
+		return nil, errors.New("eip id is required")
+	}
+	return c.updateMasterEIP(ctx, clusterID, common.BindInfoSpec{
+		Action:     MasterEIPActionBind,
+		ActionSpec: &common.BindActionSpec{ID: eipID},
+	})
+}
+
+// UnbindMasterEIP unbinds the EIP from the api server of the cluster, the EIP
+// is not released
+func (c *Client) UnbindMasterEIP(ctx context.Context, clusterID string) (*common.BindInfoStatus, error) {
+	return c.updateMasterEIP(ctx, clusterID, common.BindInfoSpec{Action: MasterEIPActionUnbind})
+}
+
+// BindNewMasterEIP allocates an EIP with networkClient and binds it to the
+// api server of the cluster once it is ready, the EIP is polled every during
+// until timeout. The EIP is released again when it cannot be bound.
+func (c *Client) BindNewMasterEIP(ctx context.Context, networkClient *network.Client, clusterID string, arg *common.EipAllocArg, during, timeout time.Duration) (*common.EipInfo, *common.BindInfoStatus, error) {
+	if clusterID == "" {
+		return nil, nil, errors.New("cluster id is required")
+	}
+	created, err := networkClient.CreateEIP(ctx, arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the new EIP cannot be bound while it is PENDING_CREATE
+	eip, err := networkClient.WaitForEIPReady(ctx, during, timeout, created.ID)
+	var status *common.BindInfoStatus
+	if err == nil {
+		status, err = c.BindMasterEIP(ctx, clusterID, eip.ID)
+	}
+	if err != nil {
+		if rerr := networkClient.DeleteEIP(ctx, created.ID); rerr != nil {
+			logrus.Errorf("error releasing eip %s after failing to bind it to cluster %s: %v", created.ID, clusterID, rerr)
+		}
+		return nil, nil, err
+	}
+	return eip, status, nil
+}
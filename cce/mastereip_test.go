@@ -0,0 +1,98 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+)
+
+// masterEIPServer serves the mastereip api of cluster-1 and sends the EIP
+// requests to the elbtest server
+type masterEIPServer struct {
+	*elbtest.Server
+	bound string
+	fail  bool
+}
+
+func (s *masterEIPServer) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Path != "/api/v3/projects/test/clusters/cluster-1/mastereip" {
+		return s.Server.RoundTrip(r)
+	}
+	req := common.CCEClusterIPBindInfo{}
+	json.NewDecoder(r.Body).Decode(&req)
+	if s.fail {
+		return cceResponse(400, cceError("CCE.01400001", "cluster is not available"))
+	}
+	status := &common.BindInfoStatus{PrivateEndpoint: "https://192.168.0.73:5443"}
+	switch req.Spec.Action {
+	case MasterEIPActionBind:
+		eip, ok := s.EIPs[req.Spec.ActionSpec.ID]
+		if !ok {
+			return cceResponse(404, cceError("CCE.01404001", "eip not found"))
+		}
+		if eip.Status != network.EIPStatusDown {
+			return cceResponse(400, cceError("CCE.01400013", "eip is "+eip.Status))
+		}
+		s.bound = eip.ID
+		status.PublicEndpoint = "https://" + eip.Addr + ":5443"
+	case MasterEIPActionUnbind:
+		s.bound = ""
+	}
+	return cceResponse(200, common.CCEClusterIPBindInfo{Spec: req.Spec, Status: status})
+}
+
+func TestMasterEIP(t *testing.T) {
+	server := &masterEIPServer{Server: elbtest.NewServer()}
+	server.AddEIP("master-eip", "100.64.1.1", "")
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = server
+	c := NewClient(base)
+	networkClient := network.NewClient(base)
+	root := context.Background()
+
+	if _, err := c.BindMasterEIP(root, "cluster-1", ""); err == nil {
+		t.Fatal("expected error without eip id")
+	}
+	status, err := c.BindMasterEIP(root, "cluster-1", "master-eip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PublicEndpoint != "https://100.64.1.1:5443" || server.bound != "master-eip" {
+		t.Fatalf("unexpected endpoints %#v", status)
+	}
+	status, err = c.UnbindMasterEIP(root, "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PublicEndpoint != "" || status.PrivateEndpoint == "" || server.bound != "" {
+		t.Fatalf("unexpected endpoints after unbind %#v", status)
+	}
+
+	arg := &common.EipAllocArg{
+		EipDesc:   common.PubIP{Type: "5_bgp"},
+		BandWidth: common.BandwidthDesc{Name: "cluster-1", Size: 5, ShrType: "PER", ChgMode: "traffic"},
+	}
+	// allocated eips cannot be bound for the first two queries
+	server.EIPPendingPolls = 2
+	server.fail = true
+	if _, _, err := c.BindNewMasterEIP(root, networkClient, "cluster-1", arg, time.Millisecond, time.Second); err == nil {
+		t.Fatal("expected bind error")
+	}
+	if len(server.EIPs) != 1 {
+		t.Fatalf("expected the allocated eip to be released, got %d eips", len(server.EIPs))
+	}
+	server.fail = false
+	eip, status, err := c.BindNewMasterEIP(root, networkClient, "cluster-1", arg, time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.bound != eip.ID || status.PublicEndpoint != "https://"+eip.Addr+":5443" {
+		t.Fatalf("unexpected endpoints %#v for eip %#v", status, eip)
+	}
+}
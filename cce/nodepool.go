@@ -0,0 +1,230 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const (
+	NodePoolTypeVM         = "vm"
+	NodePoolTypeElasticBMS = "ElasticBMS"
+
+	NodePoolPhaseSynchronizing = "Synchronizing"
+	NodePoolPhaseSynchronized  = "Synchronized"
+	NodePoolPhaseSoldOut       = "SoldOut"
+	NodePoolPhaseDeleting      = "Deleting"
+	NodePoolPhaseError         = "Error"
+)
+
+const (
+	// NodePoolPollInterval and DefaultNodePoolTimeout are used when
+	// NodePoolWaitOptions leaves them empty
+	NodePoolPollInterval   = 10 * time.Second
+	DefaultNodePoolTimeout = 30 * time.Minute
+)
+
+type NodePoolWaitOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+func validateNodePool(pool *common.NodePool) error {
+	if pool.MetaData.Name == "" {
+		return errors.New("node pool name is required")
+	}
+	if pool.Spec.NodeTemplate.Flavor == "" {
+		return errors.New("node pool flavor is required")
+	}
+	return validateAutoscaling(pool.Spec.Autoscaling, pool.Spec.InitialNodeCount)
+}
+
+func validateAutoscaling(autoscaling *common.NodePoolAutoscaling, count int64) error {
+	if autoscaling == nil || !autoscaling.Enable {
+		return nil
+	}
+	if autoscaling.MaxNodeCount < autoscaling.MinNodeCount {
+		return fmt.Errorf("max node count %d is less than min node count %d", autoscaling.MaxNodeCount, autoscaling.MinNodeCount)
+	}
+	if count < autoscaling.MinNodeCount || count > autoscaling.MaxNodeCount {
+		return fmt.Errorf("node count %d is out of the autoscaling range %d-%d", count, autoscaling.MinNodeCount, autoscaling.MaxNodeCount)
+	}
+	return nil
+}
+
+func (c *Client) CreateNodePool(ctx context.Context, clusterID string, pool *common.NodePool) (*common.NodePool, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	if err := validateNodePool(pool); err != nil {
+		return nil, err
+	}
+	request := *pool
+	if request.Kind == "" {
+		request.Kind = "NodePool"
+	}
+	if request.APIVersion == "" {
+		request.APIVersion = "v3"
+	}
+	rtn := common.NodePool{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterID, "nodepools"),
+		&request,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) GetNodePool(ctx context.Context, clusterID, id string) (*common.NodePool, error) {
+	if clusterID == "" || id == "" {
+		return nil, errors.New("node pool id and cluster id are required")
+	}
+	rtn := common.NodePool{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "nodepools", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) ListNodePools(ctx context.Context, clusterID string) (*common.NodePoolList, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.NodePoolList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "nodepools"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// UpdateNodePool changes the name, node count, autoscaling and the node
+// template of new nodes, existing nodes are not changed
+func (c *Client) UpdateNodePool(ctx context.Context, clusterID, id string, pool *common.NodePool) (*common.NodePool, error) {
+	if clusterID == "" || id == "" {
+		return nil, errors.New("node pool id and cluster id are required")
+	}
+	if err := validateAutoscaling(pool.Spec.Autoscaling, pool.Spec.InitialNodeCount); err != nil {
+		return nil, err
+	}
+	rtn := common.NodePool{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("clusters", clusterID, "nodepools", id),
+		pool,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// DeleteNodePool deletes the node pool with all of its nodes
+func (c *Client) DeleteNodePool(ctx context.Context, clusterID, id string) error {
+	if clusterID == "" || id == "" {
+		return errors.New("node pool id and cluster id are required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("clusters", clusterID, "nodepools", id),
+		nil,
+		nil,
+	)
+	return err
+}
+
+// WaitForNodePoolStatus polls the node pool until condition returns true or
+// an error, opts can be nil
+func (c *Client) WaitForNodePoolStatus(ctx context.Context, clusterID, id string, condition func(*common.NodePool) (bool, error), opts *NodePoolWaitOptions) (*common.NodePool, error) {
+	interval, timeout := NodePoolPollInterval, DefaultNodePoolTimeout
+	if opts != nil {
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+	}
+	var last *common.NodePool
+	err := common.CustomWaitForCompleteUntilTrue(ctx, interval, timeout, func(ictx context.Context) (bool, error) {
+		pool, err := c.GetNodePool(ictx, clusterID, id)
+		if err != nil {
+			return false, err
+		}
+		last = pool
+		return condition(pool)
+	})
+	return last, err
+}
+
+// WaitForNodePoolActive waits until the node pool has no nodes being created or deleted
+func (c *Client) WaitForNodePoolActive(ctx context.Context, clusterID, id string, opts *NodePoolWaitOptions) (*common.NodePool, error) {
+	return c.WaitForNodePoolStatus(ctx, clusterID, id, nodePoolSettled, opts)
+}
+
+func nodePoolSettled(pool *common.NodePool) (bool, error) {
+	status := pool.Status
+	if status == nil {
+		return false, nil
+	}
+	switch status.Phase {
+	case NodePoolPhaseError, NodePoolPhaseSoldOut:
+		return false, fmt.Errorf("node pool %s is %s with %d nodes", pool.MetaData.UID, status.Phase, status.CurrentNode)
+	case "", NodePoolPhaseSynchronized:
+		return status.CreatingNode == 0 && status.DeletingNode == 0, nil
+	}
+	return false, nil
+}
+
+// ScaleNodePool sets the node count of the pool and waits until the pool
+// has as many nodes. The count has to be in the autoscaling range when
+// autoscaling is enabled.
+func (c *Client) ScaleNodePool(ctx context.Context, clusterID, id string, count int64, opts *NodePoolWaitOptions) (*common.NodePool, error) {
+	if count < 0 {
+		return nil, errors.New("node count cannot be negative")
+	}
+	pool, err := c.GetNodePool(ctx, clusterID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAutoscaling(pool.Spec.Autoscaling, count); err != nil {
+		return nil, err
+	}
+	if pool.Spec.InitialNodeCount != count {
+		update := &common.NodePool{
+			MetaData: common.NodePoolMetaInfo{Name: pool.MetaData.Name},
+			Spec:     pool.Spec,
+		}
+		update.Spec.InitialNodeCount = count
+		if _, err := c.UpdateNodePool(ctx, clusterID, id, update); err != nil {
+			return nil, err
+		}
+	}
+	return c.WaitForNodePoolStatus(ctx, clusterID, id, func(pool *common.NodePool) (bool, error) {
+		if ok, err := nodePoolSettled(pool); !ok || err != nil {
+			return ok, err
+		}
+		return pool.Status.CurrentNode == count, nil
+	}, opts)
+}
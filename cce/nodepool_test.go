@@ -0,0 +1,172 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// nodePoolServer moves the current node count of its pools one node towards
+// the initial node count on every GET
+type nodePoolServer struct {
+	sync.Mutex
+	pools map[string]*common.NodePool
+	puts  int
+}
+
+func (s *nodePoolServer) serve(r *http.Request) (int, interface{}) {
+	s.Lock()
+	defer s.Unlock()
+	prefix := "/api/v3/projects/test/clusters/cluster-1/nodepools"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		return 404, cceNotFound
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if id == "" {
+		switch r.Method {
+		case http.MethodPost:
+			pool := &common.NodePool{}
+			json.NewDecoder(r.Body).Decode(pool)
+			pool.MetaData.UID = "pool-1"
+			pool.Status = &common.NodePoolStatus{}
+			s.pools[pool.MetaData.UID] = pool
+			return 201, pool
+		case http.MethodGet:
+			list := common.NodePoolList{}
+			for _, pool := range s.pools {
+				list.Items = append(list.Items, *pool)
+			}
+			return 200, list
+		}
+	}
+	pool, ok := s.pools[id]
+	if !ok {
+		return 404, cceNotFound
+	}
+	switch r.Method {
+	case http.MethodGet:
+		status := pool.Status
+		status.CreatingNode, status.DeletingNode = 0, 0
+		switch {
+		case status.CurrentNode < pool.Spec.InitialNodeCount:
+			status.CurrentNode++
+		case status.CurrentNode > pool.Spec.InitialNodeCount:
+			status.CurrentNode--
+		}
+		if diff := pool.Spec.InitialNodeCount - status.CurrentNode; diff > 0 {
+			status.CreatingNode = diff
+		} else if diff < 0 {
+			status.DeletingNode = -diff
+		}
+		return 200, pool
+	case http.MethodPut:
+		s.puts++
+		update := &common.NodePool{}
+		json.NewDecoder(r.Body).Decode(update)
+		pool.Spec = update.Spec
+		return 200, pool
+	case http.MethodDelete:
+		delete(s.pools, id)
+		return 200, pool
+	}
+	return 405, cceError("CCE.01405001", r.Method)
+}
+
+func TestNodePool(t *testing.T) {
+	server := &nodePoolServer{pools: map[string]*common.NodePool{}}
+	c := newTestClient(cceHandler(server.serve))
+	root := context.Background()
+	wait := &NodePoolWaitOptions{Interval: time.Millisecond}
+
+	pool := &common.NodePool{
+		MetaData: common.NodePoolMetaInfo{Name: "workers"},
+		Spec: common.NodePoolSpec{
+			Type: NodePoolTypeVM,
+			NodeTemplate: common.NodeSpecInfo{
+				Flavor:        "s3.large.2",
+				AvailableZone: "cn-north-1a",
+				RootVolume:    common.NodeVolume{Size: 40, VolumeType: "SATA"},
+				DataVolumes:   []common.NodeVolume{{Size: 100, VolumeType: "SATA"}},
+				Taints:        []common.Taint{{Key: "dedicated", Value: "batch", Effect: "NoSchedule"}},
+				K8sTags:       map[string]string{"pool": "workers"},
+			},
+			InitialNodeCount: 2,
+			Autoscaling:      &common.NodePoolAutoscaling{Enable: true, MinNodeCount: 1, MaxNodeCount: 3, Priority: 1},
+		},
+	}
+	if _, err := c.CreateNodePool(root, "cluster-1", &common.NodePool{}); err == nil {
+		t.Fatal("expected error without name")
+	}
+	pool.Spec.InitialNodeCount = 5
+	if _, err := c.CreateNodePool(root, "cluster-1", pool); err == nil {
+		t.Fatal("expected error for a node count out of the autoscaling range")
+	}
+	pool.Spec.InitialNodeCount = 2
+	created, err := c.CreateNodePool(root, "cluster-1", pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Kind != "NodePool" || created.Spec.NodeTemplate.Taints[0].Effect != "NoSchedule" {
+		t.Fatalf("unexpected node pool %#v", created)
+	}
+
+	active, err := c.WaitForNodePoolActive(root, "cluster-1", created.MetaData.UID, wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active.Status.CurrentNode != 2 {
+		t.Fatalf("expected 2 nodes, got %d", active.Status.CurrentNode)
+	}
+
+	if _, err := c.ScaleNodePool(root, "cluster-1", created.MetaData.UID, 4, wait); err == nil {
+		t.Fatal("expected error scaling above the autoscaling max")
+	}
+	scaled, err := c.ScaleNodePool(root, "cluster-1", created.MetaData.UID, 3, wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaled.Status.CurrentNode != 3 || server.puts != 1 {
+		t.Fatalf("expected 3 nodes after one update, got %d nodes and %d updates", scaled.Status.CurrentNode, server.puts)
+	}
+
+	list, err := c.ListNodePools(root, "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Spec.NodeTemplate.K8sTags["pool"] != "workers" {
+		t.Fatalf("unexpected node pools %#v", list.Items)
+	}
+	if err := c.DeleteNodePool(root, "cluster-1", created.MetaData.UID); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.pools) != 0 {
+		t.Fatal("expected node pool to be deleted")
+	}
+}
+
+func TestNodePoolSettled(t *testing.T) {
+	for _, tc := range []struct {
+		status *common.NodePoolStatus
+		ok     bool
+		err    bool
+	}{
+		{nil, false, false},
+		{&common.NodePoolStatus{CurrentNode: 2}, true, false},
+		{&common.NodePoolStatus{CurrentNode: 1, CreatingNode: 1}, false, false},
+		{&common.NodePoolStatus{Phase: NodePoolPhaseSynchronizing}, false, false},
+		{&common.NodePoolStatus{Phase: NodePoolPhaseSynchronized}, true, false},
+		{&common.NodePoolStatus{Phase: NodePoolPhaseSoldOut}, false, true},
+		{&common.NodePoolStatus{Phase: NodePoolPhaseError}, false, true},
+	} {
+		ok, err := nodePoolSettled(&common.NodePool{Status: tc.status})
+		if ok != tc.ok || (err != nil) != tc.err {
+			t.Errorf("status %+v: expected %v, %v, got %v, %v", tc.status, tc.ok, tc.err, ok, err)
+		}
+	}
+}
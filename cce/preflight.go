@@ -0,0 +1,119 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/ecs"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+)
+
+// cce creates a security group for the masters and one for the nodes of every cluster
+const securityGroupsPerCluster = 2
+
+// QuotaShortage describes a quota which is not enough for the planned resources
+type QuotaShortage struct {
+	Service   string
+	Resource  string
+	Required  int64
+	Available int64
+}
+
+func (s QuotaShortage) String() string {
+	return fmt.Sprintf("%s %s: required %d, available %d", s.Service, s.Resource, s.Required, s.Available)
+}
+
+// CapacityReport is returned by CheckClusterCapacity
+type CapacityReport struct {
+	Shortages []QuotaShortage
+}
+
+func (r *CapacityReport) OK() bool {
+	return len(r.Shortages) == 0
+}
+
+func (r *CapacityReport) Error() string {
+	var msgs []string
+	for _, s := range r.Shortages {
+		msgs = append(msgs, s.String())
+	}
+	return "quota exceeded: " + strings.Join(msgs, "; ")
+}
+
+func (r *CapacityReport) check(service, resource string, required, available int64) {
+	if required <= 0 || available < 0 || required <= available {
+		return
+	}
+	r.Shortages = append(r.Shortages, QuotaShortage{
+		Service:   service,
+		Resource:  resource,
+		Required:  required,
+		Available: available,
+	})
+}
+
+// CheckClusterCapacity reports the quotas which would be exceeded by creating
+// the cluster with node.NodeCount nodes. The nodes are checked against the ECS
+// instance, vCPU and RAM quotas, the vCPUs and RAM are taken from
+// node.NodeFlavor. A vpc and a subnet are counted when the host network of
+// the cluster is not set, and eips are counted when the nodes get new public
+// ips. The node limit of the cluster flavor is an approximation, see
+// MaxNodesForFlavor.
+func (c *Client) CheckClusterCapacity(ctx context.Context, networkClient *network.Client, ecsClient *ecs.Client, cluster *common.ClusterInfo, node *common.NodeConfig) (*CapacityReport, error) {
+	if cluster == nil || node == nil || networkClient == nil || ecsClient == nil {
+		return nil, errors.New("cluster, node config, network client and ecs client are required")
+	}
+	report := &CapacityReport{}
+
+	cceQuotas, err := c.GetQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if quota, ok := cceQuotas.Get(QuotaCluster); ok {
+		report.check("cce", QuotaCluster, 1, quota.Available())
+	}
+	if limit, ok := MaxNodesForFlavor(cluster.Spec.Flavor); ok {
+		report.check("cce", "node", node.NodeCount, limit)
+	}
+
+	limits, err := ecsClient.GetLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instances, cores, ram := limits.Available()
+	report.check("ecs", "instances", node.NodeCount, instances)
+	if node.NodeFlavor != "" {
+		flavor, err := ecsClient.GetFlavor(ctx, node.NodeFlavor)
+		if err != nil {
+			return nil, err
+		}
+		report.check("ecs", "cores", node.NodeCount*flavor.VCPUs, cores)
+		report.check("ecs", "ram", node.NodeCount*flavor.RAM, ram)
+	}
+
+	vpcQuotas, err := networkClient.GetQuotas(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	required := map[string]int64{
+		network.QuotaSecurityGroup: securityGroupsPerCluster,
+	}
+	if cluster.Spec.HostNetwork == nil || cluster.Spec.HostNetwork.Vpc == "" {
+		required[network.QuotaVPC] = 1
+	}
+	if cluster.Spec.HostNetwork == nil || cluster.Spec.HostNetwork.Subnet == "" {
+		required[network.QuotaSubnet] = 1
+	}
+	if node.PublicIP.Eip != nil {
+		required[network.QuotaPublicIP] = node.NodeCount
+	}
+	for _, resource := range []string{network.QuotaVPC, network.QuotaSubnet, network.QuotaSecurityGroup, network.QuotaPublicIP} {
+		if quota, ok := vpcQuotas.Quotas.Get(resource); ok {
+			report.check("vpc", resource, required[resource], quota.Available())
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,67 @@
+package cce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/ecs"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+)
+
+type quotaTransport map[string]interface{}
+
+func (q quotaTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if body, ok := q[r.URL.Path]; ok {
+		b, _ := json.Marshal(body)
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+	}
+	return &http.Response{StatusCode: 404, Body: ioutil.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func TestCheckClusterCapacity(t *testing.T) {
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = quotaTransport{
+		"/api/v3/projects/test/quotas": common.CCEQuotaList{Quotas: []common.CCEQuota{
+			{QuotaKey: QuotaCluster, QuotaLimit: 5, Used: 5},
+		}},
+		"/v2.1/test/limits": map[string]interface{}{"limits": map[string]interface{}{"absolute": common.ECSAbsoluteLimits{
+			MaxTotalInstances: 100, TotalInstancesUsed: 10,
+			MaxTotalCores: 200, TotalCoresUsed: 100,
+			MaxTotalRAMSize: -1, TotalRAMUsed: 409600,
+		}}},
+		"/v2.1/test/flavors/s3.large.2": common.ECSFlavorDetails{Flavor: common.ECSFlavor{ID: "s3.large.2", VCPUs: 2, RAM: 4096}},
+		"/v1/test/quotas": common.VPCQuotaList{Quotas: &common.QuotaResources{Resources: []common.QuotaResource{
+			{Type: network.QuotaVPC, Quota: 5, Used: 1},
+			{Type: network.QuotaSubnet, Quota: 100, Used: 10},
+			{Type: network.QuotaSecurityGroup, Quota: -1, Used: 10},
+			{Type: network.QuotaPublicIP, Quota: 10, Used: 8},
+		}}},
+	}
+	cceClient := NewClient(base)
+	networkClient := network.NewClient(base)
+	report, err := cceClient.CheckClusterCapacity(context.Background(), networkClient, ecs.NewClient(base), &common.ClusterInfo{
+		Spec: common.SpecInfo{Flavor: "cce.s1.small"},
+	}, &common.NodeConfig{
+		NodeFlavor: "s3.large.2",
+		NodeCount:  60,
+		PublicIP:   common.PublicIP{Eip: &common.Eip{Iptype: "5_bgp"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]bool{"cluster": true, "node": true, "cores": true, "publicIp": true}
+	if len(report.Shortages) != len(expected) {
+		t.Fatal("unexpected shortages", report.Error())
+	}
+	for _, s := range report.Shortages {
+		if !expected[s.Resource] {
+			t.Fatal("unexpected shortage", s)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cce
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// quota keys of cce
+const (
+	QuotaCluster = "cluster"
+)
+
+// flavorNodeLimits is the maximum number of nodes of a cluster by flavor
+// size, e.g. cce.s1.small or cce.s2.large. The cce api does not return the
+// limit, these are the sizes documented for the cluster flavors and only an
+// approximation: the ECS quotas checked by CheckClusterCapacity are what
+// actually blocks node creation.
+var flavorNodeLimits = map[string]int64{
+	"small":  50,
+	"medium": 200,
+	"large":  1000,
+	"xlarge": 2000,
+}
+
+func (c *Client) GetQuotas(ctx context.Context) (*common.CCEQuotaList, error) {
+	rtn := common.CCEQuotaList{}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("quotas"),
+		nil,
+		&rtn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// MaxNodesForFlavor returns the documented node limit of a cluster flavor, see flavorNodeLimits
+func MaxNodesForFlavor(flavor string) (int64, bool) {
+	parts := strings.Split(flavor, ".")
+	limit, ok := flavorNodeLimits[parts[len(parts)-1]]
+	return limit, ok
+}
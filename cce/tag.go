@@ -0,0 +1,86 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+type clusterTagsRequest struct {
+	Tags []common.Tag `json:"tags"`
+}
+
+func (c *Client) CreateTag(ctx context.Context, clusterID string, tag common.Tag) error {
+	if tag.Key == "" {
+		return errors.New("tag key is required")
+	}
+	return c.BatchUpdateTags(ctx, clusterID, common.TagActionCreate, []common.Tag{tag})
+}
+
+func (c *Client) DeleteTag(ctx context.Context, clusterID, key string) error {
+	if key == "" {
+		return errors.New("tag key is required")
+	}
+	return c.BatchUpdateTags(ctx, clusterID, common.TagActionDelete, []common.Tag{{Key: key}})
+}
+
+func (c *Client) BatchUpdateTags(ctx context.Context, clusterID, action string, tags []common.Tag) error {
+	if clusterID == "" {
+		return errors.New("cluster id is required")
+	}
+	if action != common.TagActionCreate && action != common.TagActionDelete {
+		return errors.New("tag action should be create or delete")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterID, "tags", action),
+		&clusterTagsRequest{Tags: tags},
+		nil,
+	)
+	return err
+}
+
+func (c *Client) GetTags(ctx context.Context, clusterID string) ([]common.Tag, error) {
+	cluster, err := c.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Spec.ClusterTags, nil
+}
+
+// ListResourcesByTags filters clusters on the client side because cce has no
+// resource_instances endpoint
+func (c *Client) ListResourcesByTags(ctx context.Context, filter *common.ResourceTagFilter) (*common.ResourceInstanceList, error) {
+	if filter == nil {
+		filter = &common.ResourceTagFilter{}
+	}
+	list, err := c.GetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rtn := common.ResourceInstanceList{}
+	for _, cluster := range list.Items {
+		if !filter.Match(cluster.MetaData.Name, cluster.Spec.ClusterTags) {
+			continue
+		}
+		rtn.TotalCount++
+		if filter.Action == common.TagActionCount {
+			continue
+		}
+		if rtn.TotalCount <= filter.Offset {
+			continue
+		}
+		if filter.Limit > 0 && int64(len(rtn.Resources)) >= filter.Limit {
+			continue
+		}
+		rtn.Resources = append(rtn.Resources, common.ResourceInstance{
+			ResourceID:   cluster.MetaData.UID,
+			ResourceName: cluster.MetaData.Name,
+			Tags:         cluster.Spec.ClusterTags,
+		})
+	}
+	return &rtn, nil
+}
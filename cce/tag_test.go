@@ -0,0 +1,85 @@
+package cce
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestTags(t *testing.T) {
+	var requests []string
+	cluster := func(id, name string, tags ...common.Tag) common.ClusterInfo {
+		return common.ClusterInfo{MetaData: common.MetaInfo{UID: id, Name: name}, Spec: common.SpecInfo{ClusterTags: tags}}
+	}
+	c := newTestClient(cceHandler(func(r *http.Request) (int, interface{}) {
+		request := r.Method + " " + r.URL.Path
+		if r.Body != nil {
+			b, _ := ioutil.ReadAll(r.Body)
+			request += " " + string(b)
+		}
+		requests = append(requests, request)
+		switch r.URL.Path {
+		case "/api/v3/projects/test/clusters":
+			return 200, common.ClusterListInfo{Items: []common.ClusterInfo{
+				cluster("cluster-1", "ci-1", common.Tag{Key: "owner", Value: "ci"}),
+				cluster("cluster-2", "ops-1", common.Tag{Key: "owner", Value: "ops"}),
+				cluster("cluster-3", "ci-2", common.Tag{Key: "owner", Value: "ci"}),
+			}}
+		case "/api/v3/projects/test/clusters/cluster-1":
+			return 200, cluster("cluster-1", "ci-1", common.Tag{Key: "owner", Value: "ci"})
+		}
+		return 200, nil
+	}))
+	ctx := context.Background()
+
+	if err := c.CreateTag(ctx, "cluster-1", common.Tag{}); err == nil {
+		t.Fatal("expected error without tag key")
+	}
+	if err := c.BatchUpdateTags(ctx, "cluster-1", common.TagActionFilter, nil); err == nil {
+		t.Fatal("expected error for an unknown batch action")
+	}
+	if err := c.CreateTag(ctx, "cluster-1", common.Tag{Key: "owner", Value: "ci"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteTag(ctx, "cluster-1", "env"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`POST /api/v3/projects/test/clusters/cluster-1/tags/create {"tags":[{"key":"owner","value":"ci"}]}`,
+		`POST /api/v3/projects/test/clusters/cluster-1/tags/delete {"tags":[{"key":"env","value":""}]}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+
+	tags, err := c.GetTags(ctx, "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Value != "ci" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	// clusters are filtered on the client side
+	list, err := c.ListResourcesByTags(ctx, &common.ResourceTagFilter{
+		Tags:   []common.TagFilter{{Key: "owner", Values: []string{"ci"}}},
+		Offset: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 2 || len(list.Resources) != 1 || list.Resources[0].ResourceID != "cluster-3" {
+		t.Fatalf("unexpected resources %#v", list)
+	}
+	list, err = c.ListResourcesByTags(ctx, &common.ResourceTagFilter{Action: common.TagActionCount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 3 || len(list.Resources) != 0 {
+		t.Fatalf("unexpected count %#v", list)
+	}
+}
@@ -0,0 +1,309 @@
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const (
+	UpgradeStrategyInPlace = "inPlaceRollingUpdate"
+	UpgradeStrategyRolling = "rollingUpdate"
+
+	UpgradePhaseFailed = "Failed"
+)
+
+const (
+	// UpgradePollInterval and DefaultUpgradeTimeout are used when
+	// UpgradeWaitOptions leaves them empty
+	UpgradePollInterval   = 30 * time.Second
+	DefaultUpgradeTimeout = 2 * time.Hour
+)
+
+// NewUpgradeAction returns the action to upgrade to targetVersion, batchSize
+// nodes are upgraded at the same time when it is not 0
+func NewUpgradeAction(targetVersion, strategy string, batchSize int64) common.ClusterUpgradeAction {
+	action := common.ClusterUpgradeAction{
+		TargetVersion: targetVersion,
+		Strategy:      &common.UpgradeStrategy{Type: strategy},
+	}
+	batch := &common.RollingUpdateStrategy{UserDefinedStep: batchSize}
+	switch strategy {
+	case UpgradeStrategyInPlace:
+		action.Strategy.InPlaceRollingUpdate = batch
+	case UpgradeStrategyRolling:
+		action.Strategy.RollingUpdate = batch
+	}
+	return action
+}
+
+func validateUpgradeAction(action *common.ClusterUpgradeAction) error {
+	if action.TargetVersion == "" {
+		return errors.New("target version is required")
+	}
+	if action.Strategy == nil {
+		return nil
+	}
+	switch action.Strategy.Type {
+	case UpgradeStrategyInPlace, UpgradeStrategyRolling:
+	default:
+		return fmt.Errorf("unknown upgrade strategy %q", action.Strategy.Type)
+	}
+	for _, batch := range []*common.RollingUpdateStrategy{action.Strategy.InPlaceRollingUpdate, action.Strategy.RollingUpdate} {
+		if batch != nil && batch.UserDefinedStep < 0 {
+			return fmt.Errorf("node batch size %d cannot be negative", batch.UserDefinedStep)
+		}
+	}
+	return nil
+}
+
+func (c *Client) GetUpgradeInfo(ctx context.Context, clusterID string) (*common.UpgradeInfo, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.UpgradeInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "upgradeinfo"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// ListUpgradeTargets returns the versions the cluster can be upgraded to
+func (c *Client) ListUpgradeTargets(ctx context.Context, clusterID string) ([]string, error) {
+	info, err := c.GetUpgradeInfo(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return info.Spec.VersionInfo.TargetVersions, nil
+}
+
+// PrecheckUpgrade starts the checks of the cluster, its nodes and add-ons
+// for the upgrade, see WaitForPrecheckTask
+func (c *Client) PrecheckUpgrade(ctx context.Context, clusterID string, action common.ClusterUpgradeAction) (*common.PrecheckTask, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	if err := validateUpgradeAction(&action); err != nil {
+		return nil, err
+	}
+	rtn := common.PrecheckTask{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterID, "operation", "precheck"),
+		&common.UpgradeTaskRequest{
+			Kind:       "PreCheckTask",
+			APIVersion: "v3",
+			Spec:       common.UpgradeTaskRequestSpec{ClusterUpgradeAction: action},
+		},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) GetPrecheckTask(ctx context.Context, clusterID, taskID string) (*common.PrecheckTask, error) {
+	if clusterID == "" || taskID == "" {
+		return nil, errors.New("task id and cluster id are required")
+	}
+	rtn := common.PrecheckTask{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "operation", "precheck", "tasks", taskID),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// WaitForPrecheckTask waits until the pre-check is finished, the failed
+// checks are listed in the error when it did not pass. Only Interval and
+// Timeout of opts are used, opts can be nil.
+func (c *Client) WaitForPrecheckTask(ctx context.Context, clusterID, taskID string, opts *UpgradeWaitOptions) (*common.PrecheckTask, error) {
+	interval, timeout := opts.durations()
+	var last *common.PrecheckTask
+	err := common.CustomWaitForCompleteUntilTrue(ctx, interval, timeout, func(ictx context.Context) (bool, error) {
+		task, err := c.GetPrecheckTask(ictx, clusterID, taskID)
+		if err != nil {
+			return false, err
+		}
+		last = task
+		done, err := common.JobPhaseDone(task.Status.Phase)
+		if err != nil {
+			var failed []string
+			for _, result := range task.Status.Results {
+				if strings.EqualFold(result.Status, UpgradePhaseFailed) {
+					failed = append(failed, fmt.Sprintf("%s %s: %s", result.Kind, result.Name, result.Message))
+				}
+			}
+			return false, fmt.Errorf("pre-check %s of cluster %s failed: %s [%s]", taskID, clusterID, task.Status.Message, strings.Join(failed, "; "))
+		}
+		return done, nil
+	})
+	return last, err
+}
+
+// UpgradeCluster starts the upgrade, see WaitForUpgradeTask
+func (c *Client) UpgradeCluster(ctx context.Context, clusterID string, action common.ClusterUpgradeAction) (*common.UpgradeTask, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	if err := validateUpgradeAction(&action); err != nil {
+		return nil, err
+	}
+	rtn := common.UpgradeTask{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterID, "operation", "upgrade"),
+		&common.UpgradeTaskRequest{
+			Kind:       "UpgradeTask",
+			APIVersion: "v3",
+			Spec:       common.UpgradeTaskRequestSpec{ClusterUpgradeAction: action},
+		},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) GetUpgradeTask(ctx context.Context, clusterID, taskID string) (*common.UpgradeTask, error) {
+	if clusterID == "" || taskID == "" {
+		return nil, errors.New("task id and cluster id are required")
+	}
+	rtn := common.UpgradeTask{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "operation", "upgrade", "tasks", taskID),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) ListUpgradeTasks(ctx context.Context, clusterID string) (*common.UpgradeTaskList, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.UpgradeTaskList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("clusters", clusterID, "operation", "upgrade", "tasks"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// PauseUpgrade stops the running upgrade after the current node batch
+func (c *Client) PauseUpgrade(ctx context.Context, clusterID string) (*common.UpgradeTask, error) {
+	return c.upgradeOperation(ctx, clusterID, "pause")
+}
+
+// ResumeUpgrade continues a paused upgrade
+func (c *Client) ResumeUpgrade(ctx context.Context, clusterID string) (*common.UpgradeTask, error) {
+	return c.upgradeOperation(ctx, clusterID, "continue")
+}
+
+// RetryUpgrade restarts the failed steps of the upgrade
+func (c *Client) RetryUpgrade(ctx context.Context, clusterID string) (*common.UpgradeTask, error) {
+	return c.upgradeOperation(ctx, clusterID, "retry")
+}
+
+func (c *Client) upgradeOperation(ctx context.Context, clusterID, operation string) (*common.UpgradeTask, error) {
+	if clusterID == "" {
+		return nil, errors.New("cluster id is required")
+	}
+	rtn := common.UpgradeTask{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("clusters", clusterID, "operation", "upgrade", operation),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// UpgradeWaitOptions are the options of WaitForUpgradeTask
+type UpgradeWaitOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	// OnProgress is called with the task on every poll when it is set
+	OnProgress func(*common.UpgradeTask)
+	// WaitWhilePaused waits for a paused upgrade until it is resumed, by
+	// default common.ErrJobPaused is returned with the paused task
+	WaitWhilePaused bool
+}
+
+func (o *UpgradeWaitOptions) durations() (time.Duration, time.Duration) {
+	interval, timeout := UpgradePollInterval, DefaultUpgradeTimeout
+	if o != nil && o.Interval > 0 {
+		interval = o.Interval
+	}
+	if o != nil && o.Timeout > 0 {
+		timeout = o.Timeout
+	}
+	return interval, timeout
+}
+
+// WaitForUpgradeTask waits until the upgrade succeeded, opts can be nil
+func (c *Client) WaitForUpgradeTask(ctx context.Context, clusterID, taskID string, opts *UpgradeWaitOptions) (*common.UpgradeTask, error) {
+	if opts == nil {
+		opts = &UpgradeWaitOptions{}
+	}
+	interval, timeout := opts.durations()
+	var last *common.UpgradeTask
+	err := common.CustomWaitForCompleteUntilTrue(ctx, interval, timeout, func(ictx context.Context) (bool, error) {
+		task, err := c.GetUpgradeTask(ictx, clusterID, taskID)
+		if err != nil {
+			return false, err
+		}
+		last = task
+		if opts.OnProgress != nil {
+			opts.OnProgress(task)
+		}
+		done, err := common.JobPhaseDone(task.Status.Phase)
+		if err == common.ErrJobPaused {
+			if opts.WaitWhilePaused {
+				return false, nil
+			}
+			return false, err
+		}
+		if err != nil {
+			var failed []string
+			for _, node := range task.Status.Nodes {
+				if strings.EqualFold(node.Phase, UpgradePhaseFailed) {
+					failed = append(failed, fmt.Sprintf("node %s: %s", node.NodeID, node.Message))
+				}
+			}
+			return false, fmt.Errorf("upgrade %s of cluster %s failed: %s [%s]", taskID, clusterID, task.Status.Message, strings.Join(failed, "; "))
+		}
+		return done, nil
+	})
+	return last, err
+}
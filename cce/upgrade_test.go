@@ -0,0 +1,115 @@
+package cce
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func upgradeTaskInPhase(phase string, nodes ...common.UpgradeNodeProgress) common.UpgradeTask {
+	return common.UpgradeTask{
+		MetaData: common.UpgradeTaskMetaData{UID: "task-1"},
+		Status:   common.UpgradeTaskStatus{Phase: phase, Nodes: nodes},
+	}
+}
+
+func TestUpgradeCluster(t *testing.T) {
+	info := common.UpgradeInfo{Spec: common.UpgradeInfoSpec{VersionInfo: common.UpgradeVersionInfo{
+		Release:        "v1.19",
+		TargetVersions: []string{"v1.21", "v1.23"},
+	}}}
+	c := newWaitClient(map[string][]interface{}{
+		"GET /api/v3/projects/test/clusters/cluster-1/upgradeinfo":        {info},
+		"POST /api/v3/projects/test/clusters/cluster-1/operation/upgrade": {upgradeTaskInPhase("Init")},
+		"GET /api/v3/projects/test/clusters/cluster-1/operation/upgrade/tasks/task-1": {
+			upgradeTaskInPhase("Running", common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Running"}),
+			upgradeTaskInPhase("Pause", common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Success"}),
+			upgradeTaskInPhase("Running", common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Running"}),
+			upgradeTaskInPhase("Pause", common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Success"}),
+			upgradeTaskInPhase("Success", common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Success"}),
+		},
+		"POST /api/v3/projects/test/clusters/cluster-1/operation/upgrade/pause": {upgradeTaskInPhase("Pause")},
+	})
+	root := context.Background()
+
+	targets, err := c.ListUpgradeTargets(root, "cluster-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[1] != "v1.23" {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+
+	if _, err := c.UpgradeCluster(root, "cluster-1", common.ClusterUpgradeAction{}); err == nil {
+		t.Fatal("expected error without target version")
+	}
+	if _, err := c.UpgradeCluster(root, "cluster-1", NewUpgradeAction("v1.21", "blueGreen", 1)); err == nil {
+		t.Fatal("expected error for an unknown strategy")
+	}
+	if _, err := c.UpgradeCluster(root, "cluster-1", NewUpgradeAction("v1.21", UpgradeStrategyRolling, -1)); err == nil {
+		t.Fatal("expected error for a negative batch size")
+	}
+	task, err := c.UpgradeCluster(root, "cluster-1", NewUpgradeAction("v1.21", UpgradeStrategyInPlace, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.PauseUpgrade(root, "cluster-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var phases []string
+	opts := &UpgradeWaitOptions{Interval: time.Millisecond, OnProgress: func(task *common.UpgradeTask) {
+		phases = append(phases, task.Status.Phase)
+	}}
+	paused, err := c.WaitForUpgradeTask(root, "cluster-1", task.MetaData.UID, opts)
+	if err != common.ErrJobPaused || paused.Status.Phase != "Pause" {
+		t.Fatalf("expected the wait to stop at the paused task, got %v", err)
+	}
+	phases = nil
+	opts.WaitWhilePaused = true
+	done, err := c.WaitForUpgradeTask(root, "cluster-1", task.MetaData.UID, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status.Phase != "Success" || strings.Join(phases, ",") != "Running,Pause,Success" {
+		t.Fatalf("unexpected phases %v", phases)
+	}
+}
+
+func TestUpgradeFailures(t *testing.T) {
+	precheck := common.PrecheckTask{Status: common.PrecheckTaskStatus{
+		Phase:   "Failed",
+		Message: "1 check failed",
+		Results: []common.UpgradeCheckResult{
+			{Name: "node-1", Kind: "node", Status: "Success"},
+			{Name: "coredns", Kind: "addon", Status: "Failed", Message: "version is not supported"},
+		},
+	}}
+	c := newWaitClient(map[string][]interface{}{
+		"GET /api/v3/projects/test/clusters/cluster-1/operation/precheck/tasks/check-1": {precheck},
+		"GET /api/v3/projects/test/clusters/cluster-1/operation/upgrade/tasks/task-1": {
+			upgradeTaskInPhase("Running"),
+			upgradeTaskInPhase("Failed",
+				common.UpgradeNodeProgress{NodeID: "node-1", Phase: "Success"},
+				common.UpgradeNodeProgress{NodeID: "node-2", Phase: "Failed", Message: "drain timed out"},
+			),
+		},
+	})
+	root := context.Background()
+	wait := &UpgradeWaitOptions{Interval: time.Millisecond}
+
+	_, err := c.WaitForPrecheckTask(root, "cluster-1", "check-1", wait)
+	if err == nil || !strings.Contains(err.Error(), "addon coredns: version is not supported") || strings.Contains(err.Error(), "node-1") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	task, err := c.WaitForUpgradeTask(root, "cluster-1", "task-1", wait)
+	if err == nil || !strings.Contains(err.Error(), "node node-2: drain timed out") || strings.Contains(err.Error(), "node-1") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if task.Status.Phase != "Failed" {
+		t.Fatalf("expected the failed task to be returned, got %s", task.Status.Phase)
+	}
+}
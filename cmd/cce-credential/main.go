@@ -0,0 +1,69 @@
+// cce-credential is a kubectl exec credential plugin which authenticates
+// with short-lived client certificates of a CCE cluster, e.g.
+//
+//	users:
+//	- name: prod
+//	  user:
+//	    exec:
+//	      apiVersion: client.authentication.k8s.io/v1alpha1
+//	      command: cce-credential
+//	      args: ["--cluster-id", "<cluster uid>"]
+//
+// The AK/SK are read from ACCESS_KEY, SECRET_KEY, REGION and PROJECT_ID, or
+// from the yaml profile given with --profile. Clusters which are only
+// reachable on their EIP need --endpoint external.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cnrancher/huaweicloud-sdk/cce"
+	"github.com/cnrancher/huaweicloud-sdk/cce/credential"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1alpha1"
+)
+
+func main() {
+	home, _ := os.UserHomeDir()
+	clusterID := flag.String("cluster-id", "", "uid of the CCE cluster")
+	duration := flag.Int64("duration", 1, "validity of new certificates in days, -1 is the maximum")
+	endpoint := flag.String("endpoint", cce.KubeconfigEndpointInternal, "kubeconfig endpoint of the certificate, internal, external or externalOTC")
+	cacheDir := flag.String("cache-dir", filepath.Join(home, ".kube", "cache", "cce"), "directory of the cached certificates, empty disables the cache")
+	profile := flag.String("profile", "", "yaml file with access_key, secret_key, region and project_id")
+	flag.Parse()
+
+	if err := run(*clusterID, *duration, *endpoint, *cacheDir, *profile); err != nil {
+		fmt.Fprintln(os.Stderr, "cce-credential:", err)
+		os.Exit(1)
+	}
+}
+
+func run(clusterID string, duration int64, endpoint, cacheDir, profile string) error {
+	var info *v1alpha1.ExecCredential
+	if env := os.Getenv(credential.ExecInfoEnv); env != "" {
+		info = &v1alpha1.ExecCredential{}
+		if err := json.Unmarshal([]byte(env), info); err != nil {
+			return fmt.Errorf("error parsing %s: %v", credential.ExecInfoEnv, err)
+		}
+	}
+	base, err := credential.LoadBaseClient(profile)
+	if err != nil {
+		return err
+	}
+	provider := &credential.Provider{
+		Client:    cce.NewClient(base),
+		ClusterID: clusterID,
+		Duration:  duration,
+		Endpoint:  endpoint,
+		CacheDir:  cacheDir,
+	}
+	rtn, err := provider.Credential(context.Background(), info)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(rtn)
+}
@@ -0,0 +1,98 @@
+package common
+
+// Models of the CCE add-on api served under /api/v3/addontemplates and
+// /api/v3/addons, which unlike the other CCE apis are not project scoped
+
+type AddonMetaData struct {
+	UID               string            `json:"uid,omitempty"`
+	Name              string            `json:"name,omitempty"`
+	Alias             string            `json:"alias,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+	UpdateTimestamp   string            `json:"updateTimestamp,omitempty"`
+}
+
+// AddonSupportVersion lists the cluster versions an add-on version can be
+// installed on, the versions are patterns such as v1.19.*
+type AddonSupportVersion struct {
+	ClusterType    string   `json:"clusterType"`
+	ClusterVersion []string `json:"clusterVersion"`
+}
+
+type AddonVersion struct {
+	Version string `json:"version"`
+	// Input has the default basic values and the custom and flavor
+	// parameters of the version, see DefaultAddonValues in cce
+	Input             map[string]interface{} `json:"input,omitempty"`
+	Stable            bool                   `json:"stable"`
+	Translate         map[string]interface{} `json:"translate,omitempty"`
+	SupportVersions   []AddonSupportVersion  `json:"supportVersions,omitempty"`
+	CreationTimestamp string                 `json:"creationTimestamp,omitempty"`
+	UpdateTimestamp   string                 `json:"updateTimestamp,omitempty"`
+}
+
+type AddonTemplateSpec struct {
+	Type        string         `json:"type"`
+	Require     bool           `json:"require"`
+	Labels      []string       `json:"labels,omitempty"`
+	LogoURL     string         `json:"logoURL,omitempty"`
+	ReadmeURL   string         `json:"readmeURL,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Versions    []AddonVersion `json:"versions"`
+}
+
+type AddonTemplate struct {
+	Kind       string            `json:"kind,omitempty"`
+	APIVersion string            `json:"apiVersion,omitempty"`
+	MetaData   AddonMetaData     `json:"metadata"`
+	Spec       AddonTemplateSpec `json:"spec"`
+}
+
+type AddonTemplateList struct {
+	Kind       string          `json:"kind,omitempty"`
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Items      []AddonTemplate `json:"items"`
+}
+
+// AddonValues configures an add-on instance. Basic holds the values CCE
+// fills in from the cluster such as the image registry, Custom the user
+// settings and Flavor the replicas and resources of the add-on.
+type AddonValues struct {
+	Basic  map[string]interface{} `json:"basic"`
+	Custom map[string]interface{} `json:"custom,omitempty"`
+	Flavor map[string]interface{} `json:"flavor,omitempty"`
+}
+
+type AddonInstanceSpec struct {
+	ClusterID           string      `json:"clusterID"`
+	Version             string      `json:"version"`
+	AddonTemplateName   string      `json:"addonTemplateName"`
+	AddonTemplateType   string      `json:"addonTemplateType,omitempty"`
+	AddonTemplateLogo   string      `json:"addonTemplateLogo,omitempty"`
+	AddonTemplateLabels []string    `json:"addonTemplateLabels,omitempty"`
+	Description         string      `json:"description,omitempty"`
+	Values              AddonValues `json:"values"`
+}
+
+type AddonInstanceStatus struct {
+	Status         string                 `json:"status"`
+	Reason         string                 `json:"Reason,omitempty"`
+	Message        string                 `json:"message,omitempty"`
+	ExtendParam    map[string]interface{} `json:"extendParam,omitempty"`
+	CurrentVersion *AddonVersion          `json:"currentVersion,omitempty"`
+}
+
+type AddonInstance struct {
+	Kind       string               `json:"kind,omitempty"`
+	APIVersion string               `json:"apiVersion,omitempty"`
+	MetaData   AddonMetaData        `json:"metadata"`
+	Spec       AddonInstanceSpec    `json:"spec"`
+	Status     *AddonInstanceStatus `json:"status,omitempty"`
+}
+
+type AddonInstanceList struct {
+	Kind       string          `json:"kind,omitempty"`
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Items      []AddonInstance `json:"items"`
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CIDRList is sent as a comma joined list of CIDRs, single addresses are
+// accepted when parsing and stored as /32 or /128 networks
+type CIDRList []net.IPNet
+
+// ParseCIDRList parses a comma joined list of CIDRs or addresses
+func ParseCIDRList(s string) (CIDRList, error) {
+	list := CIDRList{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		ipnet, err := ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *ipnet)
+	}
+	return list, nil
+}
+
+// ParseCIDR parses a CIDR or a single address
+func ParseCIDR(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip address %q", s)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr %q", s)
+	}
+	return ipnet, nil
+}
+
+// Contains reports whether the list has the same network as ipnet
+func (l CIDRList) Contains(ipnet net.IPNet) bool {
+	for _, n := range l {
+		if n.String() == ipnet.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (l CIDRList) String() string {
+	items := make([]string, 0, len(l))
+	for _, n := range l {
+		items = append(items, n.String())
+	}
+	return strings.Join(items, ",")
+}
+
+func (l CIDRList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+func (l *CIDRList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	list, err := ParseCIDRList(s)
+	if err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCIDRListJSON(t *testing.T) {
+	list := ELBWhitelist{}
+	if err := json.Unmarshal([]byte(`{"whitelist":"192.168.0.1, 10.0.0.0/8,,fd00::/64,::1"}`), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Whitelist) != 4 {
+		t.Fatalf("unexpected cidrs %v", list.Whitelist)
+	}
+	if s := list.Whitelist.String(); s != "192.168.0.1/32,10.0.0.0/8,fd00::/64,::1/128" {
+		t.Fatalf("unexpected cidrs %s", s)
+	}
+	data, err := json.Marshal(ELBWhitelist{ListenerID: "l", Whitelist: list.Whitelist[:2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"listener_id":"l","whitelist":"192.168.0.1/32,10.0.0.0/8"}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"whitelist":"192.168.0.300"}`), &list); err == nil {
+		t.Fatal("invalid address should be rejected")
+	}
+	if err := json.Unmarshal([]byte(`{"whitelist":"10.0.0.0/33"}`), &list); err == nil {
+		t.Fatal("invalid cidr should be rejected")
+	}
+}
@@ -119,7 +119,8 @@ func (c *Client) DoRequest(ctx context.Context, method, url string, input, outpu
 		return nil, &einfo
 	}
 
-	if output != nil {
+	// DELETE and some PUT requests answer 204 without body
+	if output != nil && len(bytes.TrimSpace(byteData)) > 0 {
 		if err = json.Unmarshal(byteData, output); err != nil {
 			return nil, err
 		}
@@ -169,9 +170,18 @@ func NewClient(ak, sk, endpoint, region, projectID string) *Client {
 	return client
 }
 
+// SetServiceNameFunc sets the service name of the client and its signer. The
+// signer is copied so that clients created from the same base client do not
+// change the service name of each other.
 func (c *Client) SetServiceNameFunc(f func() string) {
 	c.getServiceFunc = f
-	c.signer.GetServiceNameFunc = f
+	old := c.signer
+	s := *old
+	s.GetServiceNameFunc = f
+	c.signer = &s
+	if c.HTTPClient.Transport == http.RoundTripper(old) {
+		c.HTTPClient.Transport = c.signer
+	}
 }
 
 func (c *Client) GetSignerServiceName() string {
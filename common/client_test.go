@@ -0,0 +1,16 @@
+package common
+
+import "testing"
+
+func TestSetServiceNameFuncDoesNotShareSigner(t *testing.T) {
+	base := NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	elb, vpc := *base, *base
+	elb.SetServiceNameFunc(func() string { return "elb" })
+	vpc.SetServiceNameFunc(func() string { return "vpc" })
+	if elb.GetSignerServiceName() != "elb" || vpc.GetSignerServiceName() != "vpc" || base.GetSignerServiceName() != "" {
+		t.Fatalf("signers are shared: %s %s %s", elb.GetSignerServiceName(), vpc.GetSignerServiceName(), base.GetSignerServiceName())
+	}
+	if elb.HTTPClient.Transport != elb.GetSigner() {
+		t.Fatal("http client does not use the signer of the client")
+	}
+}
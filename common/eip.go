@@ -0,0 +1,14 @@
+package common
+
+import "encoding/json"
+
+func (p PortDesc) MarshalJSON() ([]byte, error) {
+	type desc PortDesc
+	if !p.ClearPortID {
+		return json.Marshal(desc(p))
+	}
+	return json.Marshal(struct {
+		desc
+		PortID *string `json:"port_id"`
+	}{desc: desc(p)})
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEipAssocArgJSON(t *testing.T) {
+	cases := []struct {
+		arg      EipAssocArg
+		expected string
+	}{
+		{EipAssocArg{}, `{"publicip":{}}`},
+		{EipAssocArg{Port: PortDesc{PortID: "port-1"}}, `{"publicip":{"port_id":"port-1"}}`},
+		{EipAssocArg{Port: PortDesc{PortID: "port-1", ClearPortID: true}}, `{"publicip":{"port_id":null}}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(c.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.expected {
+			t.Fatalf("got %s, want %s", data, c.expected)
+		}
+	}
+}
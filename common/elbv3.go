@@ -0,0 +1,390 @@
+package common
+
+// Models of the dedicated load balancer api served under /v3/{project_id}/elb.
+// The same object is sent on creation and update, only the attributes which
+// are set are changed by an update.
+
+// ELBV3PageInfo is returned by the list apis, the next page is requested with
+// NextMarker as marker
+type ELBV3PageInfo struct {
+	PreviousMarker string `json:"previous_marker,omitempty"`
+	NextMarker     string `json:"next_marker,omitempty"`
+	CurrentCount   int64  `json:"current_count"`
+}
+
+type ELBV3LoadBalancerRequest struct {
+	Loadbalancer ELBV3LoadBalancer `json:"loadbalancer"`
+}
+
+type ELBV3LoadBalancerDetails struct {
+	Loadbalancer ELBV3LoadBalancer `json:"loadbalancer"`
+	// LoadbalancerID and OrderID are returned instead of the load balancer for prepaid creations
+	LoadbalancerID string `json:"loadbalancer_id,omitempty"`
+	OrderID        string `json:"order_id,omitempty"`
+	RequestID      string `json:"request_id,omitempty"`
+}
+
+type ELBV3LoadBalancer struct {
+	ID                  string   `json:"id,omitempty"`
+	ProjectID           string   `json:"project_id,omitempty"`
+	Name                string   `json:"name,omitempty"`
+	Description         string   `json:"description,omitempty"`
+	VpcID               string   `json:"vpc_id,omitempty"`
+	AvailabilityZones   []string `json:"availability_zone_list,omitempty"`
+	Guaranteed          *bool    `json:"guaranteed,omitempty"`
+	L4FlavorID          string   `json:"l4_flavor_id,omitempty"`
+	L7FlavorID          string   `json:"l7_flavor_id,omitempty"`
+	Provider            string   `json:"provider,omitempty"`
+	AdminStateUp        *bool    `json:"admin_state_up,omitempty"`
+	EnterpriseProjectID string   `json:"enterprise_project_id,omitempty"`
+	// VipSubnetCidrID is the IPv4 subnet (neutron_subnet_id) of the private VIP
+	VipSubnetCidrID string `json:"vip_subnet_cidr_id,omitempty"`
+	VipAddress      string `json:"vip_address,omitempty"`
+	VipPortID       string `json:"vip_port_id,omitempty"`
+	// IPv6VipVirsubnetID is the subnet (network id) of the IPv6 VIP
+	IPv6VipVirsubnetID string `json:"ipv6_vip_virsubnet_id,omitempty"`
+	IPv6VipAddress     string `json:"ipv6_vip_address,omitempty"`
+	IPv6VipPortID      string `json:"ipv6_vip_port_id,omitempty"`
+	// ELBVirsubnetIDs are the subnets (network ids) the load balancer uses to reach the backends
+	ELBVirsubnetIDs []string `json:"elb_virsubnet_ids,omitempty"`
+	// IPTargetEnable allows members outside of the VPC which are added by address
+	IPTargetEnable           *bool              `json:"ip_target_enable,omitempty"`
+	DeletionProtectionEnable *bool              `json:"deletion_protection_enable,omitempty"`
+	Publicip                 *ELBV3PublicIPSpec `json:"publicip,omitempty"`
+	Tags                     []Tag              `json:"tags,omitempty"`
+	ProvisioningStatus       string             `json:"provisioning_status,omitempty"`
+	OperatingStatus          string             `json:"operating_status,omitempty"`
+	Eips                     []ELBV3Eip         `json:"eips,omitempty"`
+	Publicips                []ELBV3PublicIP    `json:"publicips,omitempty"`
+	Listeners                []ELBResourceRef   `json:"listeners,omitempty"`
+	Pools                    []ELBResourceRef   `json:"pools,omitempty"`
+	CreatedAt                string             `json:"created_at,omitempty"`
+	UpdatedAt                string             `json:"updated_at,omitempty"`
+}
+
+// ELBV3PublicIPSpec creates a new EIP together with the load balancer
+type ELBV3PublicIPSpec struct {
+	IPVersion   int64                  `json:"ip_version,omitempty"`
+	NetworkType string                 `json:"network_type"` // 5_bgp or 5_sbgp
+	BillingInfo string                 `json:"billing_info,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Bandwidth   ELBV3PublicIPBandwidth `json:"bandwidth"`
+}
+
+type ELBV3PublicIPBandwidth struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Size       int64  `json:"size,omitempty"`
+	ChargeMode string `json:"charge_mode,omitempty"` // bandwidth or traffic
+	ShareType  string `json:"share_type,omitempty"`  // PER or WHOLE
+}
+
+type ELBV3Eip struct {
+	EipID      string `json:"eip_id"`
+	EipAddress string `json:"eip_address"`
+	IPVersion  int64  `json:"ip_version,omitempty"`
+}
+
+type ELBV3PublicIP struct {
+	PublicipID      string `json:"publicip_id"`
+	PublicipAddress string `json:"publicip_address"`
+	IPVersion       int64  `json:"ip_version,omitempty"`
+}
+
+type ELBV3LoadBalancerList struct {
+	Loadbalancers []ELBV3LoadBalancer `json:"loadbalancers"`
+	PageInfo      ELBV3PageInfo       `json:"page_info"`
+}
+
+type ELBV3LoadBalancerListRequest struct {
+	Marker             string   `json:"marker,omitempty"`
+	Limit              int64    `json:"limit,omitempty"`
+	PageReverse        bool     `json:"page_reverse,omitempty"`
+	ID                 []string `json:"id,omitempty"`
+	Name               []string `json:"name,omitempty"`
+	VpcID              []string `json:"vpc_id,omitempty"`
+	VipAddress         []string `json:"vip_address,omitempty"`
+	VipPortID          []string `json:"vip_port_id,omitempty"`
+	ProvisioningStatus []string `json:"provisioning_status,omitempty"`
+}
+
+type ELBV3ListenerRequest struct {
+	Listener ELBV3Listener `json:"listener"`
+}
+
+type ELBV3ListenerDetails struct {
+	Listener ELBV3Listener `json:"listener"`
+}
+
+type ELBV3Listener struct {
+	ID                      string           `json:"id,omitempty"`
+	ProjectID               string           `json:"project_id,omitempty"`
+	Name                    string           `json:"name,omitempty"`
+	Description             string           `json:"description,omitempty"`
+	Protocol                string           `json:"protocol,omitempty"` // TCP/UDP/HTTP/HTTPS/QUIC
+	ProtocolPort            int64            `json:"protocol_port,omitempty"`
+	LoadbalancerID          string           `json:"loadbalancer_id,omitempty"`
+	Loadbalancers           []ELBResourceRef `json:"loadbalancers,omitempty"`
+	DefaultPoolID           string           `json:"default_pool_id,omitempty"`
+	DefaultTLSContainerRef  string           `json:"default_tls_container_ref,omitempty"`
+	ClientCATLSContainerRef string           `json:"client_ca_tls_container_ref,omitempty"`
+	SniContainerRefs        []string         `json:"sni_container_refs,omitempty"`
+	TLSCiphersPolicy        string           `json:"tls_ciphers_policy,omitempty"`
+	HTTP2Enable             *bool            `json:"http2_enable,omitempty"`
+	AdminStateUp            *bool            `json:"admin_state_up,omitempty"`
+	KeepaliveTimeout        *int64           `json:"keepalive_timeout,omitempty"`
+	ClientTimeout           *int64           `json:"client_timeout,omitempty"`
+	MemberTimeout           *int64           `json:"member_timeout,omitempty"`
+	Tags                    []Tag            `json:"tags,omitempty"`
+	CreatedAt               string           `json:"created_at,omitempty"`
+	UpdatedAt               string           `json:"updated_at,omitempty"`
+}
+
+type ELBV3ListenerList struct {
+	Listeners []ELBV3Listener `json:"listeners"`
+	PageInfo  ELBV3PageInfo   `json:"page_info"`
+}
+
+type ELBV3ListenerListRequest struct {
+	Marker         string   `json:"marker,omitempty"`
+	Limit          int64    `json:"limit,omitempty"`
+	PageReverse    bool     `json:"page_reverse,omitempty"`
+	ID             []string `json:"id,omitempty"`
+	Name           []string `json:"name,omitempty"`
+	LoadbalancerID []string `json:"loadbalancer_id,omitempty"`
+	Protocol       []string `json:"protocol,omitempty"`
+	ProtocolPort   []int64  `json:"protocol_port,omitempty"`
+	DefaultPoolID  []string `json:"default_pool_id,omitempty"`
+}
+
+type ELBV3PoolRequest struct {
+	Pool ELBV3Pool `json:"pool"`
+}
+
+type ELBV3PoolDetails struct {
+	Pool ELBV3Pool `json:"pool"`
+}
+
+type ELBV3Pool struct {
+	ID                 string                   `json:"id,omitempty"`
+	ProjectID          string                   `json:"project_id,omitempty"`
+	Name               string                   `json:"name,omitempty"`
+	Description        string                   `json:"description,omitempty"`
+	Protocol           string                   `json:"protocol,omitempty"`     // TCP/UDP/HTTP/HTTPS/QUIC
+	LbAlgorithm        string                   `json:"lb_algorithm,omitempty"` // ROUND_ROBIN/LEAST_CONNECTIONS/SOURCE_IP/QUIC_CID
+	LoadbalancerID     string                   `json:"loadbalancer_id,omitempty"`
+	ListenerID         string                   `json:"listener_id,omitempty"`
+	Loadbalancers      []ELBResourceRef         `json:"loadbalancers,omitempty"`
+	Listeners          []ELBResourceRef         `json:"listeners,omitempty"`
+	Members            []ELBResourceRef         `json:"members,omitempty"`
+	HealthmonitorID    string                   `json:"healthmonitor_id,omitempty"`
+	SessionPersistence *ELBV3SessionPersistence `json:"session_persistence,omitempty"`
+	SlowStart          *ELBV3SlowStart          `json:"slow_start,omitempty"`
+	AdminStateUp       *bool                    `json:"admin_state_up,omitempty"`
+	IPVersion          string                   `json:"ip_version,omitempty"`
+	// VpcID and Type (instance or ip) restrict the members which can be added
+	VpcID string `json:"vpc_id,omitempty"`
+	Type  string `json:"type,omitempty"`
+	// MemberDeletionProtectionEnable refuses removing members while it is set
+	MemberDeletionProtectionEnable *bool `json:"member_deletion_protection_enable,omitempty"`
+}
+
+type ELBV3SessionPersistence struct {
+	Type               string `json:"type"` // SOURCE_IP/HTTP_COOKIE/APP_COOKIE
+	CookieName         string `json:"cookie_name,omitempty"`
+	PersistenceTimeout int64  `json:"persistence_timeout,omitempty"`
+}
+
+type ELBV3SlowStart struct {
+	Enable   bool  `json:"enable"`
+	Duration int64 `json:"duration,omitempty"`
+}
+
+type ELBV3PoolList struct {
+	Pools    []ELBV3Pool   `json:"pools"`
+	PageInfo ELBV3PageInfo `json:"page_info"`
+}
+
+type ELBV3PoolListRequest struct {
+	Marker          string   `json:"marker,omitempty"`
+	Limit           int64    `json:"limit,omitempty"`
+	PageReverse     bool     `json:"page_reverse,omitempty"`
+	ID              []string `json:"id,omitempty"`
+	Name            []string `json:"name,omitempty"`
+	LoadbalancerID  []string `json:"loadbalancer_id,omitempty"`
+	ListenerID      []string `json:"listener_id,omitempty"`
+	Protocol        []string `json:"protocol,omitempty"`
+	MemberAddress   []string `json:"member_address,omitempty"`
+	MemberDeviceID  []string `json:"member_device_id,omitempty"`
+	HealthmonitorID []string `json:"healthmonitor_id,omitempty"`
+}
+
+type ELBV3MemberRequest struct {
+	Member ELBV3Member `json:"member"`
+}
+
+type ELBV3MemberDetails struct {
+	Member ELBV3Member `json:"member"`
+}
+
+type ELBV3Member struct {
+	ID        string `json:"id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Address   string `json:"address,omitempty"`
+	// ProtocolPort is only required when the pool is not bound to a listener port
+	ProtocolPort int64 `json:"protocol_port,omitempty"`
+	// SubnetCidrID is the IPv4 or IPv6 subnet of the member, it is left empty
+	// for IP type members outside of the VPC
+	SubnetCidrID    string `json:"subnet_cidr_id,omitempty"`
+	Weight          *int64 `json:"weight,omitempty"`
+	AdminStateUp    *bool  `json:"admin_state_up,omitempty"`
+	IPVersion       string `json:"ip_version,omitempty"`
+	MemberType      string `json:"member_type,omitempty"` // instance or ip
+	InstanceID      string `json:"instance_id,omitempty"`
+	OperatingStatus string `json:"operating_status,omitempty"`
+}
+
+type ELBV3MemberList struct {
+	Members  []ELBV3Member `json:"members"`
+	PageInfo ELBV3PageInfo `json:"page_info"`
+}
+
+type ELBV3MemberListRequest struct {
+	Marker          string   `json:"marker,omitempty"`
+	Limit           int64    `json:"limit,omitempty"`
+	PageReverse     bool     `json:"page_reverse,omitempty"`
+	ID              []string `json:"id,omitempty"`
+	Name            []string `json:"name,omitempty"`
+	Address         []string `json:"address,omitempty"`
+	ProtocolPort    []int64  `json:"protocol_port,omitempty"`
+	SubnetCidrID    []string `json:"subnet_cidr_id,omitempty"`
+	OperatingStatus []string `json:"operating_status,omitempty"`
+}
+
+type ELBV3HealthmonitorRequest struct {
+	Healthmonitor ELBV3Healthmonitor `json:"healthmonitor"`
+}
+
+type ELBV3HealthmonitorDetails struct {
+	Healthmonitor ELBV3Healthmonitor `json:"healthmonitor"`
+}
+
+type ELBV3Healthmonitor struct {
+	ID             string           `json:"id,omitempty"`
+	ProjectID      string           `json:"project_id,omitempty"`
+	Name           string           `json:"name,omitempty"`
+	PoolID         string           `json:"pool_id,omitempty"` // only used on creation
+	Pools          []ELBResourceRef `json:"pools,omitempty"`
+	Type           string           `json:"type,omitempty"` // TCP/UDP_CONNECT/HTTP/HTTPS/PING
+	Delay          int64            `json:"delay,omitempty"`
+	Timeout        int64            `json:"timeout,omitempty"`
+	MaxRetries     int64            `json:"max_retries,omitempty"`
+	MaxRetriesDown int64            `json:"max_retries_down,omitempty"`
+	MonitorPort    *int64           `json:"monitor_port,omitempty"`
+	URLPath        string           `json:"url_path,omitempty"`
+	HTTPMethod     string           `json:"http_method,omitempty"`
+	ExpectedCodes  string           `json:"expected_codes,omitempty"`
+	DomainName     string           `json:"domain_name,omitempty"`
+	AdminStateUp   *bool            `json:"admin_state_up,omitempty"`
+}
+
+type ELBV3HealthmonitorList struct {
+	Healthmonitors []ELBV3Healthmonitor `json:"healthmonitors"`
+	PageInfo       ELBV3PageInfo        `json:"page_info"`
+}
+
+type ELBV3HealthmonitorListRequest struct {
+	Marker      string   `json:"marker,omitempty"`
+	Limit       int64    `json:"limit,omitempty"`
+	PageReverse bool     `json:"page_reverse,omitempty"`
+	ID          []string `json:"id,omitempty"`
+	Name        []string `json:"name,omitempty"`
+	Type        []string `json:"type,omitempty"`
+}
+
+type ELBV3CertificateRequest struct {
+	Certificate ELBV3Certificate `json:"certificate"`
+}
+
+type ELBV3CertificateDetails struct {
+	Certificate ELBV3Certificate `json:"certificate"`
+}
+
+type ELBV3Certificate struct {
+	ID           string `json:"id,omitempty"`
+	ProjectID    string `json:"project_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type,omitempty"` // server or client
+	Domain       string `json:"domain,omitempty"`
+	Certificate  string `json:"certificate,omitempty"`
+	PrivateKey   string `json:"private_key,omitempty"`
+	AdminStateUp *bool  `json:"admin_state_up,omitempty"`
+	ExpireTime   string `json:"expire_time,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	UpdatedAt    string `json:"updated_at,omitempty"`
+}
+
+type ELBV3CertificateList struct {
+	Certificates []ELBV3Certificate `json:"certificates"`
+	PageInfo     ELBV3PageInfo      `json:"page_info"`
+}
+
+type ELBV3CertificateListRequest struct {
+	Marker      string   `json:"marker,omitempty"`
+	Limit       int64    `json:"limit,omitempty"`
+	PageReverse bool     `json:"page_reverse,omitempty"`
+	ID          []string `json:"id,omitempty"`
+	Name        []string `json:"name,omitempty"`
+	Domain      []string `json:"domain,omitempty"`
+	Type        []string `json:"type,omitempty"`
+}
+
+type ELBV3Flavor struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Type          string          `json:"type"` // L4/L7/L4_elastic/L7_elastic
+	Shared        bool            `json:"shared"`
+	ProjectID     string          `json:"project_id,omitempty"`
+	FlavorSoldOut bool            `json:"flavor_sold_out,omitempty"`
+	Info          ELBV3FlavorInfo `json:"info"`
+}
+
+type ELBV3FlavorInfo struct {
+	Connection int64 `json:"connection,omitempty"`
+	CPS        int64 `json:"cps,omitempty"`
+	QPS        int64 `json:"qps,omitempty"`
+	HTTPSCPS   int64 `json:"https_cps,omitempty"`
+	Bandwidth  int64 `json:"bandwidth,omitempty"`
+	LCU        int64 `json:"lcu,omitempty"`
+}
+
+type ELBV3FlavorList struct {
+	Flavors  []ELBV3Flavor `json:"flavors"`
+	PageInfo ELBV3PageInfo `json:"page_info"`
+}
+
+type ELBV3FlavorListRequest struct {
+	Marker      string   `json:"marker,omitempty"`
+	Limit       int64    `json:"limit,omitempty"`
+	PageReverse bool     `json:"page_reverse,omitempty"`
+	ID          []string `json:"id,omitempty"`
+	Name        []string `json:"name,omitempty"`
+	Type        []string `json:"type,omitempty"`
+	Shared      *bool    `json:"shared,omitempty"`
+}
+
+type ELBV3AvailabilityZone struct {
+	Code              string   `json:"code"`
+	State             string   `json:"state"`
+	Protocol          []string `json:"protocol,omitempty"`
+	PublicBorderGroup string   `json:"public_border_group,omitempty"`
+	Category          int64    `json:"category,omitempty"`
+}
+
+// ELBV3AvailabilityZoneList holds the sets of availability zones which can be
+// combined, a load balancer uses zones of a single set
+type ELBV3AvailabilityZoneList struct {
+	AvailabilityZones [][]ELBV3AvailabilityZone `json:"availability_zones"`
+}
@@ -0,0 +1,14 @@
+package common
+
+import "encoding/json"
+
+func (u ELBHealthmonitorUpdate) MarshalJSON() ([]byte, error) {
+	type update ELBHealthmonitorUpdate
+	if !u.ClearMonitorPort {
+		return json.Marshal(update(u))
+	}
+	return json.Marshal(struct {
+		update
+		MonitorPort *int64 `json:"monitor_port"`
+	}{update: update(u)})
+}
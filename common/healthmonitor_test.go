@@ -0,0 +1,27 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestELBHealthmonitorUpdateJSON(t *testing.T) {
+	port := int64(8080)
+	cases := []struct {
+		update   ELBHealthmonitorUpdate
+		expected string
+	}{
+		{ELBHealthmonitorUpdate{Delay: 5}, `{"delay":5}`},
+		{ELBHealthmonitorUpdate{MonitorPort: &port}, `{"monitor_port":8080}`},
+		{ELBHealthmonitorUpdate{Delay: 5, MonitorPort: &port, ClearMonitorPort: true}, `{"delay":5,"monitor_port":null}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(ELBHealthmonitorUpdateRequest{Healthmonitor: c.update})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"healthmonitor":`+c.expected+`}` {
+			t.Fatalf("got %s, want %s", data, c.expected)
+		}
+	}
+}
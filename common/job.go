@@ -16,8 +16,49 @@ const (
 	JobRunning = "running"
 	JobFail    = "fail"
 	JobInit    = "init"
+	JobQueuing = "queuing"
+	JobPause   = "pause"
 )
 
+// ErrJobPaused is returned by JobPhaseDone for a paused job or task
+var ErrJobPaused = errors.New("job is paused")
+
+// JobPhaseDone reports whether a job, or a task which has the same phases as
+// jobs, is finished. Init and queuing are still running, ErrJobPaused is
+// returned for pause and an error for the failed phases.
+func JobPhaseDone(phase string) (bool, error) {
+	switch strings.ToLower(phase) {
+	case JobSuccess:
+		return true, nil
+	case JobRunning, JobInit, JobQueuing:
+		return false, nil
+	case JobPause:
+		return false, ErrJobPaused
+	}
+	return false, fmt.Errorf("phase is %s", phase)
+}
+
+// GetJobV3 returns a job of the /api/v3 services such as CCE
+func (c *Client) GetJobV3(ctx context.Context, jobID string) (*JobInfo, error) {
+	if jobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	rtn := JobInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("jobs", jobID),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// WaitForJobReadyV3 waits until the job succeeded. Jobs in the init or
+// queuing phase are waited for like running ones. A paused job ends the wait
+// with an error wrapping ErrJobPaused.
 func (c *Client) WaitForJobReadyV3(ctx context.Context, duration, timeout time.Duration, jobID string) (bool, *JobInfo, error) {
 	if jobID == "" {
 		return false, nil, errors.New("job id is required")
@@ -25,27 +66,19 @@ func (c *Client) WaitForJobReadyV3(ctx context.Context, duration, timeout time.D
 	var lastJobInfo *JobInfo
 	err := CustomWaitForCompleteUntilTrue(ctx, duration, timeout, func(ictx context.Context) (bool, error) {
 		logrus.Infof("Querying job %s for %s", jobID, c.getServiceFunc())
-		var jobInfo JobInfo
-		_, err := c.DoRequest(
-			ictx,
-			http.MethodGet,
-			c.GetURL("jobs", jobID),
-			nil,
-			&jobInfo,
-		)
+		jobInfo, err := c.GetJobV3(ictx, jobID)
 		if err != nil {
 			return false, err
 		}
-		lastJobInfo = &jobInfo
-		switch strings.ToLower(jobInfo.Status.Phase) {
-		case JobSuccess:
-			return true, nil
-		case JobRunning:
+		lastJobInfo = jobInfo
+		done, err := JobPhaseDone(jobInfo.Status.Phase)
+		if err != nil {
+			return false, errors.Wrapf(err, "error for waiting %s job %s in phase %s", c.getServiceFunc(), jobID, jobInfo.Status.Phase)
+		}
+		if !done {
 			logrus.Debugf("job %s is still running", jobID)
-			return false, nil
-		default:
-			return false, fmt.Errorf("error for waiting %s job for %s", c.getServiceFunc(), jobID)
 		}
+		return done, nil
 	})
 	return err == nil, lastJobInfo, err
 }
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// jobTransport answers every job request with the next of phases, the last
+// one is repeated
+type jobTransport struct {
+	phases []string
+}
+
+func (j *jobTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	phase := j.phases[0]
+	if len(j.phases) > 1 {
+		j.phases = j.phases[1:]
+	}
+	b, _ := json.Marshal(JobInfo{Status: JobStatus{Phase: phase}})
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func newJobClient(phases ...string) *Client {
+	c := NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	c.SetServiceNameFunc(func() string { return "cce" })
+	c.GetSigner().NextTransport = &jobTransport{phases: phases}
+	return c
+}
+
+func TestWaitForJobReadyV3(t *testing.T) {
+	ctx := context.Background()
+	ok, job, err := newJobClient("Init", "Queuing", "Running", "Success").WaitForJobReadyV3(ctx, time.Millisecond, time.Second, "job-1")
+	if !ok || err != nil || job.Status.Phase != "Success" {
+		t.Fatalf("init and queuing jobs should be waited for: %v", err)
+	}
+
+	_, job, err = newJobClient("Running", "Pause").WaitForJobReadyV3(ctx, time.Millisecond, time.Second, "job-1")
+	if errors.Cause(err) != ErrJobPaused || !strings.Contains(err.Error(), "Pause") || job.Status.Phase != "Pause" {
+		t.Fatalf("expected paused job, got %v", err)
+	}
+
+	_, _, err = newJobClient("Fail").WaitForJobReadyV3(ctx, time.Millisecond, time.Second, "job-1")
+	if err == nil || errors.Cause(err) == ErrJobPaused || !strings.Contains(err.Error(), "Fail") {
+		t.Fatalf("expected failed job, got %v", err)
+	}
+}
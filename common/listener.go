@@ -0,0 +1,22 @@
+package common
+
+import "encoding/json"
+
+func (u ELBListenerUpdateObject) MarshalJSON() ([]byte, error) {
+	type update ELBListenerUpdateObject
+	data, err := json.Marshal(update(u))
+	if err != nil || !u.ClearDefaultTlsContainerRef && !u.ClearSniContainerRefs {
+		return data, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if u.ClearDefaultTlsContainerRef {
+		fields["default_tls_container_ref"] = json.RawMessage("null")
+	}
+	if u.ClearSniContainerRefs {
+		fields["sni_container_refs"] = json.RawMessage("[]")
+	}
+	return json.Marshal(fields)
+}
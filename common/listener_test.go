@@ -0,0 +1,27 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestELBListenerUpdateJSON(t *testing.T) {
+	cases := []struct {
+		update   ELBListenerUpdateObject
+		expected string
+	}{
+		{ELBListenerUpdateObject{DefaultTlsContainerRef: "cert-1"}, `{"default_tls_container_ref":"cert-1"}`},
+		{ELBListenerUpdateObject{SniContainerRefs: []string{}}, `{}`},
+		{ELBListenerUpdateObject{ClearDefaultTlsContainerRef: true}, `{"default_tls_container_ref":null}`},
+		{ELBListenerUpdateObject{Name: "https", ClearSniContainerRefs: true}, `{"name":"https","sni_container_refs":[]}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(ELBListenerUpdateRequest{Listener: c.update})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"listener":`+c.expected+`}` {
+			t.Fatalf("got %s, want %s", data, c.expected)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package common
+
+// Models of the CCE node pool api served under /clusters/{cluster_id}/nodepools
+
+type NodePoolMetaInfo struct {
+	Name              string            `json:"name"`
+	UID               string            `json:"uid,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+	UpdateTimestamp   string            `json:"updateTimestamp,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+type NodePoolAutoscaling struct {
+	Enable       bool  `json:"enable"`
+	MinNodeCount int64 `json:"minNodeCount,omitempty"`
+	MaxNodeCount int64 `json:"maxNodeCount,omitempty"`
+	// ScaleDownCooldownTime is in minutes
+	ScaleDownCooldownTime int64 `json:"scaleDownCooldownTime,omitempty"`
+	// Priority decides which pool the autoscaler scales up first, higher goes first
+	Priority int64 `json:"priority,omitempty"`
+}
+
+type NodePoolManagement struct {
+	ServerGroupReference string `json:"serverGroupReference,omitempty"`
+}
+
+type NodePoolSpec struct {
+	// Type is vm or ElasticBMS
+	Type         string       `json:"type,omitempty"`
+	NodeTemplate NodeSpecInfo `json:"nodeTemplate"`
+	// InitialNodeCount is the node count the pool is scaled to
+	InitialNodeCount int64                `json:"initialNodeCount"`
+	Autoscaling      *NodePoolAutoscaling `json:"autoscaling,omitempty"`
+	NodeManagement   *NodePoolManagement  `json:"nodeManagement,omitempty"`
+}
+
+// NodePoolStatus has an empty Phase while the pool is available
+type NodePoolStatus struct {
+	CurrentNode  int64  `json:"currentNode"`
+	CreatingNode int64  `json:"creatingNode,omitempty"`
+	DeletingNode int64  `json:"deletingNode,omitempty"`
+	Phase        string `json:"phase,omitempty"`
+	JobID        string `json:"jobId,omitempty"`
+}
+
+type NodePool struct {
+	Kind       string           `json:"kind,omitempty"`
+	APIVersion string           `json:"apiVersion,omitempty"`
+	MetaData   NodePoolMetaInfo `json:"metadata"`
+	Spec       NodePoolSpec     `json:"spec"`
+	Status     *NodePoolStatus  `json:"status,omitempty"`
+}
+
+type NodePoolList struct {
+	Kind       string     `json:"kind,omitempty"`
+	APIVersion string     `json:"apiVersion,omitempty"`
+	Items      []NodePool `json:"items"`
+}
@@ -0,0 +1,14 @@
+package common
+
+import "encoding/json"
+
+func (u ELBBackendGroupUpdate) MarshalJSON() ([]byte, error) {
+	type update ELBBackendGroupUpdate
+	if !u.ClearSessionPersistence {
+		return json.Marshal(update(u))
+	}
+	return json.Marshal(struct {
+		update
+		SessionPersistence *SessionPersistence `json:"session_persistence"`
+	}{update: update(u)})
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestELBBackendGroupUpdateJSON(t *testing.T) {
+	cases := []struct {
+		update   ELBBackendGroupUpdate
+		expected string
+	}{
+		{ELBBackendGroupUpdate{Name: "web"}, `{"name":"web"}`},
+		{ELBBackendGroupUpdate{SessionPersistence: &SessionPersistence{Type: "SOURCE_IP"}}, `{"session_persistence":{"type":"SOURCE_IP"}}`},
+		{ELBBackendGroupUpdate{LbAlgorithm: "ROUND_ROBIN", ClearSessionPersistence: true}, `{"lb_algorithm":"ROUND_ROBIN","session_persistence":null}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(ELBBackendGroupUpdateRequest{Pool: c.update})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"pool":`+c.expected+`}` {
+			t.Fatalf("got %s, want %s", data, c.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// EncodeQuery encodes the fields of a filter struct as query parameters. The
+// json tags give the parameter names, fields tagged omitempty are skipped when
+// they hold a zero value and slices are encoded as repeated keys.
+func EncodeQuery(filter interface{}) url.Values {
+	values := url.Values{}
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return values
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return values
+	}
+	encodeStruct(values, v)
+	return values
+}
+
+func encodeStruct(values url.Values, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && fv.Kind() == reflect.Struct && tag == "" {
+			encodeStruct(values, fv)
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if omitempty && isZero(fv) {
+			continue
+		}
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				values.Add(name, fmt.Sprint(fv.Index(j).Interface()))
+			}
+			continue
+		}
+		values.Add(name, fmt.Sprint(fv.Interface()))
+	}
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// WithQuery appends the encoded filter to path
+func WithQuery(path string, filter interface{}) string {
+	query := EncodeQuery(filter).Encode()
+	if query == "" {
+		return path
+	}
+	if strings.Contains(path, "?") {
+		return path + "&" + query
+	}
+	return path + "?" + query
+}
@@ -0,0 +1,31 @@
+package common
+
+import "testing"
+
+func TestEncodeQuery(t *testing.T) {
+	adminStateUp := false
+	filter := struct {
+		Marker       string   `json:"marker,omitempty"`
+		Limit        int64    `json:"limit,omitempty"`
+		PageReverse  bool     `json:"page_reverse,omitempty"`
+		Name         string   `json:"name,omitempty"`
+		AdminStateUp *bool    `json:"admin_state_up,omitempty"`
+		IDs          []string `json:"id,omitempty"`
+		Ignored      string   `json:"-"`
+	}{
+		Limit:        10,
+		Name:         "a b",
+		AdminStateUp: &adminStateUp,
+		IDs:          []string{"1", "2"},
+		Ignored:      "x",
+	}
+	if got := EncodeQuery(&filter).Encode(); got != "admin_state_up=false&id=1&id=2&limit=10&name=a+b" {
+		t.Fatal("wrong query", got)
+	}
+	if got := WithQuery("https://host/pools", nil); got != "https://host/pools" {
+		t.Fatal("wrong url", got)
+	}
+	if got := WithQuery("https://host/pools?a=1", &filter); got != "https://host/pools?a=1&admin_state_up=false&id=1&id=2&limit=10&name=a+b" {
+		t.Fatal("wrong url", got)
+	}
+}
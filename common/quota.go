@@ -0,0 +1,67 @@
+package common
+
+// Get returns the quota of resourceType
+func (q *QuotaResources) Get(resourceType string) (QuotaResource, bool) {
+	if q == nil {
+		return QuotaResource{}, false
+	}
+	for _, r := range q.Resources {
+		if r.Type == resourceType {
+			return r, true
+		}
+	}
+	return QuotaResource{}, false
+}
+
+// Available returns how many resources can still be created, -1 means unlimited
+func (r QuotaResource) Available() int64 {
+	if r.Quota < 0 {
+		return -1
+	}
+	if r.Used >= r.Quota {
+		return 0
+	}
+	return r.Quota - r.Used
+}
+
+// Get returns the quota of quotaKey
+func (q *CCEQuotaList) Get(quotaKey string) (CCEQuota, bool) {
+	if q == nil {
+		return CCEQuota{}, false
+	}
+	for _, r := range q.Quotas {
+		if r.QuotaKey == quotaKey {
+			return r, true
+		}
+	}
+	return CCEQuota{}, false
+}
+
+// Available returns how many resources can still be created, -1 means unlimited
+func (r CCEQuota) Available() int64 {
+	if r.QuotaLimit < 0 {
+		return -1
+	}
+	if r.Used >= r.QuotaLimit {
+		return 0
+	}
+	return r.QuotaLimit - r.Used
+}
+
+// Available returns how many instances, vCPUs and MB of RAM can still be
+// used, -1 means unlimited
+func (l *ECSAbsoluteLimits) Available() (instances, cores, ram int64) {
+	return limitAvailable(l.MaxTotalInstances, l.TotalInstancesUsed),
+		limitAvailable(l.MaxTotalCores, l.TotalCoresUsed),
+		limitAvailable(l.MaxTotalRAMSize, l.TotalRAMUsed)
+}
+
+func limitAvailable(limit, used int64) int64 {
+	if limit < 0 {
+		return -1
+	}
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
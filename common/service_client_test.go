@@ -0,0 +1,45 @@
+package common_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/ecs"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+)
+
+type authRecorder []string
+
+func (a *authRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	*a = append(*a, r.Header.Get("Authorization"))
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader([]byte("{}")))}, nil
+}
+
+// Service clients built from one base client used to share its signer, so
+// the service client created last set the signing scope of all of them
+func TestServiceClientsFromOneBase(t *testing.T) {
+	recorder := &authRecorder{}
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = recorder
+	vpc := network.NewClient(base)
+	compute := ecs.NewClient(base)
+	ctx := context.Background()
+
+	if _, err := vpc.GetQuotas(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compute.GetLimits(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(*recorder) != 2 || !strings.Contains((*recorder)[0], "/cn-north-1/vpc/") || !strings.Contains((*recorder)[1], "/cn-north-1/ecs/") {
+		t.Fatalf("requests are not signed for their own service: %v", *recorder)
+	}
+	if base.GetSignerServiceName() != "" {
+		t.Fatalf("service name of the base client is changed to %s", base.GetSignerServiceName())
+	}
+}
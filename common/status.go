@@ -0,0 +1,54 @@
+package common
+
+const (
+	ProvisioningActive        = "ACTIVE"
+	ProvisioningPendingCreate = "PENDING_CREATE"
+	ProvisioningPendingUpdate = "PENDING_UPDATE"
+	ProvisioningPendingDelete = "PENDING_DELETE"
+	ProvisioningError         = "ERROR"
+)
+
+// Listener returns the status of the listener, nil when the load balancer has no such listener
+func (s *ELBLoadBalancerStatus) Listener(id string) *ELBListenerStatus {
+	for i := range s.Listeners {
+		if s.Listeners[i].ID == id {
+			return &s.Listeners[i]
+		}
+	}
+	return nil
+}
+
+// Pool returns the status of the pool, pools bound to a listener and to the
+// load balancer are both searched
+func (s *ELBLoadBalancerStatus) Pool(id string) *ELBPoolStatus {
+	for _, pools := range s.allPools() {
+		for i := range pools {
+			if pools[i].ID == id {
+				return &pools[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Member returns the status of the member of the pool
+func (s *ELBLoadBalancerStatus) Member(poolID, id string) *ELBMemberStatus {
+	pool := s.Pool(poolID)
+	if pool == nil {
+		return nil
+	}
+	for i := range pool.Members {
+		if pool.Members[i].ID == id {
+			return &pool.Members[i]
+		}
+	}
+	return nil
+}
+
+func (s *ELBLoadBalancerStatus) allPools() [][]ELBPoolStatus {
+	rtn := [][]ELBPoolStatus{s.Pools}
+	for _, listener := range s.Listeners {
+		rtn = append(rtn, listener.Pools)
+	}
+	return rtn
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	TagActionCreate = "create"
+	TagActionDelete = "delete"
+	TagActionFilter = "filter"
+	TagActionCount  = "count"
+)
+
+// The helpers below implement the /tags and /resource_instances/action
+// endpoints which are shared by the vpc and elb services. resourceURL is the
+// url of a single resource, e.g. .../vpcs/{vpc_id}, and resourceTypeURL is the
+// url of the collection, e.g. .../vpcs.
+
+func (c *Client) CreateResourceTag(ctx context.Context, resourceURL string, tag Tag) error {
+	if tag.Key == "" {
+		return errors.New("tag key is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		resourceURL+"/tags",
+		&TagRequest{Tag: tag},
+		nil,
+	)
+	return err
+}
+
+func (c *Client) DeleteResourceTag(ctx context.Context, resourceURL, key string) error {
+	if key == "" {
+		return errors.New("tag key is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		resourceURL+"/tags/"+url.PathEscape(key),
+		nil,
+		nil,
+	)
+	return err
+}
+
+func (c *Client) BatchUpdateResourceTags(ctx context.Context, resourceURL, action string, tags []Tag) error {
+	if action != TagActionCreate && action != TagActionDelete {
+		return errors.New("tag action should be create or delete")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		resourceURL+"/tags/action",
+		&BatchTagRequest{Action: action, Tags: tags},
+		nil,
+	)
+	return err
+}
+
+func (c *Client) GetResourceTags(ctx context.Context, resourceURL string) ([]Tag, error) {
+	rtn := TagList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		resourceURL+"/tags",
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return rtn.Tags, nil
+}
+
+func (c *Client) ListResourcesByTags(ctx context.Context, resourceTypeURL string, filter *ResourceTagFilter) (*ResourceInstanceList, error) {
+	if filter == nil {
+		filter = &ResourceTagFilter{}
+	}
+	input := *filter
+	if input.Action == "" {
+		input.Action = TagActionFilter
+	}
+	rtn := ResourceInstanceList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		resourceTypeURL+"/resource_instances/action",
+		&input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// Match reports whether a resource with the name and tags is selected by the
+// filter, it is used by services which have no server side tag filtering.
+func (f *ResourceTagFilter) Match(name string, tags []Tag) bool {
+	values := map[string]string{}
+	for _, tag := range tags {
+		values[tag.Key] = tag.Value
+	}
+	matchOne := func(filter TagFilter) bool {
+		v, ok := values[filter.Key]
+		if !ok {
+			return false
+		}
+		if len(filter.Values) == 0 {
+			return true
+		}
+		for _, value := range filter.Values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+	for _, filter := range f.Tags {
+		if !matchOne(filter) {
+			return false
+		}
+	}
+	if len(f.NotTags) != 0 {
+		all := true
+		for _, filter := range f.NotTags {
+			all = all && matchOne(filter)
+		}
+		if all {
+			return false
+		}
+	}
+	if len(f.TagsAny) != 0 {
+		any := false
+		for _, filter := range f.TagsAny {
+			any = any || matchOne(filter)
+		}
+		if !any {
+			return false
+		}
+	}
+	for _, filter := range f.NotTagsAny {
+		if matchOne(filter) {
+			return false
+		}
+	}
+	for _, match := range f.Matches {
+		if match.Key == "resource_name" && !strings.Contains(name, match.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceTagFilterMatch(t *testing.T) {
+	tags := []Tag{{Key: "owner", Value: "ci"}, {Key: "env", Value: "test"}}
+	tests := []struct {
+		name   string
+		filter ResourceTagFilter
+		match  bool
+	}{
+		{"empty", ResourceTagFilter{}, true},
+		{"key only", ResourceTagFilter{Tags: []TagFilter{{Key: "owner"}}}, true},
+		{"value", ResourceTagFilter{Tags: []TagFilter{{Key: "owner", Values: []string{"ops", "ci"}}}}, true},
+		{"wrong value", ResourceTagFilter{Tags: []TagFilter{{Key: "owner", Values: []string{"ops"}}}}, false},
+		{"all tags", ResourceTagFilter{Tags: []TagFilter{{Key: "owner"}, {Key: "team"}}}, false},
+		{"any tags", ResourceTagFilter{TagsAny: []TagFilter{{Key: "team"}, {Key: "env"}}}, true},
+		{"not tags needs all", ResourceTagFilter{NotTags: []TagFilter{{Key: "env"}, {Key: "team"}}}, true},
+		{"not tags", ResourceTagFilter{NotTags: []TagFilter{{Key: "env"}, {Key: "owner"}}}, false},
+		{"not tags any", ResourceTagFilter{NotTagsAny: []TagFilter{{Key: "team"}, {Key: "env"}}}, false},
+		{"name", ResourceTagFilter{Matches: []TagMatch{{Key: "resource_name", Value: "ci-"}}}, true},
+		{"wrong name", ResourceTagFilter{Matches: []TagMatch{{Key: "resource_name", Value: "prod"}}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match("ci-cluster", tags); got != tt.match {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.match)
+		}
+	}
+}
+
+// tagRecorder records the requests as "<method> <url> <body>" and answers
+// all of them with response
+type tagRecorder struct {
+	requests []string
+	response interface{}
+}
+
+func (t *tagRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	request := r.Method + " " + r.URL.String()
+	if r.Body != nil {
+		b, _ := ioutil.ReadAll(r.Body)
+		request += " " + string(b)
+	}
+	t.requests = append(t.requests, request)
+	b, _ := json.Marshal(t.response)
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func TestResourceTags(t *testing.T) {
+	recorder := &tagRecorder{}
+	c := NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	c.SetServiceNameFunc(func() string { return "vpc" })
+	c.GetSigner().NextTransport = recorder
+	ctx := context.Background()
+	vpcs := "https://vpc.cn-north-1.myhuawei.com/v2.0/test/vpcs"
+
+	if err := c.CreateResourceTag(ctx, vpcs+"/vpc-1", Tag{Value: "ci"}); err == nil {
+		t.Fatal("expected error without tag key")
+	}
+	if err := c.DeleteResourceTag(ctx, vpcs+"/vpc-1", ""); err == nil {
+		t.Fatal("expected error without tag key")
+	}
+	if err := c.BatchUpdateResourceTags(ctx, vpcs+"/vpc-1", TagActionFilter, nil); err == nil {
+		t.Fatal("expected error for an unknown batch action")
+	}
+	if len(recorder.requests) != 0 {
+		t.Fatalf("invalid input should not be sent: %v", recorder.requests)
+	}
+
+	if err := c.CreateResourceTag(ctx, vpcs+"/vpc-1", Tag{Key: "owner", Value: "ci"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteResourceTag(ctx, vpcs+"/vpc-1", "team/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BatchUpdateResourceTags(ctx, vpcs+"/vpc-1", TagActionDelete, []Tag{{Key: "owner"}, {Key: "env", Value: "test"}}); err != nil {
+		t.Fatal(err)
+	}
+	recorder.response = TagList{Tags: []Tag{{Key: "owner", Value: "ci"}}}
+	tags, err := c.GetResourceTags(ctx, vpcs+"/vpc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Key != "owner" || tags[0].Value != "ci" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	recorder.response = ResourceInstanceList{TotalCount: 1, Resources: []ResourceInstance{{ResourceID: "vpc-1", Tags: tags}}}
+	list, err := c.ListResourcesByTags(ctx, vpcs, &ResourceTagFilter{Tags: []TagFilter{{Key: "owner", Values: []string{"ci"}}}, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 1 || list.Resources[0].ResourceID != "vpc-1" {
+		t.Fatalf("unexpected resources %#v", list)
+	}
+	if _, err := c.ListResourcesByTags(ctx, vpcs, &ResourceTagFilter{Action: TagActionCount}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`POST ` + vpcs + `/vpc-1/tags {"tag":{"key":"owner","value":"ci"}}`,
+		`DELETE ` + vpcs + `/vpc-1/tags/team%2Fa`,
+		`POST ` + vpcs + `/vpc-1/tags/action {"action":"delete","tags":[{"key":"owner","value":""},{"key":"env","value":"test"}]}`,
+		`GET ` + vpcs + `/vpc-1/tags`,
+		`POST ` + vpcs + `/resource_instances/action {"action":"filter","tags":[{"key":"owner","values":["ci"]}],"limit":"10"}`,
+		`POST ` + vpcs + `/resource_instances/action {"action":"count"}`,
+	}
+	if strings.Join(recorder.requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(recorder.requests, "\n"))
+	}
+}
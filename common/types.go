@@ -167,6 +167,8 @@ type Subnet struct {
 	VpcID            string `json:"vpc_id"`            // Specifies the ID of the VPC to which the subnet belongs.
 	Status           string `json:"status"`            // Specifies the status of the subnet.The value can be ACTIVE, DOWN, BUILD, ERROR, or DELETE.
 	NetworkID        string `json:"neutron_network_id"`
+	// NeutronSubnetID is used as vip_subnet_id of shared load balancers
+	NeutronSubnetID string `json:"neutron_subnet_id"`
 }
 
 type NodeConfig struct {
@@ -224,6 +226,7 @@ type SpecInfo struct {
 	Authentication   Authentication        `json:"authentication,omitempty"`
 	HostNetwork      *NetworkInfo          `json:"hostNetwork,omitempty"`
 	ContainerNetwork *ContainerNetworkInfo `json:"containerNetwork,omitempty"`
+	ClusterTags      []Tag                 `json:"clusterTags,omitempty"`
 }
 
 type EndPoints struct {
@@ -323,6 +326,16 @@ type NodeSpecInfo struct {
 	BillingMode     int64        `json:"billingMode,omitempty"`
 	OperationSystem string       `json:"os,omitempty"`
 	ExtendParam     *ExtendParam `json:"extendParam,omitempty"`
+	// Taints and K8sTags (kubernetes labels) are applied to the kubernetes node
+	Taints  []Taint           `json:"taints,omitempty"`
+	K8sTags map[string]string `json:"k8sTags,omitempty"`
+}
+
+type Taint struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	// Effect is NoSchedule, PreferNoSchedule or NoExecute
+	Effect string `json:"effect"`
 }
 
 type NodeStatusInfo struct {
@@ -357,6 +370,7 @@ type ClusterListInfo struct {
 type Cluster struct {
 	Server                   string `json:"server,omitempty"`
 	CertificateAuthorityData string `json:"certificate-authority-data,omitempty"`
+	InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify,omitempty"`
 }
 type ClusterConfig struct {
 	Name    string  `json:"name,omitempty"`
@@ -373,7 +387,7 @@ type UserConfig struct {
 }
 
 type Context struct {
-	Cluster string `json:"context,omitempty"`
+	Cluster string `json:"cluster,omitempty"`
 	User    string `json:"user,omitempty"`
 }
 type ContextConfig struct {
@@ -381,12 +395,21 @@ type ContextConfig struct {
 	Context Context `json:"context,omitempty"`
 }
 
+// ClusterCert is a kubeconfig with the contexts internal, external and
+// externalOTC, see cce.SelectKubeconfig
 type ClusterCert struct {
-	Kind       string          `json:"kind,omitempty"`
-	APIVersion string          `json:"apiVersion,omitempty"`
-	Clusters   []ClusterConfig `json:"clusters,omitempty"`
-	Users      []UserConfig    `json:"users,omitempty"`
-	Contexts   []ContextConfig `json:"contexts,omitempty"`
+	Kind           string          `json:"kind,omitempty"`
+	APIVersion     string          `json:"apiVersion,omitempty"`
+	Clusters       []ClusterConfig `json:"clusters,omitempty"`
+	Users          []UserConfig    `json:"users,omitempty"`
+	Contexts       []ContextConfig `json:"contexts,omitempty"`
+	CurrentContext string          `json:"current-context,omitempty"`
+}
+
+// ClusterCertRequest sets the validity of the client certificate in days, -1
+// is the maximum
+type ClusterCertRequest struct {
+	Duration int64 `json:"duration"`
 }
 
 //PubIP info
@@ -414,12 +437,25 @@ type EipInfo struct {
 	TenantID      string `json:"tenant_id,omitempty"`
 	CreateTime    string `json:"create_time,omitempty"`
 	BandwidthSize uint32 `json:"bandwidth_size,omitempty"`
+	BandwidthID   string `json:"bandwidth_id,omitempty"`
+	PortID        string `json:"port_id,omitempty"`
+	PrivateIPAddr string `json:"private_ip_address,omitempty"`
 }
 
 type EipResp struct {
 	Eip EipInfo `json:"publicip,omitempty"`
 }
 
+type EipListResp struct {
+	Eips []EipInfo `json:"publicips"`
+}
+
+type EipListRequest struct {
+	Marker string `json:"marker,omitempty"`
+	Limit  int64  `json:"limit,omitempty"`
+	PortID string `json:"port_id,omitempty"`
+}
+
 //FixedIP Port info
 type FixedIP struct {
 	SubnetID  string `json:"subnet_id,omitempty"`
@@ -438,6 +474,8 @@ type Ports struct {
 
 type PortDesc struct {
 	PortID string `json:"port_id,omitempty"`
+	// ClearPortID sends port_id as null which unbinds the EIP
+	ClearPortID bool `json:"-"`
 }
 
 type EipAssocArg struct {
@@ -472,6 +510,7 @@ type JobInfo struct {
 	Status     JobStatus   `json:"status,omitempty"`
 }
 
+// the v3 api takes the same request at /api/v3/projects/<project_id>/clusters/<cluster_id>/mastereip, see cce.BindMasterEIP
 //put https://console.huaweicloud.com/cce2.0/rest/cce/api/v2/projects/<project_id>/clusters/<cluster_id>/mastereip
 //with HEADER region: cn-north-1
 /* request
@@ -529,22 +568,28 @@ type BindInfoStatus struct {
 	PublicEndpoint  string `json:"publicEndpoint,omitempty"`
 }
 
+// LoadbalancerObject is a shared load balancer. It is always created with a
+// private VIP in VipSubnetID (the neutron subnet id of a VPC subnet), such an
+// internal load balancer is made external by binding an EIP to VipPortID.
 type LoadbalancerObject struct {
 	UpdatableLoadBalancerAttribute
-	ID          string `json:"id,omitempty"`
-	CreateAt    string `json:"create_at,omitempty"`
-	UpdateAt    string `json:"update_at,omitempty"`
-	ProjectId   string `json:"project_id,omitempty"`
-	TenantID    string `json:"tenant_id,omitempty"`
-	VipSubnetID string `json:"vip_subnet_id"`
-	VIPAddress  string `json:"vip_address,omitempty"`
-	VipPortID   string `json:"vip_port_id,omitempty"`
-	Listeners   []struct {
-		ID string `json:"id,omitempty"`
-	} `json:"listeners,omitempty"`
-	Pools []struct {
-		ID string `json:"id,omitempty"`
-	} `json:"pools,omitempty"`
+	ID                  string `json:"id,omitempty"`
+	CreateAt            string `json:"created_at,omitempty"`
+	UpdateAt            string `json:"updated_at,omitempty"`
+	ProjectId           string `json:"project_id,omitempty"`
+	TenantID            string `json:"tenant_id,omitempty"`
+	EnterpriseProjectID string `json:"enterprise_project_id,omitempty"`
+	VipSubnetID         string `json:"vip_subnet_id"`
+	VIPAddress          string `json:"vip_address,omitempty"`
+	VipPortID           string `json:"vip_port_id,omitempty"`
+	// Provider is vlb, the only provider of shared load balancers
+	Provider string `json:"provider,omitempty"`
+	// ProvisioningStatus is ACTIVE, PENDING_CREATE or ERROR
+	ProvisioningStatus string `json:"provisioning_status,omitempty"`
+	// OperatingStatus is ONLINE or FROZEN
+	OperatingStatus string           `json:"operating_status,omitempty"`
+	Listeners       []ELBResourceRef `json:"listeners,omitempty"`
+	Pools           []ELBResourceRef `json:"pools,omitempty"`
 }
 
 type LoadBalancerRequest struct {
@@ -557,10 +602,14 @@ type LoadBalancerJobInfo struct {
 }
 
 type UpdatableLoadBalancerAttribute struct {
-	//AdminStateUp int32  `json:"admin_state_up,omitempty"`
-	//Bandwidth    int64  `json:"bandwidth,omitempty"`
-	Description string `json:"description,omitempty"`
-	Name        string `json:"name,omitempty"`
+	// AdminStateUp can only be true for shared load balancers
+	AdminStateUp *bool  `json:"admin_state_up,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type LoadBalancerUpdateRequest struct {
+	Loadbalancer UpdatableLoadBalancerAttribute `json:"loadbalancer"`
 }
 
 type LoadBalancerInfo struct {
@@ -568,8 +617,21 @@ type LoadBalancerInfo struct {
 }
 
 type LoadBalancerList struct {
-	LoadBalancers []LoadBalancerInfo `json:"loadbalancers,omitempty"`
-	InstanceNum   string             `json:"instance_num,omitempty"`
+	LoadBalancers      []LoadbalancerObject `json:"loadbalancers,omitempty"`
+	LoadBalancersLinks []Link               `json:"loadbalancers_links,omitempty"`
+}
+
+type LoadBalancerListRequest struct {
+	Marker             string `json:"marker,omitempty"`
+	Limit              int64  `json:"limit,omitempty"`
+	PageReverse        bool   `json:"page_reverse,omitempty"`
+	ID                 string `json:"id,omitempty"`
+	Name               string `json:"name,omitempty"`
+	VipSubnetID        string `json:"vip_subnet_id,omitempty"`
+	VipAddress         string `json:"vip_address,omitempty"`
+	VipPortID          string `json:"vip_port_id,omitempty"`
+	ProvisioningStatus string `json:"provisioning_status,omitempty"`
+	OperatingStatus    string `json:"operating_status,omitempty"`
 }
 
 type ELBListenerRequestObject struct {
@@ -588,6 +650,10 @@ type ELBListenerRequestObject struct {
 	ClientCaTlsContainerRef string   `json:"client_ca_tls_container_ref,omitempty"`
 	SniContainerRefs        []string `json:"sni_container_refs,omitempty"`
 	TlsCiphersPolicy        string   `json:"tls_ciphers_policy,omitempty"`
+	// ClearDefaultTlsContainerRef sends default_tls_container_ref as null which removes the certificate
+	ClearDefaultTlsContainerRef bool `json:"-"`
+	// ClearSniContainerRefs sends sni_container_refs as an empty list which removes all SNI certificates
+	ClearSniContainerRefs bool `json:"-"`
 }
 
 type ELBListenerRequest struct {
@@ -615,7 +681,37 @@ type ELBListenerInfoObject struct {
 
 	ELBListenerCommon
 	UpdatableELBListenerAttribute
-	HealthcheckID *string `json:"healthcheck_id,omitempty"`
+	HealthcheckID          *string  `json:"healthcheck_id,omitempty"`
+	DefaultTlsContainerRef string   `json:"default_tls_container_ref,omitempty"`
+	SniContainerRefs       []string `json:"sni_container_refs,omitempty"`
+
+	// fields of shared load balancer listeners
+	ProtocolPort    int64            `json:"protocol_port,omitempty"`
+	Loadbalancers   []ELBResourceRef `json:"loadbalancers,omitempty"`
+	DefaultPoolID   string           `json:"default_pool_id,omitempty"`
+	ConnectionLimit int64            `json:"connection_limit,omitempty"`
+	AdminStateUp    *bool            `json:"admin_state_up,omitempty"`
+	Http2Enable     bool             `json:"http2_enable,omitempty"`
+}
+
+type ELBListenerUpdateRequest struct {
+	Listener ELBListenerUpdateObject `json:"listener"`
+}
+
+type ELBListenerUpdateObject struct {
+	Name                    string   `json:"name,omitempty"`
+	Description             string   `json:"description,omitempty"`
+	ConnectionLimit         int64    `json:"connection_limit,omitempty"`
+	Http2Enable             *bool    `json:"http2_enable,omitempty"`
+	DefaultPoolId           string   `json:"default_pool_id,omitempty"`
+	DefaultTlsContainerRef  string   `json:"default_tls_container_ref,omitempty"`
+	ClientCaTlsContainerRef string   `json:"client_ca_tls_container_ref,omitempty"`
+	SniContainerRefs        []string `json:"sni_container_refs,omitempty"`
+	TlsCiphersPolicy        string   `json:"tls_ciphers_policy,omitempty"`
+	// ClearDefaultTlsContainerRef sends default_tls_container_ref as null which removes the certificate
+	ClearDefaultTlsContainerRef bool `json:"-"`
+	// ClearSniContainerRefs sends sni_container_refs as an empty list which removes all SNI certificates
+	ClearSniContainerRefs bool `json:"-"`
 }
 
 type UpdatableELBListenerAttribute struct {
@@ -635,7 +731,8 @@ type UpdatableELBListenerAttribute struct {
 }
 
 type ELBListenerList struct {
-	Listeners []ELBListenerInfo `json:"listeners"`
+	Listeners      []ELBListenerInfoObject `json:"listeners"`
+	ListenersLinks []Link                  `json:"listeners_links,omitempty"`
 }
 
 type ELBHealthCheckRequest struct {
@@ -707,29 +804,38 @@ type ELBBackendGroupDetails struct {
 }
 
 type ELBBackendGroupListItem struct {
-	ID          string `json:"id,omitempty"`
-	TenantID    string `json:"tenant_id,omitempty"`
-	ProjectID   string `json:"project_id,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Potocol     string `json:"potocol,omitempty"`
-	LbAlgorithm string `json:"lb_algorithm,omitempty"`
-	Members     []struct {
-		ID string `json:"id,omitempty"`
-	} `json:"members,omitempty"`
-	HealthmonitorID string `json:"healthmonitor_id,omitempty"`
-	AdminStateUp    bool   `json:"admin_state_up,omitempty"`
-	Listeners       []struct {
-		ID string `json:"id,omitempty"`
-	} `json:"listeners,omitempty"`
-	Loadbalancers []struct {
-		ID string `json:"id,omitempty"`
-	} `json:"loadbalancers,omitempty"`
+	ID                 string             `json:"id,omitempty"`
+	TenantID           string             `json:"tenant_id,omitempty"`
+	ProjectID          string             `json:"project_id,omitempty"`
+	Name               string             `json:"name,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	Protocol           string             `json:"protocol,omitempty"`
+	LbAlgorithm        string             `json:"lb_algorithm,omitempty"`
+	Members            []ELBResourceRef   `json:"members,omitempty"`
+	HealthmonitorID    string             `json:"healthmonitor_id,omitempty"`
+	AdminStateUp       bool               `json:"admin_state_up,omitempty"`
+	Listeners          []ELBResourceRef   `json:"listeners,omitempty"`
+	Loadbalancers      []ELBResourceRef   `json:"loadbalancers,omitempty"`
 	SessionPersistence SessionPersistence `json:"session_persistence,omitempty"`
-	PoolsLinks         []struct {
-		Href string `json:"href"`
-		Rel  string `json:"rel"`
-	} `json:"pools_links,omitempty"`
+}
+
+type ELBBackendGroupList struct {
+	Pools      []ELBBackendGroupListItem `json:"pools"`
+	PoolsLinks []Link                    `json:"pools_links,omitempty"`
+}
+
+type ELBBackendGroupUpdateRequest struct {
+	Pool ELBBackendGroupUpdate `json:"pool"`
+}
+
+type ELBBackendGroupUpdate struct {
+	Name               string              `json:"name,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	LbAlgorithm        string              `json:"lb_algorithm,omitempty"`
+	AdminStateUp       *bool               `json:"admin_state_up,omitempty"`
+	SessionPersistence *SessionPersistence `json:"session_persistence,omitempty"`
+	// ClearSessionPersistence sends session_persistence as null which turns it off
+	ClearSessionPersistence bool `json:"-"`
 }
 
 type ELBBackendGroupObject struct {
@@ -737,6 +843,7 @@ type ELBBackendGroupObject struct {
 }
 
 type ELBBackend struct {
+	ID           string `json:"id,omitempty"`
 	TenantID     string `json:"tenant_id,omitempty"`
 	ProjectID    string `json:"project_id,omitempty"`
 	Name         string `json:"name,omitempty"`
@@ -744,7 +851,8 @@ type ELBBackend struct {
 	ProtocolPort int64  `json:"protocol_port,"`
 	SubnetID     string `json:"subnet_id,"`
 	AdminStateUp bool   `json:"admin_state_up,omitempty"`
-	Weight       int64  `json:"weight,omitempty"`
+	// Weight defaults to 1 when it is nil, 0 sends no new connections to the member
+	Weight *int64 `json:"weight,omitempty"`
 }
 
 type ELBBackendRequest struct {
@@ -768,16 +876,382 @@ type ELBBackendMember struct {
 	OperatingStatus string `json:"operating_status,omitempty"`
 }
 
+type ELBBackendListRequest struct {
+	Marker          string `json:"marker,omitempty"`
+	Limit           int64  `json:"limit,omitempty"`
+	PageReverse     bool   `json:"page_reverse,omitempty"`
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Address         string `json:"address,omitempty"`
+	ProtocolPort    int64  `json:"protocol_port,omitempty"`
+	SubnetID        string `json:"subnet_id,omitempty"`
+	AdminStateUp    *bool  `json:"admin_state_up,omitempty"`
+	Weight          int64  `json:"weight,omitempty"`
+	OperatingStatus string `json:"operating_status,omitempty"`
+}
+
+type ELBBackendMemberList struct {
+	Members      []ELBBackendMember `json:"members"`
+	MembersLinks []Link             `json:"members_links,omitempty"`
+}
+
+type ELBBackendMemberDetails struct {
+	Member ELBBackendMember `json:"member"`
+}
+
+type ELBBackendUpdateRequest struct {
+	Member ELBBackendUpdate `json:"member"`
+}
+
+type ELBBackendUpdate struct {
+	Name         string `json:"name,omitempty"`
+	Weight       *int64 `json:"weight,omitempty"`
+	AdminStateUp *bool  `json:"admin_state_up,omitempty"`
+}
+
+//ELBResourceRef reference to another elb resource
+type ELBResourceRef struct {
+	ID string `json:"id"`
+}
+
+type ELBHealthmonitorRequest struct {
+	Healthmonitor ELBHealthmonitor `json:"healthmonitor"`
+}
+
+type ELBHealthmonitorDetails struct {
+	Healthmonitor ELBHealthmonitor `json:"healthmonitor"`
+}
+
+type ELBHealthmonitor struct {
+	ID            string           `json:"id,omitempty"`
+	TenantID      string           `json:"tenant_id,omitempty"`
+	ProjectID     string           `json:"project_id,omitempty"`
+	Name          string           `json:"name,omitempty"`
+	PoolID        string           `json:"pool_id,omitempty"` // only used on creation
+	Type          string           `json:"type,omitempty"`    // TCP/UDP_CONNECT/HTTP
+	Delay         int64            `json:"delay,omitempty"`
+	Timeout       int64            `json:"timeout,omitempty"`
+	MaxRetries    int64            `json:"max_retries,omitempty"`
+	URLPath       string           `json:"url_path,omitempty"`
+	HTTPMethod    string           `json:"http_method,omitempty"`
+	ExpectedCodes string           `json:"expected_codes,omitempty"` // 200, 200,202 or 200-204
+	MonitorPort   int64            `json:"monitor_port,omitempty"`
+	DomainName    string           `json:"domain_name,omitempty"`
+	AdminStateUp  *bool            `json:"admin_state_up,omitempty"`
+	Pools         []ELBResourceRef `json:"pools,omitempty"`
+}
+
+type ELBHealthmonitorList struct {
+	Healthmonitors      []ELBHealthmonitor `json:"healthmonitors"`
+	HealthmonitorsLinks []Link             `json:"healthmonitors_links,omitempty"`
+}
+
+type ELBHealthmonitorListRequest struct {
+	Marker        string `json:"marker,omitempty"`
+	Limit         int64  `json:"limit,omitempty"`
+	PageReverse   bool   `json:"page_reverse,omitempty"`
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Delay         int64  `json:"delay,omitempty"`
+	Timeout       int64  `json:"timeout,omitempty"`
+	MaxRetries    int64  `json:"max_retries,omitempty"`
+	MonitorPort   int64  `json:"monitor_port,omitempty"`
+	URLPath       string `json:"url_path,omitempty"`
+	HTTPMethod    string `json:"http_method,omitempty"`
+	ExpectedCodes string `json:"expected_codes,omitempty"`
+	DomainName    string `json:"domain_name,omitempty"`
+}
+
+type ELBHealthmonitorUpdateRequest struct {
+	Healthmonitor ELBHealthmonitorUpdate `json:"healthmonitor"`
+}
+
+type ELBHealthmonitorUpdate struct {
+	Name          string `json:"name,omitempty"`
+	Delay         int64  `json:"delay,omitempty"`
+	Timeout       int64  `json:"timeout,omitempty"`
+	MaxRetries    int64  `json:"max_retries,omitempty"`
+	URLPath       string `json:"url_path,omitempty"`
+	HTTPMethod    string `json:"http_method,omitempty"`
+	ExpectedCodes string `json:"expected_codes,omitempty"`
+	MonitorPort   *int64 `json:"monitor_port,omitempty"` // 1-65535
+	DomainName    string `json:"domain_name,omitempty"`
+	AdminStateUp  *bool  `json:"admin_state_up,omitempty"`
+	// ClearMonitorPort sends monitor_port as null which checks the port of the members again
+	ClearMonitorPort bool `json:"-"`
+}
+
+type ELBL7PolicyRequest struct {
+	L7Policy ELBL7Policy `json:"l7policy"`
+}
+
+type ELBL7PolicyDetails struct {
+	L7Policy ELBL7Policy `json:"l7policy"`
+}
+
+type ELBL7Policy struct {
+	ID                 string           `json:"id,omitempty"`
+	TenantID           string           `json:"tenant_id,omitempty"`
+	ProjectID          string           `json:"project_id,omitempty"`
+	Name               string           `json:"name,omitempty"`
+	Description        string           `json:"description,omitempty"`
+	ListenerID         string           `json:"listener_id,omitempty"`
+	Action             string           `json:"action,omitempty"` // REDIRECT_TO_POOL/REDIRECT_TO_LISTENER
+	RedirectPoolID     string           `json:"redirect_pool_id,omitempty"`
+	RedirectListenerID string           `json:"redirect_listener_id,omitempty"`
+	Position           int64            `json:"position,omitempty"`
+	AdminStateUp       *bool            `json:"admin_state_up,omitempty"`
+	ProvisioningStatus string           `json:"provisioning_status,omitempty"`
+	Rules              []ELBResourceRef `json:"rules,omitempty"`
+}
+
+type ELBL7PolicyList struct {
+	L7Policies      []ELBL7Policy `json:"l7policies"`
+	L7PoliciesLinks []Link        `json:"l7policies_links,omitempty"`
+}
+
+type ELBL7PolicyListRequest struct {
+	Marker             string `json:"marker,omitempty"`
+	Limit              int64  `json:"limit,omitempty"`
+	PageReverse        bool   `json:"page_reverse,omitempty"`
+	ID                 string `json:"id,omitempty"`
+	Name               string `json:"name,omitempty"`
+	ListenerID         string `json:"listener_id,omitempty"`
+	Action             string `json:"action,omitempty"`
+	RedirectPoolID     string `json:"redirect_pool_id,omitempty"`
+	RedirectListenerID string `json:"redirect_listener_id,omitempty"`
+	Position           int64  `json:"position,omitempty"`
+}
+
+type ELBL7PolicyUpdateRequest struct {
+	L7Policy ELBL7PolicyUpdate `json:"l7policy"`
+}
+
+type ELBL7PolicyUpdate struct {
+	Name               string `json:"name,omitempty"`
+	Description        string `json:"description,omitempty"`
+	RedirectPoolID     string `json:"redirect_pool_id,omitempty"`
+	RedirectListenerID string `json:"redirect_listener_id,omitempty"`
+	Position           int64  `json:"position,omitempty"`
+	AdminStateUp       *bool  `json:"admin_state_up,omitempty"`
+}
+
+type ELBL7RuleRequest struct {
+	Rule ELBL7Rule `json:"rule"`
+}
+
+type ELBL7RuleDetails struct {
+	Rule ELBL7Rule `json:"rule"`
+}
+
+type ELBL7Rule struct {
+	ID                 string `json:"id,omitempty"`
+	TenantID           string `json:"tenant_id,omitempty"`
+	ProjectID          string `json:"project_id,omitempty"`
+	Type               string `json:"type,omitempty"`         // HOST_NAME/PATH
+	CompareType        string `json:"compare_type,omitempty"` // EQUAL_TO/REGEX/STARTS_WITH
+	Value              string `json:"value,omitempty"`
+	Key                string `json:"key,omitempty"`
+	Invert             bool   `json:"invert,omitempty"`
+	AdminStateUp       *bool  `json:"admin_state_up,omitempty"`
+	ProvisioningStatus string `json:"provisioning_status,omitempty"`
+}
+
+type ELBL7RuleList struct {
+	Rules      []ELBL7Rule `json:"rules"`
+	RulesLinks []Link      `json:"rules_links,omitempty"`
+}
+
+type ELBL7RuleListRequest struct {
+	Marker      string `json:"marker,omitempty"`
+	Limit       int64  `json:"limit,omitempty"`
+	PageReverse bool   `json:"page_reverse,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Type        string `json:"type,omitempty"`
+	CompareType string `json:"compare_type,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+type ELBL7RuleUpdateRequest struct {
+	Rule ELBL7RuleUpdate `json:"rule"`
+}
+
+type ELBL7RuleUpdate struct {
+	CompareType  string `json:"compare_type,omitempty"`
+	Value        string `json:"value,omitempty"`
+	Key          string `json:"key,omitempty"`
+	Invert       *bool  `json:"invert,omitempty"`
+	AdminStateUp *bool  `json:"admin_state_up,omitempty"`
+}
+
+type ELBWhitelistRequest struct {
+	Whitelist ELBWhitelist `json:"whitelist"`
+}
+
+type ELBWhitelistDetails struct {
+	Whitelist ELBWhitelist `json:"whitelist"`
+}
+
+type ELBWhitelist struct {
+	ID              string   `json:"id,omitempty"`
+	TenantID        string   `json:"tenant_id,omitempty"`
+	ProjectID       string   `json:"project_id,omitempty"`
+	ListenerID      string   `json:"listener_id,omitempty"`
+	EnableWhitelist *bool    `json:"enable_whitelist,omitempty"`
+	Whitelist       CIDRList `json:"whitelist"`
+}
+
+type ELBWhitelistList struct {
+	Whitelists      []ELBWhitelist `json:"whitelists"`
+	WhitelistsLinks []Link         `json:"whitelists_links,omitempty"`
+}
+
+type ELBWhitelistListRequest struct {
+	Marker          string `json:"marker,omitempty"`
+	Limit           int64  `json:"limit,omitempty"`
+	PageReverse     bool   `json:"page_reverse,omitempty"`
+	ID              string `json:"id,omitempty"`
+	ListenerID      string `json:"listener_id,omitempty"`
+	EnableWhitelist *bool  `json:"enable_whitelist,omitempty"`
+}
+
+type ELBWhitelistUpdateRequest struct {
+	Whitelist ELBWhitelistUpdate `json:"whitelist"`
+}
+
+type ELBWhitelistUpdate struct {
+	EnableWhitelist *bool     `json:"enable_whitelist,omitempty"`
+	Whitelist       *CIDRList `json:"whitelist,omitempty"`
+}
+
+type ELBStatuses struct {
+	Statuses struct {
+		Loadbalancer ELBLoadBalancerStatus `json:"loadbalancer"`
+	} `json:"statuses"`
+}
+
+// ELBLoadBalancerStatus is the status tree of a load balancer
+type ELBLoadBalancerStatus struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name,omitempty"`
+	ProvisioningStatus string              `json:"provisioning_status"`
+	OperatingStatus    string              `json:"operating_status,omitempty"`
+	Listeners          []ELBListenerStatus `json:"listeners,omitempty"`
+	Pools              []ELBPoolStatus     `json:"pools,omitempty"`
+}
+
+type ELBListenerStatus struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name,omitempty"`
+	ProvisioningStatus string              `json:"provisioning_status"`
+	OperatingStatus    string              `json:"operating_status,omitempty"`
+	Pools              []ELBPoolStatus     `json:"pools,omitempty"`
+	L7Policies         []ELBL7PolicyStatus `json:"l7policies,omitempty"`
+}
+
+type ELBPoolStatus struct {
+	ID                 string                  `json:"id"`
+	Name               string                  `json:"name,omitempty"`
+	ProvisioningStatus string                  `json:"provisioning_status"`
+	OperatingStatus    string                  `json:"operating_status,omitempty"`
+	Healthmonitor      *ELBHealthmonitorStatus `json:"healthmonitor,omitempty"`
+	Members            []ELBMemberStatus       `json:"members,omitempty"`
+}
+
+type ELBHealthmonitorStatus struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name,omitempty"`
+	Type               string `json:"type,omitempty"`
+	ProvisioningStatus string `json:"provisioning_status"`
+}
+
+type ELBMemberStatus struct {
+	ID                 string `json:"id"`
+	Address            string `json:"address,omitempty"`
+	ProtocolPort       int64  `json:"protocol_port,omitempty"`
+	ProvisioningStatus string `json:"provisioning_status"`
+	OperatingStatus    string `json:"operating_status,omitempty"`
+}
+
+type ELBL7PolicyStatus struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name,omitempty"`
+	Action             string            `json:"action,omitempty"`
+	ProvisioningStatus string            `json:"provisioning_status"`
+	Rules              []ELBL7RuleStatus `json:"rules,omitempty"`
+}
+
+type ELBL7RuleStatus struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type,omitempty"`
+	ProvisioningStatus string `json:"provisioning_status"`
+}
+
+//Link pagination link of list responses
+type Link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"` // next/previous
+}
+
 type ELBQuotaList struct {
-	Quotas *struct {
-		Resources []struct {
-			Type  string `json:"type,omitempty"`
-			Used  int64  `json:"used,omitempty"`
-			Quota int64  `json:"quota,omitempty"`
-			Max   int64  `json:"max,omitempty"`
-			Min   int64  `json:"min,omitempty"`
-		} `json:"resources,omitempty"`
-	} `json:"quotas,omitempty"`
+	Quotas *QuotaResources `json:"quotas,omitempty"`
+}
+
+type VPCQuotaList struct {
+	Quotas *QuotaResources `json:"quotas,omitempty"`
+}
+
+type QuotaResources struct {
+	Resources []QuotaResource `json:"resources,omitempty"`
+}
+
+//QuotaResource a quota of -1 means unlimited
+type QuotaResource struct {
+	Type  string `json:"type,omitempty"`
+	Used  int64  `json:"used,omitempty"`
+	Quota int64  `json:"quota,omitempty"`
+	Max   int64  `json:"max,omitempty"`
+	Min   int64  `json:"min,omitempty"`
+}
+
+type CCEQuotaList struct {
+	Quotas []CCEQuota `json:"quotas,omitempty"`
+}
+
+type CCEQuota struct {
+	QuotaKey   string `json:"quotaKey,omitempty"`
+	QuotaLimit int64  `json:"quotaLimit,omitempty"`
+	Used       int64  `json:"used,omitempty"`
+	Unit       string `json:"unit,omitempty"`
+}
+
+type ECSLimits struct {
+	Limits struct {
+		Absolute ECSAbsoluteLimits `json:"absolute"`
+	} `json:"limits"`
+}
+
+//ECSAbsoluteLimits a limit of -1 means unlimited, RAM is in MB
+type ECSAbsoluteLimits struct {
+	MaxTotalInstances  int64 `json:"maxTotalInstances"`
+	TotalInstancesUsed int64 `json:"totalInstancesUsed"`
+	MaxTotalCores      int64 `json:"maxTotalCores"`
+	TotalCoresUsed     int64 `json:"totalCoresUsed"`
+	MaxTotalRAMSize    int64 `json:"maxTotalRAMSize"`
+	TotalRAMUsed       int64 `json:"totalRAMUsed"`
+}
+
+type ECSFlavorDetails struct {
+	Flavor ECSFlavor `json:"flavor"`
+}
+
+//ECSFlavor RAM is in MB
+type ECSFlavor struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	VCPUs int64  `json:"vcpus"`
+	RAM   int64  `json:"ram"`
 }
 
 type ELBCertificateRequest struct {
@@ -786,6 +1260,7 @@ type ELBCertificateRequest struct {
 }
 
 type ELBCertificateCommon struct {
+	Type        string `json:"type,omitempty"` // server/client, default to server
 	Domain      string `json:"domain,omitempty"`
 	Certificate string `json:"certificate,omitempty"`
 	PrivateKey  string `json:"private_key,omitempty"`
@@ -800,14 +1275,27 @@ type ELBCertificateInfo struct {
 	ID         string `json:"id,omitempty"`
 	CreateTime string `json:"create_time,omitempty"`
 	UpdateTime string `json:"update_time,omitempty"`
+	ExpireTime string `json:"expire_time,omitempty"`
+	AdminState bool   `json:"admin_state_up,omitempty"`
 
 	UpdatableELBCertificateAttribute
 	ELBCertificateCommon
 }
 
 type ELBCertificateList struct {
-	Certificates []ELBCertificateInfo `json:"certificates,omitempty"`
-	InstanceNum  string               `json:"instance_num,omitempty"`
+	Certificates      []ELBCertificateInfo `json:"certificates,omitempty"`
+	CertificatesLinks []Link               `json:"certificates_links,omitempty"`
+	InstanceNum       json.Number          `json:"instance_num,omitempty"`
+}
+
+type ELBCertificateListRequest struct {
+	Marker      string `json:"marker,omitempty"`
+	Limit       int64  `json:"limit,omitempty"`
+	PageReverse bool   `json:"page_reverse,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	Type        string `json:"type,omitempty"`
 }
 
 type JobInfoV1 struct {
@@ -835,3 +1323,55 @@ type PrivateIp struct {
 	DeviceOwner string `json:"device_owner,omitempty"`
 	IpAddress   string `json:"ip_address,omitempty"`
 }
+
+//Tag resource tag shared by vpc, eip, elb and cce
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type TagRequest struct {
+	Tag Tag `json:"tag"`
+}
+
+type BatchTagRequest struct {
+	Action string `json:"action"` // create/delete
+	Tags   []Tag  `json:"tags"`
+}
+
+type TagList struct {
+	Tags []Tag `json:"tags"`
+}
+
+type TagFilter struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"` // empty values match any value of the key
+}
+
+type TagMatch struct {
+	Key   string `json:"key"` // resource_name
+	Value string `json:"value"`
+}
+
+type ResourceTagFilter struct {
+	Action     string      `json:"action"` // filter/count
+	Tags       []TagFilter `json:"tags,omitempty"`
+	TagsAny    []TagFilter `json:"tags_any,omitempty"`
+	NotTags    []TagFilter `json:"not_tags,omitempty"`
+	NotTagsAny []TagFilter `json:"not_tags_any,omitempty"`
+	Limit      int64       `json:"limit,string,omitempty"`
+	Offset     int64       `json:"offset,string,omitempty"`
+	Matches    []TagMatch  `json:"matches,omitempty"`
+}
+
+type ResourceInstance struct {
+	ResourceID     string          `json:"resource_id"`
+	ResourceName   string          `json:"resource_name,omitempty"`
+	ResourceDetail json.RawMessage `json:"resource_detail,omitempty"`
+	Tags           []Tag           `json:"tags"`
+}
+
+type ResourceInstanceList struct {
+	Resources  []ResourceInstance `json:"resources,omitempty"`
+	TotalCount int64              `json:"total_count"`
+}
@@ -0,0 +1,132 @@
+package common
+
+// Models of the CCE cluster upgrade api served under
+// /clusters/{cluster_id}/upgradeinfo and /clusters/{cluster_id}/operation.
+// Upgrade and pre-check tasks have the phases Init, Queuing, Running, Pause,
+// Success and Failed, see JobPhaseDone.
+
+type UpgradeVersionInfo struct {
+	Release        string   `json:"release"`
+	Patch          string   `json:"patch"`
+	SuggestPatch   string   `json:"suggestPatch,omitempty"`
+	TargetVersions []string `json:"targetVersions"`
+}
+
+type UpgradeInfoSpec struct {
+	LastUpgradeInfo *UpgradeTaskSummary `json:"lastUpgradeInfo,omitempty"`
+	VersionInfo     UpgradeVersionInfo  `json:"versionInfo"`
+}
+
+type UpgradeTaskSummary struct {
+	Phase          string `json:"phase,omitempty"`
+	Progress       string `json:"progress,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+type UpgradeInfo struct {
+	Kind       string             `json:"kind,omitempty"`
+	APIVersion string             `json:"apiVersion,omitempty"`
+	Spec       UpgradeInfoSpec    `json:"spec"`
+	Status     UpgradeTaskSummary `json:"status"`
+}
+
+type UpgradeTaskMetaData struct {
+	UID               string `json:"uid,omitempty"`
+	Name              string `json:"name,omitempty"`
+	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+	UpdateTimestamp   string `json:"updateTimestamp,omitempty"`
+}
+
+type RollingUpdateStrategy struct {
+	// UserDefinedStep is the number of nodes upgraded in one batch
+	UserDefinedStep int64 `json:"userDefinedStep,omitempty"`
+}
+
+type UpgradeStrategy struct {
+	// Type is inPlaceRollingUpdate or rollingUpdate
+	Type                 string                 `json:"type"`
+	InPlaceRollingUpdate *RollingUpdateStrategy `json:"inPlaceRollingUpdate,omitempty"`
+	RollingUpdate        *RollingUpdateStrategy `json:"rollingUpdate,omitempty"`
+}
+
+type UpgradeAddon struct {
+	AddonTemplateName string                 `json:"addonTemplateName"`
+	Operation         string                 `json:"operation"`
+	Version           string                 `json:"version"`
+	Values            map[string]interface{} `json:"values,omitempty"`
+}
+
+type ClusterUpgradeAction struct {
+	TargetVersion string           `json:"targetVersion"`
+	Strategy      *UpgradeStrategy `json:"strategy,omitempty"`
+	Addons        []UpgradeAddon   `json:"addons,omitempty"`
+	// NodePoolOrder upgrades the node pools by priority, higher goes first
+	NodePoolOrder map[string]int64 `json:"nodePoolOrder,omitempty"`
+}
+
+type UpgradeTaskRequestSpec struct {
+	ClusterUpgradeAction ClusterUpgradeAction `json:"clusterUpgradeAction"`
+}
+
+type UpgradeTaskRequest struct {
+	Kind       string                 `json:"kind"`
+	APIVersion string                 `json:"apiVersion"`
+	Spec       UpgradeTaskRequestSpec `json:"spec"`
+}
+
+// UpgradeNodeProgress is the progress of one node of an upgrade task
+type UpgradeNodeProgress struct {
+	NodeID   string `json:"nodeID"`
+	NodeName string `json:"nodeName,omitempty"`
+	Phase    string `json:"phase"`
+	Progress string `json:"progress,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type UpgradeTaskSpec struct {
+	Version       string `json:"version,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+}
+
+type UpgradeTaskStatus struct {
+	Phase          string                `json:"phase,omitempty"`
+	Progress       string                `json:"progress,omitempty"`
+	Message        string                `json:"message,omitempty"`
+	CompletionTime string                `json:"completionTime,omitempty"`
+	Nodes          []UpgradeNodeProgress `json:"nodes,omitempty"`
+}
+
+type UpgradeTask struct {
+	Kind       string              `json:"kind,omitempty"`
+	APIVersion string              `json:"apiVersion,omitempty"`
+	MetaData   UpgradeTaskMetaData `json:"metadata"`
+	Spec       UpgradeTaskSpec     `json:"spec"`
+	Status     UpgradeTaskStatus   `json:"status"`
+}
+
+type UpgradeTaskList struct {
+	Items []UpgradeTask `json:"items"`
+}
+
+// UpgradeCheckResult is one check of a pre-check task, Kind is cluster, node or addon
+type UpgradeCheckResult struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type PrecheckTaskStatus struct {
+	Phase   string               `json:"phase,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Results []UpgradeCheckResult `json:"results,omitempty"`
+}
+
+type PrecheckTask struct {
+	Kind       string              `json:"kind,omitempty"`
+	APIVersion string              `json:"apiVersion,omitempty"`
+	MetaData   UpgradeTaskMetaData `json:"metadata"`
+	Spec       UpgradeTaskSpec     `json:"spec"`
+	Status     PrecheckTaskStatus  `json:"status"`
+}
@@ -0,0 +1,36 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// Client talks to the OpenStack compatible /v2.1 api of ECS
+type Client struct {
+	common.Client
+}
+
+func NewClient(baseClient *common.Client) *Client {
+	c := &Client{
+		Client: *baseClient,
+	}
+	c.GetBaseURLFunc = c.GetBaseURL
+	c.SetServiceNameFunc(serviceName)
+	c.GetAPIPrefixFunc = prefix
+	c.GetAPIEndpointFunc = c.GetAPIEndpoint
+	c.GetAPIHostnameFunc = c.GetAPIHostname
+	return c
+}
+
+func (c *Client) GetBaseURL() string {
+	return fmt.Sprintf("%s%s/%s", c.GetAPIEndpointFunc(), c.GetAPIPrefixFunc(), c.ProjectID)
+}
+
+func serviceName() string {
+	return "ecs"
+}
+
+func prefix() string {
+	return "/v2.1"
+}
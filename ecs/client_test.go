@@ -0,0 +1,15 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func Test_GetURL(t *testing.T) {
+	baseClient := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	c := NewClient(baseClient)
+	if c.GetURL("limits") != "https://ecs.cn-north-1.myhuawei.com/v2.1/test/limits" {
+		t.Fatal("get url is not valid", c.GetURL("limits"))
+	}
+}
@@ -0,0 +1,42 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// GetLimits returns the instance, vCPU and RAM quotas of the project and their usage
+func (c *Client) GetLimits(ctx context.Context) (*common.ECSAbsoluteLimits, error) {
+	rtn := common.ECSLimits{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("limits"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Limits.Absolute, nil
+}
+
+// GetFlavor returns the vCPUs and RAM of a flavor such as s3.large.2
+func (c *Client) GetFlavor(ctx context.Context, id string) (*common.ECSFlavor, error) {
+	if id == "" {
+		return nil, errors.New("[GetFlavor]flavor id is required")
+	}
+	rtn := common.ECSFlavorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("flavors", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Flavor, nil
+}
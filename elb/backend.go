@@ -55,3 +55,54 @@ func (c *Client) GetBackend(ctx context.Context, poolID string, memberID string)
 	}
 	return rtn, nil
 }
+
+// ListBackends returns the members of the pool, all pages are followed
+func (c *Client) ListBackends(ctx context.Context, poolID string, filter *common.ELBBackendListRequest) ([]common.ELBBackendMember, error) {
+	if poolID == "" {
+		return nil, errors.New("[ListBackends]pool id is required")
+	}
+	var rtn []common.ELBBackendMember
+	next := common.WithQuery(c.GetURL("pools", poolID, "members"), filter)
+	for next != "" {
+		page := common.ELBBackendMemberList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Members...)
+		next = nextLink(page.MembersLinks)
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateBackend(ctx context.Context, poolID, memberID string, update common.ELBBackendUpdate) (common.ELBBackendMember, error) {
+	if poolID == "" || memberID == "" {
+		return common.ELBBackendMember{}, errors.New("pool id and member id is both required")
+	}
+	rtn := common.ELBBackendMemberDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("pools", poolID, "members", memberID),
+		&common.ELBBackendUpdateRequest{Member: update},
+		&rtn,
+	); err != nil {
+		return common.ELBBackendMember{}, err
+	}
+	return rtn.Member, nil
+}
+
+// nextLink returns the href of the next page
+func nextLink(links []common.Link) string {
+	for _, link := range links {
+		if link.Rel == "next" {
+			return link.Href
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package elb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// DefaultSyncConcurrency is the number of members changed at the same time
+// by SyncBackends when BackendSyncOptions.Concurrency is not set. Shared load
+// balancers accept one change at a time, changes rejected with 409 are
+// retried.
+const DefaultSyncConcurrency = 4
+
+// BackendSyncOptions configures SyncBackends
+type BackendSyncOptions struct {
+	// Concurrency is the number of members changed at the same time
+	Concurrency int
+}
+
+const (
+	BackendActionNone   = "none"
+	BackendActionAdd    = "add"
+	BackendActionRemove = "remove"
+	BackendActionUpdate = "update"
+)
+
+// BackendSyncResult is the outcome of one member of SyncBackends
+type BackendSyncResult struct {
+	Address      string
+	ProtocolPort int64
+	MemberID     string
+	Action       string
+	Err          error
+}
+
+func backendKey(address string, port int64) string {
+	return fmt.Sprintf("%s:%d", address, port)
+}
+
+// SyncBackends makes the members of the pool match desired. Members are
+// matched by address and protocol port: missing ones are added, extra ones
+// are removed and the ones with another weight are updated. A nil Weight
+// keeps the weight of an existing member, a weight of 0 drains it. Every
+// change waits for the load balancer to be ACTIVE and is retried while it
+// is rejected with 409. Every member gets a result, the returned error is
+// set when one of the changes failed.
+func (c *Client) SyncBackends(ctx context.Context, poolID string, desired []common.ELBBackend, opts *BackendSyncOptions) ([]BackendSyncResult, error) {
+	pool, err := c.GetBackendGroup(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pool.Pool.Loadbalancers) == 0 {
+		return nil, fmt.Errorf("pool %s has no loadbalancer", poolID)
+	}
+	loadbalancerID := pool.Pool.Loadbalancers[0].ID
+	current, err := c.ListBackends(ctx, poolID, nil)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]common.ELBBackendMember{}
+	for _, member := range current {
+		existing[backendKey(member.Address, int64(member.ProtocolPort))] = member
+	}
+
+	var results []BackendSyncResult
+	var tasks []func(context.Context) error
+	wanted := map[string]bool{}
+	for _, backend := range desired {
+		key := backendKey(backend.Address, backend.ProtocolPort)
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+		member, ok := existing[key]
+		backend := backend
+		result := BackendSyncResult{Address: backend.Address, ProtocolPort: backend.ProtocolPort, MemberID: member.ID, Action: BackendActionNone}
+		idx := len(results)
+		switch {
+		case !ok:
+			result.Action = BackendActionAdd
+			tasks = append(tasks, func(ctx context.Context) error {
+				rtn, err := c.AddBackend(ctx, poolID, common.ELBBackendRequest{Member: backend})
+				results[idx].MemberID = rtn.Member.ID
+				return err
+			})
+		case backend.Weight != nil && *backend.Weight != int64(member.Weight):
+			result.Action = BackendActionUpdate
+			weight := *backend.Weight
+			tasks = append(tasks, func(ctx context.Context) error {
+				_, err := c.UpdateBackend(ctx, poolID, member.ID, common.ELBBackendUpdate{Weight: &weight})
+				return err
+			})
+		default:
+			tasks = append(tasks, nil)
+		}
+		results = append(results, result)
+	}
+	for _, member := range current {
+		if wanted[backendKey(member.Address, int64(member.ProtocolPort))] {
+			continue
+		}
+		memberID := member.ID
+		results = append(results, BackendSyncResult{Address: member.Address, ProtocolPort: int64(member.ProtocolPort), MemberID: memberID, Action: BackendActionRemove})
+		tasks = append(tasks, func(ctx context.Context) error {
+			return c.deleteRetryingConflicts(ctx, loadbalancerID, func(ctx context.Context) error {
+				return c.RemoveBackend(ctx, poolID, memberID)
+			})
+		})
+	}
+
+	var wg sync.WaitGroup
+	concurrency := DefaultSyncConcurrency
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	for i, task := range tasks {
+		if task == nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, task func(context.Context) error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Err = c.changeWhenActive(ctx, loadbalancerID, task)
+		}(i, task)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of the member changes of pool %s failed", failed, poolID)
+	}
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+)
+
+func weight(w int64) *int64 {
+	return &w
+}
+
+// newFakePool creates a load balancer with a pool of the members
+func newFakePool(t *testing.T, members ...common.ELBBackend) (*elbtest.Server, *Client, string) {
+	server, client, _ := newFakeClients()
+	root := context.Background()
+	lb, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := client.AddBackendGroup(root, common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{
+		Protocol:       "TCP",
+		LbAlgorithm:    "ROUND_ROBIN",
+		LoadbalancerID: lb.Loadbalancer.ID,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, member := range members {
+		if _, err := client.AddBackend(root, pool.Pool.ID, common.ELBBackendRequest{Member: member}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return server, client, pool.Pool.ID
+}
+
+func TestSyncBackends(t *testing.T) {
+	server, c, poolID := newFakePool(t,
+		common.ELBBackend{Address: "192.168.0.1", ProtocolPort: 30080, SubnetID: "subnet-1"},
+		common.ELBBackend{Address: "192.168.0.2", ProtocolPort: 30080, SubnetID: "subnet-1"},
+		common.ELBBackend{Address: "192.168.0.3", ProtocolPort: 30080, SubnetID: "subnet-1"},
+		common.ELBBackend{Address: "192.168.0.6", ProtocolPort: 30080, SubnetID: "subnet-1", Weight: weight(3)},
+	)
+	server.PendingPolls = 2
+	server.FailNext(http.MethodPost, "/v2.0/lbaas/pools/"+poolID+"/members", http.StatusConflict)
+
+	results, err := c.SyncBackends(context.Background(), poolID, []common.ELBBackend{
+		{Address: "192.168.0.1", ProtocolPort: 30080, SubnetID: "subnet-1", Weight: weight(1)},
+		{Address: "192.168.0.2", ProtocolPort: 30080, SubnetID: "subnet-1", Weight: weight(0)},
+		{Address: "192.168.0.4", ProtocolPort: 30080, SubnetID: "subnet-1"},
+		{Address: "192.168.0.5", ProtocolPort: 30080},
+		{Address: "192.168.0.6", ProtocolPort: 30080, SubnetID: "subnet-1"},
+	}, &BackendSyncOptions{Concurrency: 2})
+	if err == nil {
+		t.Fatal("failed member should be reported")
+	}
+	actions := map[string]string{}
+	for _, result := range results {
+		actions[result.Address] = result.Action
+		if (result.Err != nil) != (result.Address == "192.168.0.5") {
+			t.Fatalf("unexpected result %#v", result)
+		}
+	}
+	expected := map[string]string{
+		"192.168.0.1": BackendActionNone,
+		"192.168.0.2": BackendActionUpdate,
+		"192.168.0.3": BackendActionRemove,
+		"192.168.0.4": BackendActionAdd,
+		"192.168.0.5": BackendActionAdd,
+		"192.168.0.6": BackendActionNone,
+	}
+	for addr, action := range expected {
+		if actions[addr] != action {
+			t.Fatalf("%s: got action %s, want %s", addr, actions[addr], action)
+		}
+	}
+
+	weights := map[string]int{}
+	for _, member := range server.Members[poolID] {
+		weights[member.Address] = member.Weight
+	}
+	want := map[string]int{"192.168.0.1": 1, "192.168.0.2": 0, "192.168.0.4": 1, "192.168.0.6": 3}
+	if len(weights) != len(want) {
+		t.Fatalf("pool is not synced: %v", weights)
+	}
+	for addr, w := range want {
+		if got, ok := weights[addr]; !ok || got != w {
+			t.Fatalf("pool is not synced: %v", weights)
+		}
+	}
+}
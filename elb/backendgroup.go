@@ -3,9 +3,11 @@ package elb
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
 )
 
 func (c *Client) AddBackendGroup(ctx context.Context, backend common.ELBBackendGroupRequest) (common.ELBBackendGroupDetails, error) {
@@ -58,16 +60,86 @@ func (c *Client) GetBackendGroup(ctx context.Context, poolID string) (common.ELB
 	return rtn, nil
 }
 
-func (c *Client) RemoveHealthmonitors(ctx context.Context, healthmonitorID string) error {
-	if healthmonitorID == "" {
-		return errors.New("[RemoveHealthmonitors]healthmonitor id is required")
+// ListBackendGroups returns the pools matching filter, all pages are followed.
+// Pools containing a node are found with filter.MemberAddress.
+func (c *Client) ListBackendGroups(ctx context.Context, filter *common.ELBBackendGroupListRequest) ([]common.ELBBackendGroupListItem, error) {
+	var rtn []common.ELBBackendGroupListItem
+	next := common.WithQuery(c.GetURL("pools"), filter)
+	for next != "" {
+		page := common.ELBBackendGroupList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Pools...)
+		next = nextLink(page.PoolsLinks)
 	}
-	_, err := c.DoRequest(
+	return rtn, nil
+}
+
+func (c *Client) UpdateBackendGroup(ctx context.Context, poolID string, update common.ELBBackendGroupUpdate) (common.ELBBackendGroupDetails, error) {
+	if poolID == "" {
+		return common.ELBBackendGroupDetails{}, errors.New("[UpdateBackendGroup]pool id is required")
+	}
+	rtn := common.ELBBackendGroupDetails{}
+	if _, err := c.DoRequest(
 		ctx,
-		http.MethodDelete,
-		c.GetURL("healthmonitors", healthmonitorID),
-		nil,
-		nil,
-	)
-	return err
+		http.MethodPut,
+		c.GetURL("pools", poolID),
+		&common.ELBBackendGroupUpdateRequest{Pool: update},
+		&rtn,
+	); err != nil {
+		return common.ELBBackendGroupDetails{}, err
+	}
+	return rtn, nil
+}
+
+// RemoveHealthmonitors is kept for compatibility, it is the same as DeleteHealthmonitor
+func (c *Client) RemoveHealthmonitors(ctx context.Context, healthmonitorID string) error {
+	return c.DeleteHealthmonitor(ctx, healthmonitorID)
+}
+
+// AddBackendGroupWithHealthmonitor creates the pool and attaches the monitor
+// to it once the load balancer is ACTIVE again, the pool is removed when the
+// monitor cannot be created or the pool has no load balancer. It returns when the load balancer is ACTIVE.
+func (c *Client) AddBackendGroupWithHealthmonitor(ctx context.Context, backend common.ELBBackendGroupRequest, monitor common.ELBHealthmonitor) (common.ELBBackendGroupDetails, *common.ELBHealthmonitor, error) {
+	pool, err := c.AddBackendGroup(ctx, backend)
+	if err != nil {
+		return common.ELBBackendGroupDetails{}, nil, err
+	}
+	if len(pool.Pool.Loadbalancers) == 0 {
+		// there is no load balancer to wait for, the pool is removed right away
+		if rerr := c.RemoveBackendGroup(ctx, pool.Pool.ID); rerr != nil {
+			logrus.Errorf("error removing pool %s without loadbalancer: %v", pool.Pool.ID, rerr)
+		}
+		return common.ELBBackendGroupDetails{}, nil, fmt.Errorf("pool %s has no loadbalancer", pool.Pool.ID)
+	}
+	loadbalancerID := pool.Pool.Loadbalancers[0].ID
+	monitor.PoolID = pool.Pool.ID
+	var created *common.ELBHealthmonitor
+	err = c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+		var err error
+		created, err = c.CreateHealthmonitor(ctx, &common.ELBHealthmonitorRequest{Healthmonitor: monitor})
+		return err
+	})
+	if err != nil {
+		if created == nil {
+			if rerr := c.deleteRetryingConflicts(ctx, loadbalancerID, func(ctx context.Context) error {
+				return c.RemoveBackendGroup(ctx, pool.Pool.ID)
+			}); rerr != nil {
+				logrus.Errorf("error removing pool %s after failing to create its healthmonitor: %v", pool.Pool.ID, rerr)
+			}
+			return common.ELBBackendGroupDetails{}, nil, err
+		}
+		// the monitor is created, only waiting for the load balancer failed
+		pool.Pool.HealthmonitorID = created.ID
+		return pool, created, err
+	}
+	pool.Pool.HealthmonitorID = created.ID
+	return pool, created, nil
 }
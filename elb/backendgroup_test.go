@@ -0,0 +1,51 @@
+package elb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestListBackendGroups(t *testing.T) {
+	server, c, _ := newFakeClients()
+	ctx := context.Background()
+	lb, err := c.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for i, protocol := range []string{"TCP", "UDP", "HTTP"} {
+		pool, err := c.AddBackendGroup(ctx, common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{
+			Protocol:       protocol,
+			LbAlgorithm:    "ROUND_ROBIN",
+			LoadbalancerID: lb.Loadbalancer.ID,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the UDP pool has another member
+		address := "192.168.0.1"
+		if i == 1 {
+			address = "192.168.0.2"
+		}
+		if _, err := c.AddBackend(ctx, pool.Pool.ID, common.ELBBackendRequest{Member: common.ELBBackend{Address: address, ProtocolPort: 80, SubnetID: "subnet-1"}}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, pool.Pool.ID)
+	}
+	server.Requests = nil
+
+	pools, err := c.ListBackendGroups(ctx, &common.ELBBackendGroupListRequest{Limit: 1, MemberAddress: "192.168.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 2 || pools[0].ID != ids[0] || pools[1].Protocol != "HTTP" {
+		t.Fatalf("unexpected pools %#v", pools)
+	}
+	// the pages are followed by their next links
+	if len(server.Requests) != 2 || !strings.HasPrefix(server.Requests[0], "GET /v2.0/lbaas/pools") {
+		t.Fatalf("unexpected requests %v", server.Requests)
+	}
+}
@@ -0,0 +1,191 @@
+package elb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+)
+
+// DefaultCascadeConcurrency is the number of deletes of one step of
+// DeleteLoadBalancerCascade run at the same time when
+// CascadeDeleteOptions.Concurrency is not set
+const DefaultCascadeConcurrency = 4
+
+// CascadeDeleteOptions configures DeleteLoadBalancerCascade
+type CascadeDeleteOptions struct {
+	// NetworkClient releases the EIP bound to the load balancer when it is set
+	NetworkClient *network.Client
+	// Concurrency is the number of deletes of one step run at the same time
+	Concurrency int
+}
+
+// CascadeDeleteReport lists the resources removed by DeleteLoadBalancerCascade
+type CascadeDeleteReport struct {
+	LoadBalancerID string
+	L7Rules        []string
+	L7Policies     []string
+	Members        []string
+	Healthmonitors []string
+	Pools          []string
+	Listeners      []string
+	LoadBalancer   bool
+	// ReleasedEIP is the id of the released EIP
+	ReleasedEIP string
+}
+
+type cascadeTask struct {
+	id     string
+	delete func(ctx context.Context) error
+}
+
+// DeleteLoadBalancerCascade deletes the load balancer and everything in it in
+// dependency order: l7 rules, l7 policies, members, healthmonitors, pools,
+// listeners and the load balancer itself. Up to opts.Concurrency deletes of
+// one step run at the same time, deletes rejected with 409 are retried and
+// every step waits for the load balancer to become ACTIVE again.
+// The report holds what was removed, also when an error is returned.
+func (c *Client) DeleteLoadBalancerCascade(ctx context.Context, id string, opts *CascadeDeleteOptions) (*CascadeDeleteReport, error) {
+	if opts == nil {
+		opts = &CascadeDeleteOptions{}
+	}
+	report := &CascadeDeleteReport{LoadBalancerID: id}
+	lb, err := c.GetLoadBalancer(ctx, id)
+	if err != nil {
+		return report, err
+	}
+	var eip *common.EipInfo
+	if opts.NetworkClient != nil && lb.Loadbalancer.VipPortID != "" {
+		if eip, err = opts.NetworkClient.GetEIPByPort(ctx, lb.Loadbalancer.VipPortID); err != nil {
+			return report, err
+		}
+	}
+	status, err := c.waitActive(ctx, id)
+	if err != nil {
+		return report, err
+	}
+
+	var rules, policies, members, monitors, pools, listeners []cascadeTask
+	seenPools := map[string]bool{}
+	addPool := func(pool common.ELBPoolStatus) {
+		if seenPools[pool.ID] {
+			return
+		}
+		seenPools[pool.ID] = true
+		poolID := pool.ID
+		for _, member := range pool.Members {
+			memberID := member.ID
+			members = append(members, cascadeTask{memberID, func(ctx context.Context) error {
+				return c.RemoveBackend(ctx, poolID, memberID)
+			}})
+		}
+		if pool.Healthmonitor != nil && pool.Healthmonitor.ID != "" {
+			monitorID := pool.Healthmonitor.ID
+			monitors = append(monitors, cascadeTask{monitorID, func(ctx context.Context) error {
+				return c.DeleteHealthmonitor(ctx, monitorID)
+			}})
+		}
+		pools = append(pools, cascadeTask{poolID, func(ctx context.Context) error {
+			return c.RemoveBackendGroup(ctx, poolID)
+		}})
+	}
+	for _, listener := range status.Listeners {
+		for _, policy := range listener.L7Policies {
+			policyID := policy.ID
+			for _, rule := range policy.Rules {
+				ruleID := rule.ID
+				rules = append(rules, cascadeTask{ruleID, func(ctx context.Context) error {
+					return c.DeleteL7Rule(ctx, policyID, ruleID)
+				}})
+			}
+			policies = append(policies, cascadeTask{policyID, func(ctx context.Context) error {
+				return c.DeleteL7Policy(ctx, policyID)
+			}})
+		}
+		for _, pool := range listener.Pools {
+			addPool(pool)
+		}
+		listenerID := listener.ID
+		listeners = append(listeners, cascadeTask{listenerID, func(ctx context.Context) error {
+			return c.DeleteListener(ctx, listenerID)
+		}})
+	}
+	for _, pool := range status.Pools {
+		addPool(pool)
+	}
+
+	steps := []struct {
+		name    string
+		tasks   []cascadeTask
+		removed *[]string
+	}{
+		{"l7 rules", rules, &report.L7Rules},
+		{"l7 policies", policies, &report.L7Policies},
+		{"members", members, &report.Members},
+		{"healthmonitors", monitors, &report.Healthmonitors},
+		{"pools", pools, &report.Pools},
+		{"listeners", listeners, &report.Listeners},
+	}
+	for _, step := range steps {
+		if len(step.tasks) == 0 {
+			continue
+		}
+		removed, err := c.runCascadeStep(ctx, id, step.tasks, opts.Concurrency)
+		*step.removed = removed
+		if err != nil {
+			return report, fmt.Errorf("error deleting %s of loadbalancer %s: %v", step.name, id, err)
+		}
+		if _, err := c.waitActive(ctx, id); err != nil {
+			return report, err
+		}
+	}
+
+	if err := c.DeleteLoadBalancer(ctx, id); err != nil && !IsNotFound(err) {
+		return report, err
+	}
+	report.LoadBalancer = true
+
+	if eip != nil {
+		if err := opts.NetworkClient.DeleteEIP(ctx, eip.ID); err != nil && !IsNotFound(err) {
+			return report, fmt.Errorf("error releasing eip %s of loadbalancer %s: %v", eip.ID, id, err)
+		}
+		report.ReleasedEIP = eip.ID
+	}
+	return report, nil
+}
+
+// runCascadeStep runs concurrency deletes at a time, resources which are gone
+// already count as removed
+func (c *Client) runCascadeStep(ctx context.Context, loadbalancerID string, tasks []cascadeTask, concurrency int) ([]string, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var removed []string
+	var errs []error
+	if concurrency < 1 {
+		concurrency = DefaultCascadeConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	for _, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(task cascadeTask) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := c.deleteRetryingConflicts(ctx, loadbalancerID, task.delete)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", task.id, err))
+				return
+			}
+			removed = append(removed, task.id)
+		}(task)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return removed, fmt.Errorf("%d of %d deletes failed, first error: %v", len(errs), len(tasks), errs[0])
+	}
+	return removed, nil
+}
@@ -0,0 +1,89 @@
+package elb
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestDeleteLoadBalancerCascade(t *testing.T) {
+	server, c, networkClient := newFakeClients()
+	ctx := context.Background()
+	lb, err := c.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lbID := lb.Loadbalancer.ID
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var listeners []string
+	for _, port := range []int64{80, 443} {
+		listener, err := c.CreateListener(ctx, &common.ELBListenerRequest{Listener: common.ELBListenerRequestObject{LoadbalancerId: lbID, Protocol: "HTTP", ProtocolPort: port}})
+		must(err)
+		listeners = append(listeners, listener.Listener.ID)
+	}
+	// the first listener has a pool with a monitor and two members and a
+	// policy with two rules, the other pool is only attached to the load balancer
+	pool1, monitor, err := c.AddBackendGroupWithHealthmonitor(ctx,
+		common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{Protocol: "HTTP", LbAlgorithm: "ROUND_ROBIN", ListenerID: listeners[0]}},
+		common.ELBHealthmonitor{Type: HealthmonitorTypeTCP, Delay: 5, Timeout: 3, MaxRetries: 3})
+	must(err)
+	pool2, err := c.AddBackendGroup(ctx, common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{Protocol: "HTTP", LbAlgorithm: "ROUND_ROBIN", LoadbalancerID: lbID}})
+	must(err)
+	var members []string
+	for _, member := range []struct {
+		poolID, address string
+	}{{pool1.Pool.ID, "192.168.0.1"}, {pool1.Pool.ID, "192.168.0.2"}, {pool2.Pool.ID, "192.168.0.3"}} {
+		rtn, err := c.AddBackend(ctx, member.poolID, common.ELBBackendRequest{Member: common.ELBBackend{Address: member.address, ProtocolPort: 80, SubnetID: "subnet-1"}})
+		must(err)
+		members = append(members, rtn.Member.ID)
+	}
+	routes, err := c.SyncL7Routes(ctx, listeners[0], []L7Route{{Host: "www.example.com", Path: "/api", PoolID: pool2.Pool.ID}})
+	must(err)
+	policyID := routes[0].PolicyID
+	var rules []string
+	for id := range server.L7Rules[policyID] {
+		rules = append(rules, id)
+	}
+	server.AddEIP("eip", "1.2.3.4", lb.Loadbalancer.VipPortID)
+	server.AddEIP("other", "1.2.3.5", "other-port")
+	// the load balancer is immutable after every delete for a while
+	server.PendingPolls = 2
+
+	report, err := c.DeleteLoadBalancerCascade(ctx, lbID, &CascadeDeleteOptions{NetworkClient: networkClient, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.LoadBalancers)+len(server.Listeners)+len(server.Pools)+len(server.Healthmonitors)+len(server.L7Policies) != 0 {
+		t.Fatalf("resources are left: %v", server.Requests)
+	}
+	if _, ok := server.EIPs["eip"]; ok || len(server.EIPs) != 1 {
+		t.Fatal("eip of the load balancer is not released")
+	}
+	sorted := func(ids ...string) string {
+		sort.Strings(ids)
+		return strings.Join(ids, ",")
+	}
+	got := []string{
+		sorted(report.L7Rules...), sorted(report.L7Policies...), sorted(report.Members...), sorted(report.Healthmonitors...),
+		sorted(report.Pools...), sorted(report.Listeners...),
+	}
+	expected := []string{
+		sorted(rules...), policyID, sorted(members...), monitor.ID,
+		sorted(pool1.Pool.ID, pool2.Pool.ID), sorted(listeners...),
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("unexpected report %#v", report)
+		}
+	}
+	if !report.LoadBalancer || report.ReleasedEIP != "eip" {
+		t.Fatalf("unexpected report %#v", report)
+	}
+}
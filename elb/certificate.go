@@ -1 +1,181 @@
 package elb
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const (
+	CertificateTypeServer = "server"
+	CertificateTypeClient = "client"
+)
+
+// CertificateDetails is read from the PEM certificate before it is uploaded
+type CertificateDetails struct {
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+	NotBefore   time.Time
+	NotAfter    time.Time
+	// Fingerprint is the hex encoded sha256 of the leaf certificate
+	Fingerprint string
+}
+
+// ParseCertificate checks that the PEM certificate chain is valid, that it is
+// not expired and that the private key matches the leaf certificate. The key
+// is optional for client (CA) certificates.
+func ParseCertificate(certificate, privateKey string) (*CertificateDetails, error) {
+	details, err := parseCertificatePair(certificate, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(details.NotAfter) {
+		return nil, fmt.Errorf("certificate expired at %s", details.NotAfter.Format(time.RFC3339))
+	}
+	return details, nil
+}
+
+// parseCertificatePair is ParseCertificate without the expiry check
+func parseCertificatePair(certificate, privateKey string) (*CertificateDetails, error) {
+	if privateKey != "" {
+		if _, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey)); err != nil {
+			return nil, fmt.Errorf("invalid certificate or private key: %v", err)
+		}
+	}
+	return parseLeafCertificate(certificate)
+}
+
+// parseLeafCertificate reads the first certificate of a PEM chain
+func parseLeafCertificate(certificate string) (*CertificateDetails, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid certificate: no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %v", err)
+	}
+	return &CertificateDetails{
+		CommonName:  cert.Subject.CommonName,
+		DNSNames:    cert.DNSNames,
+		IPAddresses: cert.IPAddresses,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		Fingerprint: fmt.Sprintf("%x", sha256.Sum256(block.Bytes)),
+	}, nil
+}
+
+func validateCertificate(input *common.ELBCertificateCommon, update bool) error {
+	if input.Certificate == "" {
+		if update && input.PrivateKey == "" {
+			return nil
+		}
+		return errors.New("certificate is required")
+	}
+	if input.PrivateKey == "" && input.Type != CertificateTypeClient {
+		return errors.New("private key is required for server certificate")
+	}
+	_, err := ParseCertificate(input.Certificate, input.PrivateKey)
+	return err
+}
+
+func (c *Client) CreateCertificate(ctx context.Context, input *common.ELBCertificateRequest) (*common.ELBCertificateInfo, error) {
+	if err := validateCertificate(&input.ELBCertificateCommon, false); err != nil {
+		return nil, err
+	}
+	rtn := common.ELBCertificateInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("certificates"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) GetCertificate(ctx context.Context, id string) (*common.ELBCertificateInfo, error) {
+	if id == "" {
+		return nil, errors.New("[GetCertificate]certificate id is required")
+	}
+	rtn := common.ELBCertificateInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("certificates", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// ListCertificates returns the certificates matching filter, all pages are followed
+func (c *Client) ListCertificates(ctx context.Context, filter *common.ELBCertificateListRequest) ([]common.ELBCertificateInfo, error) {
+	var rtn []common.ELBCertificateInfo
+	next := common.WithQuery(c.GetURL("certificates"), filter)
+	for next != "" {
+		page := common.ELBCertificateList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Certificates...)
+		next = nextLink(page.CertificatesLinks)
+	}
+	return rtn, nil
+}
+
+// UpdateCertificate replaces the attributes which are set in input, the new
+// certificate and private key are validated the same way as on creation
+func (c *Client) UpdateCertificate(ctx context.Context, id string, input *common.ELBCertificateRequest) (*common.ELBCertificateInfo, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateCertificate]certificate id is required")
+	}
+	if err := validateCertificate(&input.ELBCertificateCommon, true); err != nil {
+		return nil, err
+	}
+	rtn := common.ELBCertificateInfo{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("certificates", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+func (c *Client) DeleteCertificate(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteCertificate]certificate id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("certificates", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,172 @@
+package elb
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+)
+
+func newTestCertificate(t *testing.T, notAfter time.Time, dnsNames ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "sdk-test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     dnsNames,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestParseCertificate(t *testing.T) {
+	notAfter := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	cert, key := newTestCertificate(t, notAfter, "a.example.com", "b.example.com")
+	details, err := ParseCertificate(cert, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !details.NotAfter.Equal(notAfter) || len(details.DNSNames) != 2 || details.CommonName != "sdk-test" {
+		t.Fatalf("wrong certificate details %#v", details)
+	}
+	if _, err := ParseCertificate(cert, ""); err != nil {
+		t.Fatal("certificate without key should be valid", err)
+	}
+
+	_, otherKey := newTestCertificate(t, notAfter)
+	if _, err := ParseCertificate(cert, otherKey); err == nil {
+		t.Fatal("mismatched private key should be rejected")
+	}
+	expired, expiredKey := newTestCertificate(t, time.Now().Add(-time.Hour))
+	if _, err := ParseCertificate(expired, expiredKey); err == nil {
+		t.Fatal("expired certificate should be rejected")
+	}
+	if _, err := ParseCertificate("not a certificate", ""); err == nil {
+		t.Fatal("invalid certificate should be rejected")
+	}
+}
+
+// recordedRequest is a request sent to the fake server with its decoded body
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// recordRequests makes the client record its requests before they are
+// served by server
+func recordRequests(c *Client, server *elbtest.Server) *[]recordedRequest {
+	requests := &[]recordedRequest{}
+	c.GetSigner().NextTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		recorded := recordedRequest{Method: r.Method, Path: r.URL.Path}
+		if r.Body != nil {
+			data, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(data, &recorded.Body)
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		}
+		*requests = append(*requests, recorded)
+		return server.RoundTrip(r)
+	})
+	return requests
+}
+
+func TestCertificateCRUD(t *testing.T) {
+	server, c, _ := newFakeClients()
+	requests := recordRequests(c, server)
+	ctx := context.Background()
+	cert, key := newTestCertificate(t, time.Now().Add(24*time.Hour), "www.example.com")
+
+	created, err := c.CreateCertificate(ctx, &common.ELBCertificateRequest{
+		UpdatableELBCertificateAttribute: common.UpdatableELBCertificateAttribute{Name: "www"},
+		ELBCertificateCommon:             common.ELBCertificateCommon{Certificate: cert, PrivateKey: key},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := (*requests)[len(*requests)-1]
+	if last.Method != http.MethodPost || last.Path != "/v2.0/lbaas/certificates" ||
+		last.Body["name"] != "www" || last.Body["certificate"] != cert || last.Body["private_key"] != key {
+		t.Fatalf("unexpected create request %#v", last)
+	}
+	if server.Certificates[created.ID] == nil {
+		t.Fatalf("certificate %s is not created", created.ID)
+	}
+
+	if _, err := c.UpdateCertificate(ctx, created.ID, &common.ELBCertificateRequest{
+		UpdatableELBCertificateAttribute: common.UpdatableELBCertificateAttribute{Description: "rotated"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	last = (*requests)[len(*requests)-1]
+	if last.Method != http.MethodPut || last.Path != "/v2.0/lbaas/certificates/"+created.ID ||
+		len(last.Body) != 1 || last.Body["description"] != "rotated" {
+		t.Fatalf("unexpected update request %#v", last)
+	}
+	if server.Certificates[created.ID].Description != "rotated" || server.Certificates[created.ID].Certificate != cert {
+		t.Fatalf("unexpected updated certificate %#v", server.Certificates[created.ID])
+	}
+
+	if err := c.DeleteCertificate(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	last = (*requests)[len(*requests)-1]
+	if last.Method != http.MethodDelete || last.Path != "/v2.0/lbaas/certificates/"+created.ID || last.Body != nil {
+		t.Fatalf("unexpected delete request %#v", last)
+	}
+	if _, ok := server.Certificates[created.ID]; ok {
+		t.Fatalf("certificate %s is not deleted", created.ID)
+	}
+}
+
+func TestListCertificates(t *testing.T) {
+	server, c, _ := newFakeClients()
+	ctx := context.Background()
+	cert, key := newTestCertificate(t, time.Now().Add(24*time.Hour))
+	for _, name := range []string{"a", "b", "a"} {
+		if _, err := c.CreateCertificate(ctx, &common.ELBCertificateRequest{
+			UpdatableELBCertificateAttribute: common.UpdatableELBCertificateAttribute{Name: name},
+			ELBCertificateCommon:             common.ELBCertificateCommon{Certificate: cert, PrivateKey: key},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server.Requests = nil
+
+	certificates, err := c.ListCertificates(ctx, &common.ELBCertificateListRequest{Limit: 1, Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certificates) != 2 || certificates[0].Name != "a" || certificates[1].Name != "a" || certificates[0].ID == certificates[1].ID {
+		t.Fatalf("unexpected certificates %#v", certificates)
+	}
+	// the pages are followed by their next links
+	if len(server.Requests) != 2 || !strings.HasPrefix(server.Requests[0], "GET /v2.0/lbaas/certificates") {
+		t.Fatalf("unexpected requests %v", server.Requests)
+	}
+}
@@ -14,6 +14,11 @@ import (
 
 type Client struct {
 	common.Client
+	// StatusPollInterval and ProvisioningTimeout are passed to the waiters
+	// by the operations which wait for the load balancer or a new EIP, e.g.
+	// SyncBackends, the defaults of the waiters are used when they are not set
+	StatusPollInterval  time.Duration
+	ProvisioningTimeout time.Duration
 }
 
 func NewClient(baseClient *common.Client) *Client {
@@ -0,0 +1,80 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
+)
+
+// maxConflictRetries limits how often a change is retried while the load
+// balancer is immutable because of another change
+const maxConflictRetries = 10
+
+// changeWhenActive makes one change to the load balancer or one of its
+// children. Shared load balancers reject changes with 409 while they are
+// PENDING_UPDATE, so a rejected change is retried once the load balancer is
+// ACTIVE again, and the load balancer is waited for after the change so that
+// the next one is accepted.
+func (c *Client) changeWhenActive(ctx context.Context, loadbalancerID string, change func(context.Context) error) error {
+	if err := c.retryConflicts(ctx, loadbalancerID, change); err != nil {
+		return err
+	}
+	_, err := c.waitActive(ctx, loadbalancerID)
+	return err
+}
+
+// retryConflicts retries the change while the load balancer rejects it with
+// 409 because it is not ACTIVE. A 409 of an ACTIVE load balancer is returned
+// when it happens twice in a row, it is not caused by another change.
+func (c *Client) retryConflicts(ctx context.Context, loadbalancerID string, change func(context.Context) error) error {
+	activeConflicts := 0
+	for i := 0; ; i++ {
+		err := change(ctx)
+		if !isConflict(err) || i >= maxConflictRetries {
+			return err
+		}
+		status, serr := c.GetLoadBalancerStatuses(ctx, loadbalancerID)
+		if serr != nil {
+			return serr
+		}
+		if status.ProvisioningStatus == common.ProvisioningActive {
+			// the change may have been rejected just before the load balancer became ACTIVE
+			if activeConflicts++; activeConflicts > 1 {
+				return err
+			}
+			continue
+		}
+		activeConflicts = 0
+		logrus.Debugf("loadbalancer %s is %s, retrying change: %v", loadbalancerID, status.ProvisioningStatus, err)
+		if _, err := c.waitActive(ctx, loadbalancerID); err != nil {
+			return err
+		}
+	}
+}
+
+// deleteRetryingConflicts retries the delete while the load balancer is
+// immutable, resources which are gone already count as deleted
+func (c *Client) deleteRetryingConflicts(ctx context.Context, loadbalancerID string, del func(context.Context) error) error {
+	err := c.retryConflicts(ctx, loadbalancerID, del)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteWhenActive is deleteRetryingConflicts which waits for the load
+// balancer to be ACTIVE after the delete like changeWhenActive
+func (c *Client) deleteWhenActive(ctx context.Context, loadbalancerID string, del func(context.Context) error) error {
+	if err := c.deleteRetryingConflicts(ctx, loadbalancerID, del); err != nil {
+		return err
+	}
+	_, err := c.waitActive(ctx, loadbalancerID)
+	return err
+}
+
+func isConflict(err error) bool {
+	eInfo, ok := err.(*common.ErrorInfo)
+	return ok && eInfo.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,137 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+	"github.com/sirupsen/logrus"
+)
+
+// PublicIPOptions describes the EIP of EnsurePublicLoadBalancer, EIPID
+// reuses an existing EIP and the other fields are used to allocate a new one
+type PublicIPOptions struct {
+	EIPID string
+	// Type defaults to 5_bgp
+	Type          string
+	BandwidthName string
+	BandwidthSize uint32
+	// ShareType defaults to PER and ChargeMode to traffic
+	ShareType  string
+	ChargeMode string
+}
+
+// networkClient calls the EIP api with the credentials of the elb client
+func (c *Client) networkClient() *network.Client {
+	return network.NewClient(&c.Client)
+}
+
+// AssociateEIP binds the EIP to the VIP port of the load balancer which makes
+// it reachable from the internet
+func (c *Client) AssociateEIP(ctx context.Context, loadbalancerID, eipID string) (*common.EipInfo, error) {
+	if eipID == "" {
+		return nil, errors.New("[AssociateEIP]eip id is required")
+	}
+	lb, err := c.GetLoadBalancer(ctx, loadbalancerID)
+	if err != nil {
+		return nil, err
+	}
+	return c.networkClient().UpdateEIP(ctx, eipID, &common.EipAssocArg{
+		Port: common.PortDesc{PortID: lb.Loadbalancer.VipPortID},
+	})
+}
+
+// DisassociateEIP unbinds the EIP from the VIP port of the load balancer and
+// returns it, nil is returned when no EIP is bound. The EIP is not released.
+func (c *Client) DisassociateEIP(ctx context.Context, loadbalancerID string) (*common.EipInfo, error) {
+	lb, err := c.GetLoadBalancer(ctx, loadbalancerID)
+	if err != nil {
+		return nil, err
+	}
+	networkClient := c.networkClient()
+	eip, err := networkClient.GetEIPByPort(ctx, lb.Loadbalancer.VipPortID)
+	if err != nil || eip == nil {
+		return nil, err
+	}
+	return networkClient.UpdateEIP(ctx, eip.ID, &common.EipAssocArg{Port: common.PortDesc{ClearPortID: true}})
+}
+
+// EnsurePublicLoadBalancer makes sure an EIP is bound to the load balancer.
+// An EIP which is already bound is returned as it is, otherwise opts.EIPID is
+// bound or a new EIP is allocated and bound once it is ready. An allocated
+// EIP is released again when it cannot be bound.
+func (c *Client) EnsurePublicLoadBalancer(ctx context.Context, loadbalancerID string, opts *PublicIPOptions) (*common.EipInfo, error) {
+	if opts == nil {
+		opts = &PublicIPOptions{}
+	}
+	lb, err := c.GetLoadBalancer(ctx, loadbalancerID)
+	if err != nil {
+		return nil, err
+	}
+	portID := lb.Loadbalancer.VipPortID
+	networkClient := c.networkClient()
+	bound, err := networkClient.GetEIPByPort(ctx, portID)
+	if err != nil {
+		return nil, err
+	}
+	if bound != nil {
+		if opts.EIPID != "" && opts.EIPID != bound.ID {
+			return nil, fmt.Errorf("loadbalancer %s already has eip %s", loadbalancerID, bound.ID)
+		}
+		return bound, nil
+	}
+
+	if opts.EIPID != "" {
+		eip, err := networkClient.GetEIP(ctx, opts.EIPID)
+		if err != nil {
+			return nil, err
+		}
+		if eip.PortID != "" {
+			return nil, fmt.Errorf("eip %s is bound to port %s", eip.ID, eip.PortID)
+		}
+		return networkClient.UpdateEIP(ctx, eip.ID, &common.EipAssocArg{Port: common.PortDesc{PortID: portID}})
+	}
+
+	if opts.BandwidthSize == 0 {
+		return nil, errors.New("[EnsurePublicLoadBalancer]bandwidth size is required to allocate an eip")
+	}
+	arg := &common.EipAllocArg{
+		EipDesc: common.PubIP{Type: opts.Type},
+		BandWidth: common.BandwidthDesc{
+			Name:    opts.BandwidthName,
+			Size:    opts.BandwidthSize,
+			ShrType: opts.ShareType,
+			ChgMode: opts.ChargeMode,
+		},
+	}
+	if arg.EipDesc.Type == "" {
+		arg.EipDesc.Type = "5_bgp"
+	}
+	if arg.BandWidth.Name == "" {
+		arg.BandWidth.Name = lb.Loadbalancer.Name
+	}
+	if arg.BandWidth.ShrType == "" {
+		arg.BandWidth.ShrType = "PER"
+	}
+	if arg.BandWidth.ChgMode == "" {
+		arg.BandWidth.ChgMode = "traffic"
+	}
+	created, err := networkClient.CreateEIP(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	// the new EIP cannot be bound while it is PENDING_CREATE
+	eip, err := networkClient.WaitForEIPReady(ctx, c.StatusPollInterval, c.ProvisioningTimeout, created.ID)
+	if err == nil {
+		eip, err = networkClient.UpdateEIP(ctx, created.ID, &common.EipAssocArg{Port: common.PortDesc{PortID: portID}})
+	}
+	if err != nil {
+		if rerr := networkClient.DeleteEIP(ctx, created.ID); rerr != nil {
+			logrus.Errorf("error releasing eip %s after failing to bind it to loadbalancer %s: %v", created.ID, loadbalancerID, rerr)
+		}
+		return nil, err
+	}
+	return eip, nil
+}
@@ -0,0 +1,100 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestAssociateEIP(t *testing.T) {
+	server, client, _ := newFakeClients()
+	root := context.Background()
+	lb, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AddEIP("eip-a", "100.64.1.1", "")
+
+	eip, err := client.AssociateEIP(root, lb.Loadbalancer.ID, "eip-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eip.PortID != lb.Loadbalancer.VipPortID {
+		t.Fatalf("expected eip bound to %s, got %q", lb.Loadbalancer.VipPortID, eip.PortID)
+	}
+
+	eip, err = client.DisassociateEIP(root, lb.Loadbalancer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eip == nil || eip.ID != "eip-a" || server.EIPs["eip-a"].PortID != "" {
+		t.Fatalf("expected eip-a to be unbound, got %#v", eip)
+	}
+	if eip, err = client.DisassociateEIP(root, lb.Loadbalancer.ID); err != nil || eip != nil {
+		t.Fatalf("expected nothing to unbind, got %#v, %v", eip, err)
+	}
+}
+
+func TestEnsurePublicLoadBalancer(t *testing.T) {
+	server, client, _ := newFakeClients()
+	// allocated eips cannot be bound for the first two queries
+	server.EIPPendingPolls = 2
+	root := context.Background()
+	lb, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{
+			UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{Name: "public"},
+			VipSubnetID:                    "subnet-1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := lb.Loadbalancer.ID
+
+	if _, err := client.EnsurePublicLoadBalancer(root, id, nil); err == nil {
+		t.Fatal("expected error without bandwidth size")
+	}
+
+	// the allocated eip is released when it cannot be bound
+	server.FailNext(http.MethodPut, "/v1/test/publicips/*", http.StatusInternalServerError)
+	if _, err := client.EnsurePublicLoadBalancer(root, id, &PublicIPOptions{BandwidthSize: 5}); err == nil {
+		t.Fatal("expected bind error")
+	}
+	if len(server.EIPs) != 0 {
+		t.Fatalf("expected the allocated eip to be released, got %d eips", len(server.EIPs))
+	}
+
+	eip, err := client.EnsurePublicLoadBalancer(root, id, &PublicIPOptions{BandwidthSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eip.PortID != lb.Loadbalancer.VipPortID || eip.BandwidthSize != 5 {
+		t.Fatalf("unexpected eip %#v", eip)
+	}
+	again, err := client.EnsurePublicLoadBalancer(root, id, &PublicIPOptions{BandwidthSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != eip.ID || len(server.EIPs) != 1 {
+		t.Fatalf("expected eip %s to be kept, got %s", eip.ID, again.ID)
+	}
+
+	server.AddEIP("eip-other", "100.64.1.2", "")
+	if _, err := client.EnsurePublicLoadBalancer(root, id, &PublicIPOptions{EIPID: "eip-other"}); err == nil {
+		t.Fatal("expected error for another eip on a public load balancer")
+	}
+	if _, err := client.DisassociateEIP(root, id); err != nil {
+		t.Fatal(err)
+	}
+	reused, err := client.EnsurePublicLoadBalancer(root, id, &PublicIPOptions{EIPID: "eip-other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reused.ID != "eip-other" || server.EIPs["eip-other"].PortID != lb.Loadbalancer.VipPortID {
+		t.Fatalf("expected eip-other to be bound, got %#v", reused)
+	}
+}
@@ -0,0 +1,1237 @@
+// Package elbtest serves the shared load balancer api and the EIP api from
+// memory, so that code using elb.Client and network.Client can be tested
+// without an account. It only imports common, the elb package can use it in
+// its own tests.
+package elbtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+var eipPath = regexp.MustCompile(`^/v1/[^/]+/publicips(/[^/]+)?$`)
+
+type injectedError struct {
+	method string
+	path   string
+	status int
+}
+
+// Server is an http.RoundTripper which keeps the resources in maps. All
+// resources are ACTIVE right after they are created.
+type Server struct {
+	sync.Mutex
+	// PendingPolls makes a load balancer PENDING_UPDATE after every change
+	// of it or its children for that many status queries, so every change
+	// makes it immutable for a while: changes are rejected with 409 meanwhile
+	// like by a shared load balancer
+	PendingPolls int
+	// EIPPendingPolls keeps a created EIP PENDING_CREATE for that many
+	// queries of it, binding it is rejected with 409 meanwhile
+	EIPPendingPolls int
+
+	LoadBalancers  map[string]*common.LoadbalancerObject
+	Listeners      map[string]*common.ELBListenerInfoObject
+	Pools          map[string]*common.ELBBackendGroupListItem
+	Members        map[string]map[string]*common.ELBBackendMember
+	Healthmonitors map[string]*common.ELBHealthmonitor
+	Certificates   map[string]*common.ELBCertificateInfo
+	L7Policies     map[string]*common.ELBL7Policy
+	L7Rules        map[string]map[string]*common.ELBL7Rule
+	Whitelists     map[string]*common.ELBWhitelist
+	EIPs           map[string]*common.EipInfo
+	// Requests has "METHOD path" of every request which was served
+	Requests []string
+	// Served is called with "METHOD path" after a request is served, the
+	// server is locked so that it can change the resources like a concurrent
+	// client
+	Served func(request string)
+
+	errors     []injectedError
+	nextID     int
+	pending    map[string]int
+	eipPending map[string]int
+}
+
+func NewServer() *Server {
+	return &Server{
+		LoadBalancers:  map[string]*common.LoadbalancerObject{},
+		Listeners:      map[string]*common.ELBListenerInfoObject{},
+		Pools:          map[string]*common.ELBBackendGroupListItem{},
+		Members:        map[string]map[string]*common.ELBBackendMember{},
+		Healthmonitors: map[string]*common.ELBHealthmonitor{},
+		Certificates:   map[string]*common.ELBCertificateInfo{},
+		L7Policies:     map[string]*common.ELBL7Policy{},
+		L7Rules:        map[string]map[string]*common.ELBL7Rule{},
+		Whitelists:     map[string]*common.ELBWhitelist{},
+		EIPs:           map[string]*common.EipInfo{},
+		pending:        map[string]int{},
+		eipPending:     map[string]int{},
+	}
+}
+
+// Install sends the requests of the base client to the server, it has to be
+// called before the service clients are created from it
+func (s *Server) Install(base *common.Client) {
+	base.GetSigner().NextTransport = s
+}
+
+// FailNext makes the next request with the method and the path (without
+// query) fail with the status code, a path ending with "/*" matches all paths
+// below it
+func (s *Server) FailNext(method, path string, status int) {
+	s.Lock()
+	defer s.Unlock()
+	s.errors = append(s.errors, injectedError{method: method, path: path, status: status})
+}
+
+// AddEIP adds an existing EIP, it is bound when portID is set
+func (s *Server) AddEIP(id, address, portID string) *common.EipInfo {
+	s.Lock()
+	defer s.Unlock()
+	eip := &common.EipInfo{ID: id, Addr: address, PortID: portID, Status: eipStatus(portID), Type: "5_bgp"}
+	s.EIPs[id] = eip
+	return eip
+}
+
+func (s *Server) id(kind string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", kind, s.nextID)
+}
+
+type response struct {
+	status int
+	body   interface{}
+}
+
+func errorResponse(status int, code, reason string) response {
+	return response{status, map[string]string{"errorCode": code, "reason": reason}}
+}
+
+func notFound(kind, id string) response {
+	return errorResponse(http.StatusNotFound, "ELB.8904", fmt.Sprintf("%s %s could not be found", kind, id))
+}
+
+func conflict(reason string) response {
+	return errorResponse(http.StatusConflict, "ELB.8907", reason)
+}
+
+func (s *Server) RoundTrip(r *http.Request) (*http.Response, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+	var body []byte
+	if r.Body != nil {
+		body, _ = ioutil.ReadAll(r.Body)
+	}
+
+	var resp response
+	if status, ok := s.injectedError(r.Method, r.URL.Path); ok {
+		resp = errorResponse(status, "ELB.1000", "injected error")
+	} else if m := eipPath.FindStringSubmatch(r.URL.Path); m != nil {
+		resp = s.serveEIP(r, strings.TrimPrefix(m[1], "/"), body)
+	} else if strings.HasPrefix(r.URL.Path, "/v2.0/lbaas/") {
+		resp = s.serveELB(r, strings.Split(strings.TrimPrefix(r.URL.Path, "/v2.0/lbaas/"), "/"), body)
+	} else {
+		resp = errorResponse(http.StatusNotFound, "APIGW.0101", "unknown api "+r.URL.Path)
+	}
+	if s.Served != nil {
+		s.Served(r.Method + " " + r.URL.Path)
+	}
+
+	data := []byte{}
+	if resp.body != nil {
+		data, _ = json.Marshal(resp.body)
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(data)),
+		Request:    r,
+	}, nil
+}
+
+func (s *Server) injectedError(method, path string) (int, bool) {
+	for i, e := range s.errors {
+		if e.method == method && (e.path == path ||
+			strings.HasSuffix(e.path, "/*") && strings.HasPrefix(path, strings.TrimSuffix(e.path, "*"))) {
+			s.errors = append(s.errors[:i], s.errors[i+1:]...)
+			return e.status, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) serveELB(r *http.Request, parts []string, body []byte) response {
+	if r.Method == http.MethodGet {
+		return s.routeELB(r, parts, body)
+	}
+	lbID := s.owner(parts, body)
+	if s.pending[lbID] > 0 {
+		return conflict(fmt.Sprintf("loadbalancer %s is immutable while it is PENDING_UPDATE", lbID))
+	}
+	resp := s.routeELB(r, parts, body)
+	if resp.status < 300 && lbID != "" && s.LoadBalancers[lbID] != nil && s.PendingPolls > 0 {
+		s.pending[lbID] = s.PendingPolls
+	}
+	return resp
+}
+
+// owner returns the id of the load balancer which is changed by the request
+func (s *Server) owner(parts []string, body []byte) string {
+	in := struct {
+		LoadbalancerID string `json:"loadbalancer_id"`
+		ListenerID     string `json:"listener_id"`
+		PoolID         string `json:"pool_id"`
+	}{}
+	var wrapped map[string]json.RawMessage
+	if json.Unmarshal(body, &wrapped) == nil {
+		for _, raw := range wrapped {
+			json.Unmarshal(raw, &in)
+		}
+	}
+	listenerOwner := func(id string) string {
+		if listener, ok := s.Listeners[id]; ok {
+			return listener.LoadbalancerID
+		}
+		return ""
+	}
+	poolOwner := func(id string) string {
+		if pool, ok := s.Pools[id]; ok {
+			return pool.Loadbalancers[0].ID
+		}
+		return ""
+	}
+	id := ""
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+	switch parts[0] {
+	case "loadbalancers":
+		return id
+	case "listeners":
+		if id == "" {
+			return in.LoadbalancerID
+		}
+		return listenerOwner(id)
+	case "pools":
+		switch {
+		case id != "":
+			return poolOwner(id)
+		case in.ListenerID != "":
+			return listenerOwner(in.ListenerID)
+		}
+		return in.LoadbalancerID
+	case "l7policies":
+		if id == "" {
+			return listenerOwner(in.ListenerID)
+		}
+		if policy, ok := s.L7Policies[id]; ok {
+			return listenerOwner(policy.ListenerID)
+		}
+	case "whitelists":
+		if id == "" {
+			return listenerOwner(in.ListenerID)
+		}
+		if whitelist, ok := s.Whitelists[id]; ok {
+			return listenerOwner(whitelist.ListenerID)
+		}
+	case "healthmonitors":
+		if id == "" {
+			return poolOwner(in.PoolID)
+		}
+		if monitor, ok := s.Healthmonitors[id]; ok && len(monitor.Pools) > 0 {
+			return poolOwner(monitor.Pools[0].ID)
+		}
+	}
+	return ""
+}
+
+func (s *Server) routeELB(r *http.Request, parts []string, body []byte) response {
+	query := r.URL.Query()
+	switch {
+	case parts[0] == "loadbalancers" && len(parts) == 3 && parts[2] == "statuses" && r.Method == http.MethodGet:
+		return s.statuses(parts[1])
+	case parts[0] == "loadbalancers" && len(parts) <= 2:
+		return s.serveLoadBalancer(r.Method, parts, query.Get, body)
+	case parts[0] == "listeners" && len(parts) <= 2:
+		return s.serveListener(r.Method, parts, query.Get, body)
+	case parts[0] == "pools" && len(parts) <= 2:
+		return s.servePool(r.Method, parts, query.Get, body)
+	case parts[0] == "pools" && len(parts) <= 4 && parts[2] == "members":
+		return s.serveMember(r.Method, parts, query.Get, body)
+	case parts[0] == "healthmonitors" && len(parts) <= 2:
+		return s.serveHealthmonitor(r.Method, parts, query.Get, body)
+	case parts[0] == "l7policies" && len(parts) <= 2:
+		return s.serveL7Policy(r.Method, parts, query.Get, body)
+	case parts[0] == "l7policies" && len(parts) <= 4 && parts[2] == "rules":
+		return s.serveL7Rule(r.Method, parts, query.Get, body)
+	case parts[0] == "whitelists" && len(parts) <= 2:
+		return s.serveWhitelist(r.Method, parts, query.Get, body)
+	case parts[0] == "certificates" && len(parts) <= 2:
+		return s.serveCertificate(r.Method, parts, query.Get, body)
+	}
+	return errorResponse(http.StatusNotFound, "APIGW.0101", "unknown api "+r.URL.Path)
+}
+
+func (s *Server) serveLoadBalancer(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.LoadBalancerList{LoadBalancers: []common.LoadbalancerObject{}}
+			for _, lb := range s.LoadBalancers {
+				if match(query("name"), lb.Name) && match(query("vip_port_id"), lb.VipPortID) && match(query("vip_address"), lb.VIPAddress) {
+					list.LoadBalancers = append(list.LoadBalancers, *s.loadBalancer(lb))
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.LoadBalancerRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			if req.Loadbalancer.VipSubnetID == "" {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", "vip_subnet_id is required")
+			}
+			lb := req.Loadbalancer
+			lb.ID = s.id("lb")
+			lb.VipPortID = s.id("port")
+			if lb.VIPAddress == "" {
+				lb.VIPAddress = fmt.Sprintf("192.168.0.%d", s.nextID)
+			}
+			if lb.Provider == "" {
+				lb.Provider = "vlb"
+			}
+			lb.ProvisioningStatus = common.ProvisioningActive
+			lb.OperatingStatus = "ONLINE"
+			s.LoadBalancers[lb.ID] = &lb
+			return response{http.StatusCreated, common.LoadBalancerInfo{Loadbalancer: *s.loadBalancer(&lb)}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	lb, ok := s.LoadBalancers[parts[1]]
+	if !ok {
+		return notFound("loadbalancer", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.LoadBalancerInfo{Loadbalancer: *s.loadBalancer(lb)}}
+	case http.MethodPut:
+		req := common.LoadBalancerUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Loadbalancer.Name != "" {
+			lb.Name = req.Loadbalancer.Name
+		}
+		if req.Loadbalancer.Description != "" {
+			lb.Description = req.Loadbalancer.Description
+		}
+		return response{http.StatusOK, common.LoadBalancerInfo{Loadbalancer: *s.loadBalancer(lb)}}
+	case http.MethodDelete:
+		loaded := s.loadBalancer(lb)
+		if len(loaded.Listeners) > 0 || len(loaded.Pools) > 0 {
+			return conflict(fmt.Sprintf("loadbalancer %s still has listeners or pools", lb.ID))
+		}
+		for _, eip := range s.EIPs {
+			if eip.PortID == lb.VipPortID {
+				eip.PortID = ""
+				eip.Status = eipStatus("")
+			}
+		}
+		delete(s.LoadBalancers, lb.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+// loadBalancer returns a copy with the listener and pool references
+func (s *Server) loadBalancer(lb *common.LoadbalancerObject) *common.LoadbalancerObject {
+	rtn := *lb
+	rtn.Listeners, rtn.Pools = nil, nil
+	if s.pending[lb.ID] > 0 {
+		rtn.ProvisioningStatus = common.ProvisioningPendingUpdate
+	}
+	for _, listener := range s.Listeners {
+		if listener.LoadbalancerID == lb.ID {
+			rtn.Listeners = append(rtn.Listeners, common.ELBResourceRef{ID: listener.ID})
+		}
+	}
+	for _, pool := range s.Pools {
+		if pool.Loadbalancers[0].ID == lb.ID {
+			rtn.Pools = append(rtn.Pools, common.ELBResourceRef{ID: pool.ID})
+		}
+	}
+	return &rtn
+}
+
+func (s *Server) serveListener(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBListenerList{Listeners: []common.ELBListenerInfoObject{}}
+			for _, listener := range s.Listeners {
+				if match(query("loadbalancer_id"), listener.LoadbalancerID) && match(query("name"), listener.Name) {
+					list.Listeners = append(list.Listeners, *listener)
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBListenerRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			in := req.Listener
+			if _, ok := s.LoadBalancers[in.LoadbalancerId]; !ok {
+				return notFound("loadbalancer", in.LoadbalancerId)
+			}
+			for _, listener := range s.Listeners {
+				if listener.LoadbalancerID == in.LoadbalancerId && listener.ProtocolPort == in.ProtocolPort {
+					return conflict(fmt.Sprintf("port %d is used by listener %s", in.ProtocolPort, listener.ID))
+				}
+			}
+			listener := &common.ELBListenerInfoObject{
+				ID:                     s.id("listener"),
+				DefaultTlsContainerRef: in.DefaultTlsContainerRef,
+				SniContainerRefs:       in.SniContainerRefs,
+				ProtocolPort:           in.ProtocolPort,
+				Loadbalancers:          []common.ELBResourceRef{{ID: in.LoadbalancerId}},
+				DefaultPoolID:          in.DefaultPoolId,
+				ConnectionLimit:        in.ConnectionLimit,
+				Http2Enable:            in.Http2Enable,
+			}
+			listener.LoadbalancerID = in.LoadbalancerId
+			listener.Protocol = in.Protocol
+			listener.Name = in.Name
+			listener.Description = in.Description
+			s.Listeners[listener.ID] = listener
+			return response{http.StatusCreated, common.ELBListenerInfo{Listener: *listener}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	listener, ok := s.Listeners[parts[1]]
+	if !ok {
+		return notFound("listener", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBListenerInfo{Listener: *listener}}
+	case http.MethodPut:
+		req := common.ELBListenerUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		in := req.Listener
+		for _, id := range append([]string{in.DefaultTlsContainerRef}, in.SniContainerRefs...) {
+			if _, ok := s.Certificates[id]; id != "" && !ok {
+				return notFound("certificate", id)
+			}
+		}
+		// null and [] clear the certificates, missing fields are kept
+		fields := map[string]map[string]json.RawMessage{}
+		json.Unmarshal(body, &fields)
+		if in.Name != "" {
+			listener.Name = in.Name
+		}
+		if in.DefaultPoolId != "" {
+			listener.DefaultPoolID = in.DefaultPoolId
+		}
+		if _, ok := fields["listener"]["default_tls_container_ref"]; ok {
+			listener.DefaultTlsContainerRef = in.DefaultTlsContainerRef
+		}
+		if _, ok := fields["listener"]["sni_container_refs"]; ok {
+			listener.SniContainerRefs = in.SniContainerRefs
+		}
+		return response{http.StatusOK, common.ELBListenerInfo{Listener: *listener}}
+	case http.MethodDelete:
+		for _, pool := range s.Pools {
+			if len(pool.Listeners) > 0 && pool.Listeners[0].ID == listener.ID {
+				return conflict(fmt.Sprintf("listener %s is used by pool %s", listener.ID, pool.ID))
+			}
+		}
+		for _, policy := range s.L7Policies {
+			if policy.ListenerID == listener.ID {
+				return conflict(fmt.Sprintf("listener %s still has l7policy %s", listener.ID, policy.ID))
+			}
+		}
+		for _, whitelist := range s.Whitelists {
+			if whitelist.ListenerID == listener.ID {
+				return conflict(fmt.Sprintf("listener %s still has whitelist %s", listener.ID, whitelist.ID))
+			}
+		}
+		delete(s.Listeners, listener.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) servePool(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBBackendGroupList{Pools: []common.ELBBackendGroupListItem{}}
+			limit, _ := strconv.Atoi(query("limit"))
+			for _, id := range sortedIDs(s.Pools) {
+				pool := s.Pools[id]
+				if query("marker") != "" && !idAfter(id, query("marker")) ||
+					!match(query("id"), pool.ID) || !match(query("name"), pool.Name) ||
+					!match(query("loadbalancer_id"), pool.Loadbalancers[0].ID) ||
+					query("member_address") != "" && !s.hasMember(pool.ID, query("member_address")) {
+					continue
+				}
+				if limit > 0 && len(list.Pools) == limit {
+					list.PoolsLinks = nextPage("pools", query, list.Pools[limit-1].ID, "id", "name", "loadbalancer_id", "member_address")
+					break
+				}
+				list.Pools = append(list.Pools, *s.pool(pool))
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBBackendGroupRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			in := req.Pool
+			pool := &common.ELBBackendGroupListItem{
+				ID:                 s.id("pool"),
+				Name:               in.Name,
+				Description:        in.Description,
+				Protocol:           in.Protocol,
+				LbAlgorithm:        in.LbAlgorithm,
+				AdminStateUp:       true,
+				SessionPersistence: in.SessionPersistence,
+			}
+			lbID := in.LoadbalancerID
+			if in.ListenerID != "" {
+				listener, ok := s.Listeners[in.ListenerID]
+				if !ok {
+					return notFound("listener", in.ListenerID)
+				}
+				if listener.DefaultPoolID != "" {
+					return conflict(fmt.Sprintf("listener %s already has pool %s", listener.ID, listener.DefaultPoolID))
+				}
+				listener.DefaultPoolID = pool.ID
+				pool.Listeners = []common.ELBResourceRef{{ID: listener.ID}}
+				lbID = listener.LoadbalancerID
+			}
+			if _, ok := s.LoadBalancers[lbID]; !ok {
+				return notFound("loadbalancer", lbID)
+			}
+			pool.Loadbalancers = []common.ELBResourceRef{{ID: lbID}}
+			s.Pools[pool.ID] = pool
+			s.Members[pool.ID] = map[string]*common.ELBBackendMember{}
+			return response{http.StatusCreated, common.ELBBackendGroupDetails{Pool: *s.pool(pool)}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	pool, ok := s.Pools[parts[1]]
+	if !ok {
+		return notFound("pool", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBBackendGroupDetails{Pool: *s.pool(pool)}}
+	case http.MethodPut:
+		req := struct {
+			Pool struct {
+				Name               string                     `json:"name"`
+				LbAlgorithm        string                     `json:"lb_algorithm"`
+				SessionPersistence *common.SessionPersistence `json:"session_persistence"`
+			} `json:"pool"`
+		}{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Pool.Name != "" {
+			pool.Name = req.Pool.Name
+		}
+		if req.Pool.LbAlgorithm != "" {
+			pool.LbAlgorithm = req.Pool.LbAlgorithm
+		}
+		if req.Pool.SessionPersistence != nil {
+			pool.SessionPersistence = *req.Pool.SessionPersistence
+		} else if strings.Contains(string(body), `"session_persistence":null`) {
+			pool.SessionPersistence = common.SessionPersistence{}
+		}
+		return response{http.StatusOK, common.ELBBackendGroupDetails{Pool: *s.pool(pool)}}
+	case http.MethodDelete:
+		if len(s.Members[pool.ID]) > 0 || pool.HealthmonitorID != "" {
+			return conflict(fmt.Sprintf("pool %s still has members or a healthmonitor", pool.ID))
+		}
+		for _, policy := range s.L7Policies {
+			if policy.RedirectPoolID == pool.ID {
+				return conflict(fmt.Sprintf("pool %s is used by l7policy %s", pool.ID, policy.ID))
+			}
+		}
+		for _, listener := range s.Listeners {
+			if listener.DefaultPoolID == pool.ID {
+				listener.DefaultPoolID = ""
+			}
+		}
+		delete(s.Pools, pool.ID)
+		delete(s.Members, pool.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+// pool returns a copy with the member references
+func (s *Server) pool(pool *common.ELBBackendGroupListItem) *common.ELBBackendGroupListItem {
+	rtn := *pool
+	rtn.Members = nil
+	for id := range s.Members[pool.ID] {
+		rtn.Members = append(rtn.Members, common.ELBResourceRef{ID: id})
+	}
+	return &rtn
+}
+
+func (s *Server) hasMember(poolID, address string) bool {
+	for _, member := range s.Members[poolID] {
+		if member.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveMember(method string, parts []string, query func(string) string, body []byte) response {
+	members, ok := s.Members[parts[1]]
+	if !ok {
+		return notFound("pool", parts[1])
+	}
+	if len(parts) == 3 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBBackendMemberList{Members: []common.ELBBackendMember{}}
+			for _, member := range members {
+				if match(query("address"), member.Address) {
+					list.Members = append(list.Members, *member)
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBBackendRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			in := req.Member
+			if in.Address == "" || in.ProtocolPort == 0 || in.SubnetID == "" {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", "address, protocol_port and subnet_id are required")
+			}
+			for _, member := range members {
+				if member.Address == in.Address && int64(member.ProtocolPort) == in.ProtocolPort {
+					return conflict(fmt.Sprintf("%s:%d is already member %s", in.Address, in.ProtocolPort, member.ID))
+				}
+			}
+			if in.Weight == nil {
+				weight := int64(1)
+				in.Weight = &weight
+			}
+			in.ID = s.id("member")
+			in.AdminStateUp = true
+			members[in.ID] = &common.ELBBackendMember{
+				ID:              in.ID,
+				Name:            in.Name,
+				Address:         in.Address,
+				ProtocolPort:    int(in.ProtocolPort),
+				SubnetID:        in.SubnetID,
+				AdminStateUp:    true,
+				Weight:          int(*in.Weight),
+				OperatingStatus: "ONLINE",
+			}
+			return response{http.StatusCreated, common.ELBBackendResponce{Member: in}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	member, ok := members[parts[3]]
+	if !ok {
+		return notFound("member", parts[3])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBBackendMemberDetails{Member: *member}}
+	case http.MethodPut:
+		req := common.ELBBackendUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Member.Weight != nil {
+			member.Weight = int(*req.Member.Weight)
+		}
+		if req.Member.Name != "" {
+			member.Name = req.Member.Name
+		}
+		return response{http.StatusOK, common.ELBBackendMemberDetails{Member: *member}}
+	case http.MethodDelete:
+		delete(members, member.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) serveHealthmonitor(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBHealthmonitorList{Healthmonitors: []common.ELBHealthmonitor{}}
+			limit, _ := strconv.Atoi(query("limit"))
+			for _, id := range sortedIDs(s.Healthmonitors) {
+				monitor := s.Healthmonitors[id]
+				if query("marker") != "" && !idAfter(id, query("marker")) || !match(query("type"), monitor.Type) {
+					continue
+				}
+				if limit > 0 && len(list.Healthmonitors) == limit {
+					list.HealthmonitorsLinks = nextPage("healthmonitors", query, list.Healthmonitors[limit-1].ID, "type")
+					break
+				}
+				list.Healthmonitors = append(list.Healthmonitors, *monitor)
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBHealthmonitorRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			monitor := req.Healthmonitor
+			pool, ok := s.Pools[monitor.PoolID]
+			if !ok {
+				return notFound("pool", monitor.PoolID)
+			}
+			if pool.HealthmonitorID != "" {
+				return conflict(fmt.Sprintf("pool %s already has healthmonitor %s", pool.ID, pool.HealthmonitorID))
+			}
+			monitor.ID = s.id("healthmonitor")
+			monitor.Pools = []common.ELBResourceRef{{ID: pool.ID}}
+			monitor.PoolID = ""
+			pool.HealthmonitorID = monitor.ID
+			s.Healthmonitors[monitor.ID] = &monitor
+			return response{http.StatusCreated, common.ELBHealthmonitorDetails{Healthmonitor: monitor}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	monitor, ok := s.Healthmonitors[parts[1]]
+	if !ok {
+		return notFound("healthmonitor", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBHealthmonitorDetails{Healthmonitor: *monitor}}
+	case http.MethodPut:
+		req := common.ELBHealthmonitorUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		in := req.Healthmonitor
+		if in.Delay != 0 {
+			monitor.Delay = in.Delay
+		}
+		if in.Timeout != 0 {
+			monitor.Timeout = in.Timeout
+		}
+		if in.MaxRetries != 0 {
+			monitor.MaxRetries = in.MaxRetries
+		}
+		if in.URLPath != "" {
+			monitor.URLPath = in.URLPath
+		}
+		fields := map[string]map[string]json.RawMessage{}
+		json.Unmarshal(body, &fields)
+		if port, ok := fields["healthmonitor"]["monitor_port"]; ok {
+			if string(port) != "null" && *in.MonitorPort < 1 {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", "monitor_port must be between 1 and 65535")
+			}
+			monitor.MonitorPort = 0
+			if in.MonitorPort != nil {
+				monitor.MonitorPort = *in.MonitorPort
+			}
+		}
+		return response{http.StatusOK, common.ELBHealthmonitorDetails{Healthmonitor: *monitor}}
+	case http.MethodDelete:
+		for _, pool := range s.Pools {
+			if pool.HealthmonitorID == monitor.ID {
+				pool.HealthmonitorID = ""
+			}
+		}
+		delete(s.Healthmonitors, monitor.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) serveL7Policy(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBL7PolicyList{L7Policies: []common.ELBL7Policy{}}
+			for _, id := range sortedIDs(s.L7Policies) {
+				policy := s.L7Policies[id]
+				if match(query("listener_id"), policy.ListenerID) && match(query("action"), policy.Action) {
+					list.L7Policies = append(list.L7Policies, *s.l7Policy(policy))
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBL7PolicyRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			policy := req.L7Policy
+			if _, ok := s.Listeners[policy.ListenerID]; !ok {
+				return notFound("listener", policy.ListenerID)
+			}
+			if _, ok := s.Pools[policy.RedirectPoolID]; policy.RedirectPoolID != "" && !ok {
+				return notFound("pool", policy.RedirectPoolID)
+			}
+			policy.ID = s.id("l7policy")
+			policy.ProvisioningStatus = common.ProvisioningActive
+			s.L7Policies[policy.ID] = &policy
+			s.L7Rules[policy.ID] = map[string]*common.ELBL7Rule{}
+			return response{http.StatusCreated, common.ELBL7PolicyDetails{L7Policy: *s.l7Policy(&policy)}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	policy, ok := s.L7Policies[parts[1]]
+	if !ok {
+		return notFound("l7policy", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBL7PolicyDetails{L7Policy: *s.l7Policy(policy)}}
+	case http.MethodPut:
+		req := common.ELBL7PolicyUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if id := req.L7Policy.RedirectPoolID; id != "" {
+			if _, ok := s.Pools[id]; !ok {
+				return notFound("pool", id)
+			}
+			policy.RedirectPoolID = id
+		}
+		if req.L7Policy.Name != "" {
+			policy.Name = req.L7Policy.Name
+		}
+		return response{http.StatusOK, common.ELBL7PolicyDetails{L7Policy: *s.l7Policy(policy)}}
+	case http.MethodDelete:
+		delete(s.L7Policies, policy.ID)
+		delete(s.L7Rules, policy.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+// l7Policy returns a copy with the rule references
+func (s *Server) l7Policy(policy *common.ELBL7Policy) *common.ELBL7Policy {
+	rtn := *policy
+	rtn.Rules = nil
+	for _, id := range sortedIDs(s.L7Rules[policy.ID]) {
+		rtn.Rules = append(rtn.Rules, common.ELBResourceRef{ID: id})
+	}
+	return &rtn
+}
+
+func (s *Server) serveL7Rule(method string, parts []string, query func(string) string, body []byte) response {
+	rules, ok := s.L7Rules[parts[1]]
+	if !ok {
+		return notFound("l7policy", parts[1])
+	}
+	if len(parts) == 3 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBL7RuleList{Rules: []common.ELBL7Rule{}}
+			for _, id := range sortedIDs(rules) {
+				if match(query("type"), rules[id].Type) {
+					list.Rules = append(list.Rules, *rules[id])
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBL7RuleRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			rule := req.Rule
+			for _, existing := range rules {
+				if existing.Type == rule.Type {
+					return conflict(fmt.Sprintf("l7policy %s already has a %s rule", parts[1], rule.Type))
+				}
+			}
+			rule.ID = s.id("l7rule")
+			rule.ProvisioningStatus = common.ProvisioningActive
+			rules[rule.ID] = &rule
+			return response{http.StatusCreated, common.ELBL7RuleDetails{Rule: rule}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	rule, ok := rules[parts[3]]
+	if !ok {
+		return notFound("l7rule", parts[3])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBL7RuleDetails{Rule: *rule}}
+	case http.MethodPut:
+		req := common.ELBL7RuleUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Rule.CompareType != "" {
+			rule.CompareType = req.Rule.CompareType
+		}
+		if req.Rule.Value != "" {
+			rule.Value = req.Rule.Value
+		}
+		return response{http.StatusOK, common.ELBL7RuleDetails{Rule: *rule}}
+	case http.MethodDelete:
+		delete(rules, rule.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) serveWhitelist(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBWhitelistList{Whitelists: []common.ELBWhitelist{}}
+			for _, id := range sortedIDs(s.Whitelists) {
+				if whitelist := s.Whitelists[id]; match(query("listener_id"), whitelist.ListenerID) {
+					list.Whitelists = append(list.Whitelists, *whitelist)
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.ELBWhitelistRequest{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			whitelist := req.Whitelist
+			if _, ok := s.Listeners[whitelist.ListenerID]; !ok {
+				return notFound("listener", whitelist.ListenerID)
+			}
+			for _, existing := range s.Whitelists {
+				if existing.ListenerID == whitelist.ListenerID {
+					return conflict(fmt.Sprintf("listener %s already has whitelist %s", whitelist.ListenerID, existing.ID))
+				}
+			}
+			whitelist.ID = s.id("whitelist")
+			if whitelist.EnableWhitelist == nil {
+				enable := true
+				whitelist.EnableWhitelist = &enable
+			}
+			s.Whitelists[whitelist.ID] = &whitelist
+			return response{http.StatusCreated, common.ELBWhitelistDetails{Whitelist: whitelist}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	whitelist, ok := s.Whitelists[parts[1]]
+	if !ok {
+		return notFound("whitelist", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, common.ELBWhitelistDetails{Whitelist: *whitelist}}
+	case http.MethodPut:
+		req := common.ELBWhitelistUpdateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Whitelist.EnableWhitelist != nil {
+			whitelist.EnableWhitelist = req.Whitelist.EnableWhitelist
+		}
+		if req.Whitelist.Whitelist != nil {
+			whitelist.Whitelist = *req.Whitelist.Whitelist
+		}
+		return response{http.StatusOK, common.ELBWhitelistDetails{Whitelist: *whitelist}}
+	case http.MethodDelete:
+		delete(s.Whitelists, whitelist.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) serveCertificate(method string, parts []string, query func(string) string, body []byte) response {
+	if len(parts) == 1 {
+		switch method {
+		case http.MethodGet:
+			list := common.ELBCertificateList{Certificates: []common.ELBCertificateInfo{}}
+			limit, _ := strconv.Atoi(query("limit"))
+			for _, id := range sortedIDs(s.Certificates) {
+				cert := s.Certificates[id]
+				if query("marker") != "" && !idAfter(id, query("marker")) || !match(query("name"), cert.Name) {
+					continue
+				}
+				if limit > 0 && len(list.Certificates) == limit {
+					list.CertificatesLinks = nextPage("certificates", query, list.Certificates[limit-1].ID, "name")
+					break
+				}
+				list.Certificates = append(list.Certificates, *cert)
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			cert := &common.ELBCertificateInfo{}
+			if err := json.Unmarshal(body, cert); err != nil {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+			}
+			if cert.Certificate == "" {
+				return errorResponse(http.StatusBadRequest, "ELB.1101", "certificate is required")
+			}
+			cert.ID = s.id("cert")
+			cert.AdminState = true
+			s.Certificates[cert.ID] = cert
+			return response{http.StatusOK, *cert}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+	}
+
+	cert, ok := s.Certificates[parts[1]]
+	if !ok {
+		return notFound("certificate", parts[1])
+	}
+	switch method {
+	case http.MethodGet:
+		return response{http.StatusOK, *cert}
+	case http.MethodPut:
+		req := common.ELBCertificateRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "ELB.1101", err.Error())
+		}
+		if req.Name != "" {
+			cert.Name = req.Name
+		}
+		if req.Description != "" {
+			cert.Description = req.Description
+		}
+		if req.Certificate != "" {
+			cert.Certificate, cert.PrivateKey = req.Certificate, req.PrivateKey
+		}
+		return response{http.StatusOK, *cert}
+	case http.MethodDelete:
+		for _, listener := range s.Listeners {
+			if listener.DefaultTlsContainerRef == cert.ID || contains(listener.SniContainerRefs, cert.ID) {
+				return conflict(fmt.Sprintf("certificate %s is used by listener %s", cert.ID, listener.ID))
+			}
+		}
+		delete(s.Certificates, cert.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "ELB.1000", method)
+}
+
+func (s *Server) statuses(id string) response {
+	lb, ok := s.LoadBalancers[id]
+	if !ok {
+		return notFound("loadbalancer", id)
+	}
+	poolStatus := func(pool *common.ELBBackendGroupListItem) common.ELBPoolStatus {
+		status := common.ELBPoolStatus{ID: pool.ID, Name: pool.Name, ProvisioningStatus: common.ProvisioningActive, OperatingStatus: "ONLINE"}
+		if monitor, ok := s.Healthmonitors[pool.HealthmonitorID]; ok {
+			status.Healthmonitor = &common.ELBHealthmonitorStatus{ID: monitor.ID, Type: monitor.Type, ProvisioningStatus: common.ProvisioningActive}
+		}
+		for _, member := range s.Members[pool.ID] {
+			status.Members = append(status.Members, common.ELBMemberStatus{
+				ID:                 member.ID,
+				Address:            member.Address,
+				ProtocolPort:       int64(member.ProtocolPort),
+				ProvisioningStatus: common.ProvisioningActive,
+				OperatingStatus:    member.OperatingStatus,
+			})
+		}
+		return status
+	}
+	status := common.ELBLoadBalancerStatus{ID: lb.ID, Name: lb.Name, ProvisioningStatus: lb.ProvisioningStatus, OperatingStatus: lb.OperatingStatus}
+	if s.pending[lb.ID] > 0 {
+		s.pending[lb.ID]--
+		status.ProvisioningStatus = common.ProvisioningPendingUpdate
+	}
+	for _, listener := range s.Listeners {
+		if listener.LoadbalancerID != lb.ID {
+			continue
+		}
+		ls := common.ELBListenerStatus{ID: listener.ID, Name: listener.Name, ProvisioningStatus: common.ProvisioningActive, OperatingStatus: "ONLINE"}
+		if pool, ok := s.Pools[listener.DefaultPoolID]; ok {
+			ls.Pools = append(ls.Pools, poolStatus(pool))
+		}
+		for _, policy := range s.L7Policies {
+			if policy.ListenerID != listener.ID {
+				continue
+			}
+			ps := common.ELBL7PolicyStatus{ID: policy.ID, Name: policy.Name, Action: policy.Action, ProvisioningStatus: common.ProvisioningActive}
+			for _, rule := range s.L7Rules[policy.ID] {
+				ps.Rules = append(ps.Rules, common.ELBL7RuleStatus{ID: rule.ID, Type: rule.Type, ProvisioningStatus: common.ProvisioningActive})
+			}
+			ls.L7Policies = append(ls.L7Policies, ps)
+		}
+		status.Listeners = append(status.Listeners, ls)
+	}
+	for _, pool := range s.Pools {
+		if len(pool.Listeners) == 0 && pool.Loadbalancers[0].ID == lb.ID {
+			status.Pools = append(status.Pools, poolStatus(pool))
+		}
+	}
+	rtn := common.ELBStatuses{}
+	rtn.Statuses.Loadbalancer = status
+	return response{http.StatusOK, rtn}
+}
+
+func (s *Server) serveEIP(r *http.Request, id string, body []byte) response {
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			list := common.EipListResp{Eips: []common.EipInfo{}}
+			for _, eip := range s.EIPs {
+				if match(r.URL.Query().Get("port_id"), eip.PortID) {
+					list.Eips = append(list.Eips, *eip)
+				}
+			}
+			return response{http.StatusOK, list}
+		case http.MethodPost:
+			req := common.EipAllocArg{}
+			if err := json.Unmarshal(body, &req); err != nil {
+				return errorResponse(http.StatusBadRequest, "VPC.0101", err.Error())
+			}
+			if req.EipDesc.Type == "" || req.BandWidth.Size == 0 {
+				return errorResponse(http.StatusBadRequest, "VPC.0101", "publicip type and bandwidth size are required")
+			}
+			eip := &common.EipInfo{
+				ID:            s.id("eip"),
+				Type:          req.EipDesc.Type,
+				Status:        eipStatus(""),
+				BandwidthSize: req.BandWidth.Size,
+				BandwidthID:   s.id("bandwidth"),
+			}
+			eip.Addr = fmt.Sprintf("100.64.0.%d", s.nextID)
+			if s.EIPPendingPolls > 0 {
+				eip.Status = common.ProvisioningPendingCreate
+				s.eipPending[eip.ID] = s.EIPPendingPolls
+			}
+			s.EIPs[eip.ID] = eip
+			return response{http.StatusOK, common.EipResp{Eip: *eip}}
+		}
+		return errorResponse(http.StatusMethodNotAllowed, "VPC.0101", r.Method)
+	}
+
+	eip, ok := s.EIPs[id]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "VPC.0504", fmt.Sprintf("publicip %s could not be found", id))
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if s.eipPending[id] > 0 {
+			if s.eipPending[id]--; s.eipPending[id] == 0 {
+				eip.Status = eipStatus(eip.PortID)
+			}
+		}
+		return response{http.StatusOK, common.EipResp{Eip: *eip}}
+	case http.MethodPut:
+		if s.eipPending[id] > 0 {
+			return errorResponse(http.StatusConflict, "VPC.0507", fmt.Sprintf("publicip %s is %s", eip.ID, eip.Status))
+		}
+		// port_id has to be sent, null unbinds the EIP
+		req := struct {
+			Port map[string]*string `json:"publicip"`
+		}{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return errorResponse(http.StatusBadRequest, "VPC.0101", err.Error())
+		}
+		port, ok := req.Port["port_id"]
+		if !ok {
+			return errorResponse(http.StatusBadRequest, "VPC.0101", "port_id is required")
+		}
+		portID := ""
+		if port != nil {
+			portID = *port
+		}
+		if portID != "" {
+			if eip.PortID != "" && eip.PortID != portID {
+				return errorResponse(http.StatusConflict, "VPC.0507", fmt.Sprintf("publicip %s is bound to port %s", eip.ID, eip.PortID))
+			}
+			for _, other := range s.EIPs {
+				if other.ID != eip.ID && other.PortID == portID {
+					return errorResponse(http.StatusConflict, "VPC.0507", fmt.Sprintf("port %s already has publicip %s", portID, other.ID))
+				}
+			}
+		}
+		eip.PortID = portID
+		eip.Status = eipStatus(portID)
+		return response{http.StatusOK, common.EipResp{Eip: *eip}}
+	case http.MethodDelete:
+		if eip.PortID != "" {
+			return errorResponse(http.StatusConflict, "VPC.0507", fmt.Sprintf("publicip %s is bound to port %s", eip.ID, eip.PortID))
+		}
+		delete(s.EIPs, eip.ID)
+		return response{http.StatusNoContent, nil}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "VPC.0101", r.Method)
+}
+
+func eipStatus(portID string) string {
+	if portID == "" {
+		return "DOWN"
+	}
+	return "ACTIVE"
+}
+
+// nextPage returns the next link of a list which is cut after marker, the
+// filters are kept
+func nextPage(resource string, query func(string) string, marker string, filters ...string) []common.Link {
+	next := url.Values{"marker": []string{marker}, "limit": []string{query("limit")}}
+	for _, filter := range filters {
+		if query(filter) != "" {
+			next.Set(filter, query(filter))
+		}
+	}
+	return []common.Link{{Rel: "next", Href: "https://elb.elbtest/v2.0/lbaas/" + resource + "?" + next.Encode()}}
+}
+
+// sortedIDs returns the keys in the order of creation
+func sortedIDs(m interface{}) []string {
+	var ids []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		ids = append(ids, key.String())
+	}
+	sort.Slice(ids, func(i, j int) bool { return idAfter(ids[j], ids[i]) })
+	return ids
+}
+
+// idAfter reports whether the resource with id was created after the one with marker
+func idAfter(id, marker string) bool {
+	if len(id) != len(marker) {
+		return len(id) > len(marker)
+	}
+	return id > marker
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether value passes the filter, an empty filter matches everything
+func match(filter, value string) bool {
+	return filter == "" || filter == value
+}
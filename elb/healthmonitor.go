@@ -0,0 +1,101 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const (
+	HealthmonitorTypeTCP        = "TCP"
+	HealthmonitorTypeUDPConnect = "UDP_CONNECT"
+	HealthmonitorTypeHTTP       = "HTTP"
+)
+
+func (c *Client) CreateHealthmonitor(ctx context.Context, input *common.ELBHealthmonitorRequest) (*common.ELBHealthmonitor, error) {
+	if input.Healthmonitor.PoolID == "" {
+		return nil, errors.New("[CreateHealthmonitor]pool id is required")
+	}
+	rtn := common.ELBHealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("healthmonitors"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+func (c *Client) GetHealthmonitor(ctx context.Context, id string) (*common.ELBHealthmonitor, error) {
+	if id == "" {
+		return nil, errors.New("[GetHealthmonitor]healthmonitor id is required")
+	}
+	rtn := common.ELBHealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("healthmonitors", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+// ListHealthmonitors returns the healthmonitors matching filter, all pages are followed
+func (c *Client) ListHealthmonitors(ctx context.Context, filter *common.ELBHealthmonitorListRequest) ([]common.ELBHealthmonitor, error) {
+	var rtn []common.ELBHealthmonitor
+	next := common.WithQuery(c.GetURL("healthmonitors"), filter)
+	for next != "" {
+		page := common.ELBHealthmonitorList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Healthmonitors...)
+		next = nextLink(page.HealthmonitorsLinks)
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateHealthmonitor(ctx context.Context, id string, update common.ELBHealthmonitorUpdate) (*common.ELBHealthmonitor, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateHealthmonitor]healthmonitor id is required")
+	}
+	rtn := common.ELBHealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("healthmonitors", id),
+		&common.ELBHealthmonitorUpdateRequest{Healthmonitor: update},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+func (c *Client) DeleteHealthmonitor(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteHealthmonitor]healthmonitor id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("healthmonitors", id),
+		nil,
+		nil,
+	)
+	return err
+}
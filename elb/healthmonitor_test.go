@@ -0,0 +1,126 @@
+package elb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestAddBackendGroupWithHealthmonitor(t *testing.T) {
+	server, c, _ := newFakeClients()
+	ctx := context.Background()
+	lb, err := c.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the monitor is rejected with 409 until the pool is created
+	server.PendingPolls = 2
+	request := common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{Protocol: "TCP", LbAlgorithm: "ROUND_ROBIN", LoadbalancerID: lb.Loadbalancer.ID}}
+	pool, monitor, err := c.AddBackendGroupWithHealthmonitor(ctx, request,
+		common.ELBHealthmonitor{Type: HealthmonitorTypeHTTP, Delay: 5, Timeout: 3, MaxRetries: 3, URLPath: "/healthz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Pools[0].ID != pool.Pool.ID || pool.Pool.HealthmonitorID != monitor.ID || server.Pools[pool.Pool.ID].HealthmonitorID != monitor.ID {
+		t.Fatalf("unexpected result %#v %#v", pool, monitor)
+	}
+	if status, _ := c.GetLoadBalancerStatuses(ctx, lb.Loadbalancer.ID); status.ProvisioningStatus != common.ProvisioningActive {
+		t.Fatal("load balancer should be ACTIVE on return")
+	}
+
+	server.FailNext(http.MethodPost, "/v2.0/lbaas/healthmonitors", http.StatusBadRequest)
+	if _, _, err := c.AddBackendGroupWithHealthmonitor(ctx, request, common.ELBHealthmonitor{Type: HealthmonitorTypeTCP}); err == nil {
+		t.Fatal("error creating the monitor should be returned")
+	}
+	if len(server.Pools) != 1 {
+		t.Fatal("pool without monitor is not removed")
+	}
+
+	// a pool returned without its load balancer is removed as well
+	c.GetSigner().NextTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		resp, err := server.RoundTrip(r)
+		if err != nil || r.Method != http.MethodPost || r.URL.Path != "/v2.0/lbaas/pools" {
+			return resp, err
+		}
+		created := common.ELBBackendGroupDetails{}
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+			return nil, err
+		}
+		created.Pool.Loadbalancers = nil
+		b, _ := json.Marshal(created)
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		return resp, nil
+	})
+	if _, _, err := c.AddBackendGroupWithHealthmonitor(ctx, request, common.ELBHealthmonitor{Type: HealthmonitorTypeTCP}); err == nil {
+		t.Fatal("error for the pool without loadbalancer should be returned")
+	}
+	if len(server.Pools) != 1 {
+		t.Fatal("pool without loadbalancer is not removed")
+	}
+}
+
+func TestUpdateHealthmonitorMonitorPort(t *testing.T) {
+	server, c, _ := newFakeClients()
+	ctx := context.Background()
+	lb, err := c.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, monitor, err := c.AddBackendGroupWithHealthmonitor(ctx,
+		common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{Protocol: "TCP", LbAlgorithm: "ROUND_ROBIN", LoadbalancerID: lb.Loadbalancer.ID}},
+		common.ELBHealthmonitor{Type: HealthmonitorTypeTCP, Delay: 5, Timeout: 3, MaxRetries: 3, MonitorPort: 8080})
+	if err != nil || pool.Pool.HealthmonitorID == "" {
+		t.Fatal(err)
+	}
+
+	zero := int64(0)
+	if _, err := c.UpdateHealthmonitor(ctx, monitor.ID, common.ELBHealthmonitorUpdate{MonitorPort: &zero}); err == nil {
+		t.Fatal("monitor port 0 should be rejected")
+	}
+	if _, err := c.UpdateHealthmonitor(ctx, monitor.ID, common.ELBHealthmonitorUpdate{ClearMonitorPort: true}); err != nil {
+		t.Fatal(err)
+	}
+	if port := server.Healthmonitors[monitor.ID].MonitorPort; port != 0 {
+		t.Fatalf("monitor port is not reset: %d", port)
+	}
+}
+
+func TestListHealthmonitors(t *testing.T) {
+	_, c, _ := newFakeClients()
+	ctx := context.Background()
+	lb, err := c.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, monitorType := range []string{HealthmonitorTypeHTTP, HealthmonitorTypeTCP, HealthmonitorTypeHTTP, HealthmonitorTypeHTTP} {
+		_, monitor, err := c.AddBackendGroupWithHealthmonitor(ctx,
+			common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{Protocol: "TCP", LbAlgorithm: "ROUND_ROBIN", LoadbalancerID: lb.Loadbalancer.ID}},
+			common.ELBHealthmonitor{Type: monitorType, Delay: 5, Timeout: 3, MaxRetries: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if monitorType == HealthmonitorTypeHTTP {
+			ids = append(ids, monitor.ID)
+		}
+	}
+
+	// the pages of two monitors are followed by their next links
+	monitors, err := c.ListHealthmonitors(ctx, &common.ELBHealthmonitorListRequest{Type: HealthmonitorTypeHTTP, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 3 {
+		t.Fatalf("unexpected monitors %#v", monitors)
+	}
+	for i, monitor := range monitors {
+		if monitor.ID != ids[i] {
+			t.Fatalf("unexpected monitors %#v", monitors)
+		}
+	}
+}
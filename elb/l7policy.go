@@ -0,0 +1,246 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const (
+	L7PolicyActionRedirectToPool     = "REDIRECT_TO_POOL"
+	L7PolicyActionRedirectToListener = "REDIRECT_TO_LISTENER"
+
+	L7RuleTypeHostName = "HOST_NAME"
+	L7RuleTypePath     = "PATH"
+
+	L7RuleCompareTypeEqualTo    = "EQUAL_TO"
+	L7RuleCompareTypeRegex      = "REGEX"
+	L7RuleCompareTypeStartsWith = "STARTS_WITH"
+)
+
+func validateL7Policy(policy *common.ELBL7Policy) error {
+	if policy.ListenerID == "" {
+		return errors.New("listener id is required")
+	}
+	switch policy.Action {
+	case L7PolicyActionRedirectToPool:
+		if policy.RedirectPoolID == "" {
+			return fmt.Errorf("redirect pool id is required for %s", policy.Action)
+		}
+	case L7PolicyActionRedirectToListener:
+		if policy.RedirectListenerID == "" {
+			return fmt.Errorf("redirect listener id is required for %s", policy.Action)
+		}
+	default:
+		return fmt.Errorf("unsupported l7policy action %q", policy.Action)
+	}
+	return nil
+}
+
+func validateL7Rule(ruleType, compareType, value string) error {
+	if value == "" {
+		return errors.New("rule value is required")
+	}
+	switch ruleType {
+	case L7RuleTypeHostName:
+		if compareType != L7RuleCompareTypeEqualTo {
+			return fmt.Errorf("%s rules only support %s", ruleType, L7RuleCompareTypeEqualTo)
+		}
+	case L7RuleTypePath:
+		switch compareType {
+		case L7RuleCompareTypeEqualTo, L7RuleCompareTypeStartsWith:
+			if !strings.HasPrefix(value, "/") {
+				return fmt.Errorf("path %q has to start with /", value)
+			}
+		case L7RuleCompareTypeRegex:
+		default:
+			return fmt.Errorf("unsupported compare type %q", compareType)
+		}
+	default:
+		return fmt.Errorf("unsupported rule type %q", ruleType)
+	}
+	return nil
+}
+
+func (c *Client) CreateL7Policy(ctx context.Context, input *common.ELBL7PolicyRequest) (*common.ELBL7Policy, error) {
+	if err := validateL7Policy(&input.L7Policy); err != nil {
+		return nil, err
+	}
+	rtn := common.ELBL7PolicyDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("l7policies"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.L7Policy, nil
+}
+
+func (c *Client) GetL7Policy(ctx context.Context, id string) (*common.ELBL7Policy, error) {
+	if id == "" {
+		return nil, errors.New("[GetL7Policy]l7policy id is required")
+	}
+	rtn := common.ELBL7PolicyDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("l7policies", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.L7Policy, nil
+}
+
+// ListL7Policies returns the l7policies matching filter, all pages are followed
+func (c *Client) ListL7Policies(ctx context.Context, filter *common.ELBL7PolicyListRequest) ([]common.ELBL7Policy, error) {
+	var rtn []common.ELBL7Policy
+	next := common.WithQuery(c.GetURL("l7policies"), filter)
+	for next != "" {
+		page := common.ELBL7PolicyList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.L7Policies...)
+		next = nextLink(page.L7PoliciesLinks)
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateL7Policy(ctx context.Context, id string, update common.ELBL7PolicyUpdate) (*common.ELBL7Policy, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateL7Policy]l7policy id is required")
+	}
+	rtn := common.ELBL7PolicyDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("l7policies", id),
+		&common.ELBL7PolicyUpdateRequest{L7Policy: update},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.L7Policy, nil
+}
+
+// DeleteL7Policy deletes the policy together with its rules
+func (c *Client) DeleteL7Policy(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteL7Policy]l7policy id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("l7policies", id),
+		nil,
+		nil,
+	)
+	return err
+}
+
+func (c *Client) CreateL7Rule(ctx context.Context, policyID string, input *common.ELBL7RuleRequest) (*common.ELBL7Rule, error) {
+	if policyID == "" {
+		return nil, errors.New("[CreateL7Rule]l7policy id is required")
+	}
+	if err := validateL7Rule(input.Rule.Type, input.Rule.CompareType, input.Rule.Value); err != nil {
+		return nil, err
+	}
+	rtn := common.ELBL7RuleDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("l7policies", policyID, "rules"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Rule, nil
+}
+
+func (c *Client) GetL7Rule(ctx context.Context, policyID, ruleID string) (*common.ELBL7Rule, error) {
+	if policyID == "" || ruleID == "" {
+		return nil, errors.New("l7policy id and rule id is both required")
+	}
+	rtn := common.ELBL7RuleDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("l7policies", policyID, "rules", ruleID),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Rule, nil
+}
+
+// ListL7Rules returns the rules of the policy matching filter, all pages are followed
+func (c *Client) ListL7Rules(ctx context.Context, policyID string, filter *common.ELBL7RuleListRequest) ([]common.ELBL7Rule, error) {
+	if policyID == "" {
+		return nil, errors.New("[ListL7Rules]l7policy id is required")
+	}
+	var rtn []common.ELBL7Rule
+	next := common.WithQuery(c.GetURL("l7policies", policyID, "rules"), filter)
+	for next != "" {
+		page := common.ELBL7RuleList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Rules...)
+		next = nextLink(page.RulesLinks)
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateL7Rule(ctx context.Context, policyID, ruleID string, update common.ELBL7RuleUpdate) (*common.ELBL7Rule, error) {
+	if policyID == "" || ruleID == "" {
+		return nil, errors.New("l7policy id and rule id is both required")
+	}
+	rtn := common.ELBL7RuleDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("l7policies", policyID, "rules", ruleID),
+		&common.ELBL7RuleUpdateRequest{Rule: update},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Rule, nil
+}
+
+func (c *Client) DeleteL7Rule(ctx context.Context, policyID, ruleID string) error {
+	if policyID == "" || ruleID == "" {
+		return errors.New("l7policy id and rule id is both required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("l7policies", policyID, "rules", ruleID),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,235 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RouteActionNone   = "none"
+	RouteActionCreate = "create"
+	RouteActionDelete = "delete"
+	RouteActionUpdate = "update"
+)
+
+// L7Route forwards the requests matching host and path to a pool, an empty
+// host or path matches everything
+type L7Route struct {
+	Host string
+	Path string
+	// PathCompareType defaults to STARTS_WITH
+	PathCompareType string
+	PoolID          string
+}
+
+func (r *L7Route) compareType() string {
+	if r.PathCompareType == "" {
+		return L7RuleCompareTypeStartsWith
+	}
+	return r.PathCompareType
+}
+
+func (r *L7Route) key() string {
+	if r.Path == "" {
+		return r.Host
+	}
+	return fmt.Sprintf("%s|%s|%s", r.Host, r.compareType(), r.Path)
+}
+
+func (r *L7Route) validate() error {
+	if r.PoolID == "" {
+		return errors.New("pool id is required")
+	}
+	if r.Host == "" && r.Path == "" {
+		return errors.New("one of host and path is required")
+	}
+	if r.Host != "" {
+		if err := validateL7Rule(L7RuleTypeHostName, L7RuleCompareTypeEqualTo, r.Host); err != nil {
+			return err
+		}
+	}
+	if r.Path != "" {
+		return validateL7Rule(L7RuleTypePath, r.compareType(), r.Path)
+	}
+	return nil
+}
+
+func (r *L7Route) rules() []common.ELBL7Rule {
+	var rules []common.ELBL7Rule
+	if r.Host != "" {
+		rules = append(rules, common.ELBL7Rule{Type: L7RuleTypeHostName, CompareType: L7RuleCompareTypeEqualTo, Value: r.Host})
+	}
+	if r.Path != "" {
+		rules = append(rules, common.ELBL7Rule{Type: L7RuleTypePath, CompareType: r.compareType(), Value: r.Path})
+	}
+	return rules
+}
+
+// routeFromRules returns the route of a policy, ok is false when the rules
+// cannot be created by SyncL7Routes
+func routeFromRules(rules []common.ELBL7Rule) (route L7Route, ok bool) {
+	for _, rule := range rules {
+		if rule.Invert || rule.Key != "" {
+			return route, false
+		}
+		switch {
+		case rule.Type == L7RuleTypeHostName && route.Host == "":
+			route.Host = rule.Value
+		case rule.Type == L7RuleTypePath && route.Path == "":
+			route.Path = rule.Value
+			route.PathCompareType = rule.CompareType
+		default:
+			return route, false
+		}
+	}
+	return route, route.Host != "" || route.Path != ""
+}
+
+// L7RouteSyncResult is the outcome of one policy of SyncL7Routes
+type L7RouteSyncResult struct {
+	Route    L7Route
+	PolicyID string
+	Action   string
+	Err      error
+}
+
+// SyncL7Routes makes the REDIRECT_TO_POOL policies of the listener match
+// routes. Policies are matched by their host and path rules: missing ones are
+// created, the ones forwarding to another pool are updated and the others are
+// deleted. REDIRECT_TO_LISTENER policies are left alone. The load balancer
+// is immutable while it applies a change, so changes are made one by one:
+// every change waits for the load balancer to be ACTIVE again and is retried
+// while it is rejected with 409.
+func (c *Client) SyncL7Routes(ctx context.Context, listenerID string, routes []L7Route) ([]L7RouteSyncResult, error) {
+	if listenerID == "" {
+		return nil, errors.New("[SyncL7Routes]listener id is required")
+	}
+	wanted := map[string]L7Route{}
+	var order []string
+	for _, route := range routes {
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("invalid route %s%s: %v", route.Host, route.Path, err)
+		}
+		key := route.key()
+		if existing, ok := wanted[key]; ok {
+			if existing.PoolID != route.PoolID {
+				return nil, fmt.Errorf("route %s%s points to pool %s and %s", route.Host, route.Path, existing.PoolID, route.PoolID)
+			}
+			continue
+		}
+		wanted[key] = route
+		order = append(order, key)
+	}
+
+	listener, err := c.GetListener(ctx, listenerID)
+	if err != nil {
+		return nil, err
+	}
+	loadbalancerID := listenerLoadBalancerID(&listener.Listener)
+	policies, err := c.ListL7Policies(ctx, &common.ELBL7PolicyListRequest{
+		ListenerID: listenerID,
+		Action:     L7PolicyActionRedirectToPool,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results []L7RouteSyncResult
+	found := map[string]bool{}
+	for _, policy := range policies {
+		rules, err := c.ListL7Rules(ctx, policy.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		route, ok := routeFromRules(rules)
+		route.PoolID = policy.RedirectPoolID
+		key := route.key()
+		desired, isWanted := wanted[key]
+		if !ok || !isWanted || found[key] {
+			results = append(results, L7RouteSyncResult{Route: route, PolicyID: policy.ID, Action: RouteActionDelete})
+			continue
+		}
+		found[key] = true
+		result := L7RouteSyncResult{Route: desired, PolicyID: policy.ID, Action: RouteActionNone}
+		if desired.PoolID != policy.RedirectPoolID {
+			result.Action = RouteActionUpdate
+		}
+		results = append(results, result)
+	}
+	for _, key := range order {
+		if !found[key] {
+			results = append(results, L7RouteSyncResult{Route: wanted[key], Action: RouteActionCreate})
+		}
+	}
+
+	// deletions go last so that traffic of moved routes is never dropped
+	failed := 0
+	for _, action := range []string{RouteActionUpdate, RouteActionCreate, RouteActionDelete} {
+		for i := range results {
+			result := &results[i]
+			if result.Action != action {
+				continue
+			}
+			switch action {
+			case RouteActionUpdate:
+				result.Err = c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+					_, err := c.UpdateL7Policy(ctx, result.PolicyID, common.ELBL7PolicyUpdate{RedirectPoolID: result.Route.PoolID})
+					return err
+				})
+			case RouteActionCreate:
+				result.PolicyID, result.Err = c.createL7Route(ctx, loadbalancerID, listenerID, result.Route)
+			case RouteActionDelete:
+				result.Err = c.deleteWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+					return c.DeleteL7Policy(ctx, result.PolicyID)
+				})
+			}
+			if result.Err != nil {
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of the l7policy changes of listener %s failed", failed, listenerID)
+	}
+	return results, nil
+}
+
+// createL7Route creates the policy and its rules one after another, the
+// policy is deleted again when one of the rules cannot be created
+func (c *Client) createL7Route(ctx context.Context, loadbalancerID, listenerID string, route L7Route) (string, error) {
+	var policy *common.ELBL7Policy
+	err := c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+		var err error
+		policy, err = c.CreateL7Policy(ctx, &common.ELBL7PolicyRequest{L7Policy: common.ELBL7Policy{
+			ListenerID:     listenerID,
+			Action:         L7PolicyActionRedirectToPool,
+			RedirectPoolID: route.PoolID,
+		}})
+		return err
+	})
+	for _, rule := range route.rules() {
+		if err != nil {
+			break
+		}
+		rule := rule
+		err = c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+			_, err := c.CreateL7Rule(ctx, policy.ID, &common.ELBL7RuleRequest{Rule: rule})
+			return err
+		})
+	}
+	if err != nil {
+		if policy != nil {
+			derr := c.deleteWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+				return c.DeleteL7Policy(ctx, policy.ID)
+			})
+			if derr != nil {
+				logrus.Errorf("error deleting l7policy %s after failing to create its rules: %v", policy.ID, derr)
+			}
+		}
+		return "", err
+	}
+	return policy.ID, nil
+}
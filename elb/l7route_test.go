@@ -0,0 +1,120 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+)
+
+// newFakeL7Listener creates a listener and a pool for every name
+func newFakeL7Listener(t *testing.T, pools ...string) (*elbtest.Server, *Client, string, map[string]string) {
+	server, c, ids := newFakeListeners(t, 80)
+	lbID := server.Listeners[ids[0]].LoadbalancerID
+	poolIDs := map[string]string{}
+	for _, name := range pools {
+		pool, err := c.AddBackendGroup(context.Background(), common.ELBBackendGroupRequest{Pool: common.ELBBackendGroup{
+			Name:           name,
+			Protocol:       "HTTP",
+			LbAlgorithm:    "ROUND_ROBIN",
+			LoadbalancerID: lbID,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		poolIDs[name] = pool.Pool.ID
+	}
+	return server, c, ids[0], poolIDs
+}
+
+func TestSyncL7Routes(t *testing.T) {
+	server, c, listenerID, pools := newFakeL7Listener(t, "web", "old", "api", "admin")
+	ctx := context.Background()
+	seed := func(poolID string, rule common.ELBL7Rule) string {
+		policy, err := c.CreateL7Policy(ctx, &common.ELBL7PolicyRequest{L7Policy: common.ELBL7Policy{
+			ListenerID:     listenerID,
+			Action:         L7PolicyActionRedirectToPool,
+			RedirectPoolID: poolID,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.CreateL7Rule(ctx, policy.ID, &common.ELBL7RuleRequest{Rule: rule}); err != nil {
+			t.Fatal(err)
+		}
+		return policy.ID
+	}
+	seed(pools["web"], common.ELBL7Rule{Type: L7RuleTypeHostName, CompareType: L7RuleCompareTypeEqualTo, Value: "www.example.com"})
+	move := seed(pools["old"], common.ELBL7Rule{Type: L7RuleTypePath, CompareType: L7RuleCompareTypeStartsWith, Value: "/api"})
+	seed(pools["web"], common.ELBL7Rule{Type: L7RuleTypeHostName, CompareType: L7RuleCompareTypeEqualTo, Value: "old.example.com"})
+	redirect, err := c.CreateL7Policy(ctx, &common.ELBL7PolicyRequest{L7Policy: common.ELBL7Policy{
+		ListenerID:         listenerID,
+		Action:             L7PolicyActionRedirectToListener,
+		RedirectListenerID: "https",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.PendingPolls = 2
+
+	results, err := c.SyncL7Routes(ctx, listenerID, []L7Route{
+		{Host: "www.example.com", PoolID: pools["web"]},
+		{Path: "/api", PoolID: pools["api"]},
+		{Host: "admin.example.com", Path: "/", PoolID: pools["admin"]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := map[string]string{}
+	created := ""
+	for _, result := range results {
+		actions[result.Route.Host+result.Route.Path] = result.Action
+		if result.Action == RouteActionCreate {
+			created = result.PolicyID
+		}
+	}
+	expected := map[string]string{
+		"www.example.com":    RouteActionNone,
+		"/api":               RouteActionUpdate,
+		"old.example.com":    RouteActionDelete,
+		"admin.example.com/": RouteActionCreate,
+	}
+	for route, action := range expected {
+		if actions[route] != action {
+			t.Fatalf("%s: got action %s, want %s", route, actions[route], action)
+		}
+	}
+	if _, ok := server.L7Policies[redirect.ID]; !ok {
+		t.Fatal("REDIRECT_TO_LISTENER policy should be left alone")
+	}
+	if len(server.L7Policies) != 4 || server.L7Policies[move].RedirectPoolID != pools["api"] {
+		t.Fatalf("listener is not synced: %#v", server.L7Policies)
+	}
+	if rules := server.L7Rules[created]; len(rules) != 2 {
+		t.Fatalf("unexpected rules of the created policy %#v", rules)
+	}
+
+	if _, err := c.SyncL7Routes(ctx, listenerID, []L7Route{{Host: "www.example.com", PoolID: pools["web"]}, {Host: "www.example.com", PoolID: pools["api"]}}); err == nil {
+		t.Fatal("conflicting routes should be rejected")
+	}
+	if _, err := c.SyncL7Routes(ctx, listenerID, []L7Route{{Path: "api", PoolID: pools["api"]}}); err == nil {
+		t.Fatal("path without leading slash should be rejected")
+	}
+}
+
+func TestSyncL7RoutesRollback(t *testing.T) {
+	server, c, listenerID, pools := newFakeL7Listener(t, "web")
+	server.PendingPolls = 2
+	// the policy is created, its first rule fails and the policy is deleted
+	// once the load balancer accepts changes again
+	server.FailNext(http.MethodPost, "/v2.0/lbaas/l7policies/*", http.StatusBadRequest)
+	results, err := c.SyncL7Routes(context.Background(), listenerID, []L7Route{{Host: "www.example.com", Path: "/", PoolID: pools["web"]}})
+	if err == nil || results[0].Err == nil {
+		t.Fatal("failed rule should be reported")
+	}
+	if len(server.L7Policies) != 0 {
+		t.Fatalf("policy without rules is not deleted: %#v", server.L7Policies)
+	}
+}
@@ -43,7 +43,7 @@ func (c *Client) GetListenersByELBID(ctx context.Context, loadBalancerId string)
 	_, err := c.DoRequest(
 		ctx,
 		http.MethodGet,
-		c.GetURL("listeners?loadBalancerId="+loadBalancerId),
+		c.GetURL("listeners?loadbalancer_id="+loadBalancerId),
 		nil,
 		&rtn,
 	)
@@ -105,3 +105,11 @@ func (c *Client) DeleteListener(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// listenerLoadBalancerID returns the id of the load balancer of the listener
+func listenerLoadBalancerID(listener *common.ELBListenerInfoObject) string {
+	if len(listener.Loadbalancers) > 0 {
+		return listener.Loadbalancers[0].ID
+	}
+	return listener.LoadbalancerID
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
 )
 
+// GetLoadBalancers returns the first page of load balancers, see ListLoadBalancers
 func (c *Client) GetLoadBalancers(ctx context.Context) (*common.LoadBalancerList, error) {
 	rtn := common.LoadBalancerList{}
 	_, err := c.DoRequest(
@@ -23,6 +25,27 @@ func (c *Client) GetLoadBalancers(ctx context.Context) (*common.LoadBalancerList
 	return &rtn, nil
 }
 
+// ListLoadBalancers returns the load balancers matching filter, all pages are followed
+func (c *Client) ListLoadBalancers(ctx context.Context, filter *common.LoadBalancerListRequest) ([]common.LoadbalancerObject, error) {
+	var rtn []common.LoadbalancerObject
+	next := common.WithQuery(c.GetURL("loadbalancers"), filter)
+	for next != "" {
+		page := common.LoadBalancerList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.LoadBalancers...)
+		next = nextLink(page.LoadBalancersLinks)
+	}
+	return rtn, nil
+}
+
 func (c *Client) GetLoadBalancer(ctx context.Context, id string) (*common.LoadBalancerInfo, error) {
 	if id == "" {
 		return nil, errors.New("loadbalancer id is required")
@@ -50,7 +73,7 @@ func (c *Client) UpdateLoadBalancer(ctx context.Context, id string, request *com
 		ctx,
 		http.MethodPut,
 		c.GetURL("loadbalancers", id),
-		request,
+		&common.LoadBalancerUpdateRequest{Loadbalancer: *request},
 		&rtn,
 	)
 	if err != nil {
@@ -74,11 +97,40 @@ func (c *Client) DeleteLoadBalancer(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	// shared load balancers are deleted without a job
+	if job.JobID == "" {
+		return c.WaitForLoadBalancerDeleted(ctx, c.StatusPollInterval, c.ProvisioningTimeout, id)
+	}
 	_, _, err = c.WaitForELBJob(ctx, common.DefaultDuration, common.DefaultTimeout, job.JobID)
 	return err
 }
 
+// WaitForLoadBalancerDeleted waits until the load balancer is not found any
+// more, the defaults of the status waiters are used when duration or timeout is 0
+func (c *Client) WaitForLoadBalancerDeleted(ctx context.Context, duration, timeout time.Duration, id string) error {
+	duration, timeout = statusWaitDefaults(duration, timeout)
+	return common.CustomWaitForCompleteUntilTrue(ctx, duration, timeout, func(ictx context.Context) (bool, error) {
+		_, err := c.GetLoadBalancer(ictx, id)
+		if IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// IsNotFound reports whether err is the 404 of a resource which does not exist
+func IsNotFound(err error) bool {
+	eInfo, ok := err.(*common.ErrorInfo)
+	return ok && eInfo.StatusCode == http.StatusNotFound
+}
+
+// CreateLoadBalancer creates an internal load balancer with a private VIP in
+// the subnet of request.Loadbalancer.VipSubnetID. External load balancers are
+// created the same way and get an EIP bound to the VipPortID of the result.
 func (c *Client) CreateLoadBalancer(ctx context.Context, request *common.LoadBalancerRequest) (*common.LoadBalancerInfo, error) {
+	if request.Loadbalancer.VipSubnetID == "" {
+		return nil, errors.New("[CreateLoadBalancer]vip subnet id is required")
+	}
 	lbInfo := common.LoadBalancerInfo{}
 	_, err := c.DoRequest(
 		ctx,
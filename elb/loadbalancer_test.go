@@ -3,12 +3,138 @@ package elb
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
 	"github.com/cnrancher/huaweicloud-sdk/network"
 	"github.com/sirupsen/logrus"
 )
 
+func newFakeClients() (*elbtest.Server, *Client, *network.Client) {
+	server := elbtest.NewServer()
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	server.Install(base)
+	c := NewClient(base)
+	c.StatusPollInterval = time.Millisecond
+	return server, c, network.NewClient(base)
+}
+
+func TestCreateInternalLoadBalancer(t *testing.T) {
+	server, client, _ := newFakeClients()
+	root := context.Background()
+
+	if _, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{}); err == nil {
+		t.Fatal("expected error without vip subnet id")
+	}
+	up := true
+	rtn, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{
+			UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{
+				Name:         "sdk-test",
+				AdminStateUp: &up,
+			},
+			VipSubnetID: "subnet-1",
+			VIPAddress:  "192.168.0.100",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb := rtn.Loadbalancer
+	if lb.ID == "" || lb.VipPortID == "" || lb.VIPAddress != "192.168.0.100" {
+		t.Fatalf("unexpected load balancer %#v", lb)
+	}
+	if lb.Provider != "vlb" || lb.ProvisioningStatus != common.ProvisioningActive {
+		t.Fatalf("unexpected provider or status %#v", lb)
+	}
+
+	updated, err := client.UpdateLoadBalancer(root, lb.ID, &common.UpdatableLoadBalancerAttribute{Name: "renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Loadbalancer.Name != "renamed" {
+		t.Fatalf("expected renamed load balancer, got %q", updated.Loadbalancer.Name)
+	}
+
+	list, err := client.ListLoadBalancers(root, &common.LoadBalancerListRequest{Name: "renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != lb.ID {
+		t.Fatalf("unexpected list %#v", list)
+	}
+	if len(server.LoadBalancers) != 1 {
+		t.Fatalf("expected one load balancer, got %d", len(server.LoadBalancers))
+	}
+}
+
+func TestCreateExternalLoadBalancer(t *testing.T) {
+	server, client, networkClient := newFakeClients()
+	root := context.Background()
+
+	rtn, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{
+			UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{Name: "sdk-test"},
+			VipSubnetID:                    "subnet-1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eip, err := networkClient.CreateEIP(root, &common.EipAllocArg{
+		EipDesc:   common.PubIP{Type: "5_bgp"},
+		BandWidth: common.BandwidthDesc{Name: "sdk-test", Size: 10, ShrType: "PER", ChgMode: "traffic"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := networkClient.UpdateEIP(root, eip.ID, &common.EipAssocArg{
+		Port: common.PortDesc{PortID: rtn.Loadbalancer.VipPortID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	bound, err := networkClient.GetEIPByPort(root, rtn.Loadbalancer.VipPortID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bound == nil || bound.ID != eip.ID || bound.Status != "ACTIVE" {
+		t.Fatalf("expected eip %s bound to the vip port, got %#v", eip.ID, bound)
+	}
+
+	listener, err := client.CreateListener(root, &common.ELBListenerRequest{
+		Listener: common.ELBListenerRequestObject{
+			Name:           "sdk-test-8080",
+			Protocol:       "TCP",
+			ProtocolPort:   8080,
+			LoadbalancerId: rtn.Loadbalancer.ID,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listeners, err := client.GetListenersByELBID(root, rtn.Loadbalancer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listeners.Listeners) != 1 || listeners.Listeners[0].ProtocolPort != 8080 {
+		t.Fatalf("unexpected listeners %#v", listeners.Listeners)
+	}
+
+	if err := client.DeleteLoadBalancer(root, rtn.Loadbalancer.ID); err == nil {
+		t.Fatal("expected conflict deleting load balancer with listener")
+	}
+	if err := client.DeleteListener(root, listener.Listener.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteLoadBalancer(root, rtn.Loadbalancer.ID); err != nil {
+		t.Fatal(err)
+	}
+	if server.EIPs[eip.ID].PortID != "" {
+		t.Fatalf("expected eip %s to be unbound", eip.ID)
+	}
+}
+
 func Test_ELBClient(t *testing.T) {
 	t.Skip()
 	baseClient, err := common.GetBaseClientFromENV()
@@ -32,27 +158,19 @@ func Test_ELBClient(t *testing.T) {
 		t.Skip("need vpc and subnet to test")
 	}
 
-	var vpcID, subnetID string
+	var subnetID string
 
 	for _, subnet := range subnets.Subnets {
-		vpcID = subnet.VpcID
-		subnetID = subnet.ID
+		subnetID = subnet.NeutronSubnetID
 	}
 
 	rtn, err := elbClient.CreateLoadBalancer(root, &common.LoadBalancerRequest{
-		AvailableZone: "cn-north-1a",
-		ChargeMode:    "traffic",
-		EIPType:       "5_bgp",
-		TenantID:      baseClient.ProjectID,
-		LoadBalancerCommonInfo: common.LoadBalancerCommonInfo{
-			Type:        "External",
-			VIPSubnetID: subnetID,
-			VpcID:       vpcID,
-		},
-		UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{
-			AdminStateUp: 1,
-			Bandwidth:    10,
-			Name:         "sdk-test",
+		Loadbalancer: common.LoadbalancerObject{
+			UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{
+				Name: "sdk-test",
+			},
+			TenantID:    baseClient.ProjectID,
+			VipSubnetID: subnetID,
 		},
 	})
 	if err != nil {
@@ -61,25 +179,20 @@ func Test_ELBClient(t *testing.T) {
 	logrus.Debugf("%#v\n", *rtn)
 
 	listener, err := elbClient.CreateListener(root, &common.ELBListenerRequest{
-		ELBListenerCommon: common.ELBListenerCommon{
-			LoadbalancerID:  rtn.ID,
-			Protocol:        "TCP",
-			BackendProtocol: "TCP",
-			SessionSticky:   true,
-		},
-		UpdatableELBListenerAttribute: common.UpdatableELBListenerAttribute{
-			Port:        8080,
-			BackendPort: 8080,
-			Name:        "sdk-test-8080",
+		Listener: common.ELBListenerRequestObject{
+			Name:           "sdk-test-8080",
+			Protocol:       "TCP",
+			ProtocolPort:   8080,
+			LoadbalancerId: rtn.Loadbalancer.ID,
 		},
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := elbClient.DeleteListener(root, listener.ID); err != nil {
+	if err := elbClient.DeleteListener(root, listener.Listener.ID); err != nil {
 		t.Fatal(err)
 	}
-	if err := elbClient.DeleteLoadBalancer(root, rtn.ID); err != nil {
+	if err := elbClient.DeleteLoadBalancer(root, rtn.Loadbalancer.ID); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -98,7 +211,7 @@ func Test_deleteLB(t *testing.T) {
 	}
 	for _, lb := range lbs.LoadBalancers {
 		println(lb.ID)
-		if err := elbClient.DeleteLoadBalancer(root, lb.ID); err != nil {
+		if _, err := elbClient.DeleteLoadBalancerCascade(root, lb.ID, nil); err != nil {
 			logrus.Error(err)
 		}
 	}
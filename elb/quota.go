@@ -1 +1,31 @@
 package elb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// quota types of elb
+const (
+	QuotaELB      = "elb"
+	QuotaListener = "listener"
+)
+
+// GetQuotas returns quota and usage of the project, they are only served by the v1.0 elbaas api
+func (c *Client) GetQuotas(ctx context.Context) (*common.ELBQuotaList, error) {
+	rtn := common.ELBQuotaList{}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/v1.0/%s/elbaas/quotas", c.GetAPIEndpointFunc(), c.ProjectID),
+		nil,
+		&rtn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
@@ -0,0 +1,457 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	DefaultCertificateGracePeriod   = 10 * time.Minute
+	DefaultCertificateExpiryWarning = 30 * 24 * time.Hour
+)
+
+// CertificateSource returns the PEM certificate and private key which should be served
+type CertificateSource interface {
+	Load(ctx context.Context) (certificate, privateKey string, err error)
+}
+
+// FileCertificateSource reads a PEM file pair
+type FileCertificateSource struct {
+	CertFile string
+	KeyFile  string
+}
+
+func (s *FileCertificateSource) Load(ctx context.Context) (string, string, error) {
+	cert, err := ioutil.ReadFile(s.CertFile)
+	if err != nil {
+		return "", "", err
+	}
+	key, err := ioutil.ReadFile(s.KeyFile)
+	if err != nil {
+		return "", "", err
+	}
+	return string(cert), string(key), nil
+}
+
+// SecretCertificateSource reads a kubernetes.io/tls secret, the CoreV1Client of
+// cce.K8sClient can be used as Client
+type SecretCertificateSource struct {
+	Client    corev1.SecretsGetter
+	Namespace string
+	Name      string
+}
+
+func (s *SecretCertificateSource) Load(ctx context.Context) (string, string, error) {
+	secret, err := s.Client.Secrets(s.Namespace).Get(s.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	if secret.Type != v1.SecretTypeTLS {
+		return "", "", fmt.Errorf("secret %s/%s is %s instead of %s", s.Namespace, s.Name, secret.Type, v1.SecretTypeTLS)
+	}
+	return string(secret.Data[v1.TLSCertKey]), string(secret.Data[v1.TLSPrivateKeyKey]), nil
+}
+
+// RotationResult is returned by CertificateRotator.Sync
+type RotationResult struct {
+	Rotated       bool
+	CertificateID string
+	// ReplacedIDs are the certificates which were served by the rotator before
+	// and will be deleted after the grace period
+	ReplacedIDs []string
+	// DeletedIDs are the replaced certificates which are deleted by this sync
+	DeletedIDs []string
+	Details    *CertificateDetails
+	Expiring   bool
+}
+
+type pendingCertificate struct {
+	id       string
+	deleteAt time.Time
+}
+
+// CertificateRotator keeps the TLS certificate of listeners in line with a
+// CertificateSource. When the content of the source changes a new certificate
+// is uploaded and all listeners are moved to it, if one of the listeners fails
+// the others are moved back and the new certificate is deleted. Replaced
+// certificates are deleted after GracePeriod.
+//
+// The listeners are moved one after another, so the move is not atomic: until
+// the last listener is moved, or moved back after a failure, some listeners
+// serve the new certificate and the others the old one.
+type CertificateRotator struct {
+	Client      *Client
+	Source      CertificateSource
+	ListenerIDs []string
+	// Name prefixes the names of uploaded certificates. When it is set the
+	// certificates of an earlier rotator with the same Name which are not
+	// served by the listeners any more are deleted after GracePeriod too, so
+	// it has to be unique for every rotator.
+	Name string
+	// SNI replaces the certificate in sni_container_refs instead of default_tls_container_ref
+	SNI           bool
+	GracePeriod   time.Duration
+	ExpiryWarning time.Duration
+	// OnExpiring is called on every sync while the certificate of the source
+	// is expired or close to expiry, also when it cannot be rotated. id is the
+	// certificate served by the rotator.
+	OnExpiring func(id string, details *CertificateDetails)
+
+	mu            sync.Mutex
+	certificateID string
+	fingerprint   string
+	discovered    bool
+	pending       []pendingCertificate
+}
+
+// Run syncs every interval until ctx is done, errors are logged and retried
+func (r *CertificateRotator) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := r.Sync(ctx); err != nil {
+			logrus.Errorf("error rotating elb certificate: %v", err)
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Sync uploads and serves the certificate of the source when it has changed
+// and deletes the replaced certificates whose grace period is over
+func (r *CertificateRotator) Sync(ctx context.Context) (*RotationResult, error) {
+	if r.Client == nil || r.Source == nil || len(r.ListenerIDs) == 0 {
+		return nil, errors.New("client, source and listener ids are required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cert, key, err := r.Source.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// an expired source is parsed as well, so that it is reported
+	details, err := parseCertificatePair(cert, key)
+	if err != nil {
+		return nil, err
+	}
+	result := &RotationResult{Details: details}
+	warning := r.ExpiryWarning
+	if warning == 0 {
+		warning = DefaultCertificateExpiryWarning
+	}
+	expiring := time.Until(details.NotAfter) < warning
+
+	if r.fingerprint == "" || !r.discovered {
+		listeners, err := r.getListeners(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if r.fingerprint == "" {
+			if err := r.adopt(ctx, listeners, details.Fingerprint); err != nil {
+				return nil, err
+			}
+		}
+		if !r.discovered {
+			if err := r.discoverPending(ctx, listeners); err != nil {
+				return nil, err
+			}
+			r.discovered = true
+		}
+	}
+	if r.fingerprint != details.Fingerprint {
+		// an expired source is rejected on upload
+		if err := r.rotate(ctx, cert, key, details, result); err != nil {
+			if expiring && r.OnExpiring != nil {
+				r.OnExpiring(r.certificateID, details)
+			}
+			return nil, err
+		}
+	}
+	result.CertificateID = r.certificateID
+	result.DeletedIDs = r.deleteExpiredPending(ctx)
+
+	if expiring {
+		result.Expiring = true
+		if r.OnExpiring != nil {
+			r.OnExpiring(r.certificateID, details)
+		}
+	}
+	return result, nil
+}
+
+func (r *CertificateRotator) getListeners(ctx context.Context) ([]common.ELBListenerInfoObject, error) {
+	var listeners []common.ELBListenerInfoObject
+	for _, listenerID := range r.ListenerIDs {
+		listener, err := r.Client.GetListener(ctx, listenerID)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, listener.Listener)
+	}
+	return listeners, nil
+}
+
+// adopt takes over a certificate which is already served by one of the
+// listeners when it has the same content as the source, the listeners which
+// do not serve it yet are moved to it
+func (r *CertificateRotator) adopt(ctx context.Context, listeners []common.ELBListenerInfoObject, fingerprint string) error {
+	checked := map[string]bool{}
+	for i := range listeners {
+		for _, id := range r.servedCertificates(&listeners[i]) {
+			if checked[id] {
+				continue
+			}
+			checked[id] = true
+			info, err := r.Client.GetCertificate(ctx, id)
+			if err != nil {
+				return err
+			}
+			details, err := parseLeafCertificate(info.Certificate)
+			if err != nil || details.Fingerprint != fingerprint {
+				continue
+			}
+			for j := range listeners {
+				if servesCertificate(r.servedCertificates(&listeners[j]), id) {
+					continue
+				}
+				if err := r.updateListener(ctx, &listeners[j], r.listenerUpdate(&listeners[j], id)); err != nil {
+					return fmt.Errorf("error moving listener %s to certificate %s: %v", listeners[j].ID, id, err)
+				}
+			}
+			r.certificateID = id
+			r.fingerprint = fingerprint
+			return nil
+		}
+	}
+	return nil
+}
+
+// discoverPending schedules the deletion of the certificates uploaded by an
+// earlier rotator with the same Name which are not served by the listeners,
+// pending certificates are only kept in memory and would leak otherwise
+func (r *CertificateRotator) discoverPending(ctx context.Context, listeners []common.ELBListenerInfoObject) error {
+	if r.Name == "" {
+		return nil
+	}
+	uploaded := regexp.MustCompile("^" + regexp.QuoteMeta(r.Name) + "-[0-9a-f]{12}$")
+	served := map[string]bool{r.certificateID: true}
+	for _, listener := range listeners {
+		served[listener.DefaultTlsContainerRef] = true
+		for _, id := range listener.SniContainerRefs {
+			served[id] = true
+		}
+	}
+	for _, p := range r.pending {
+		served[p.id] = true
+	}
+	certificates, err := r.Client.ListCertificates(ctx, &common.ELBCertificateListRequest{Limit: 100})
+	if err != nil {
+		return err
+	}
+	for _, info := range certificates {
+		if uploaded.MatchString(info.Name) && !served[info.ID] {
+			logrus.Infof("elb certificate %s of an earlier rotation is not served by listeners %v, deleting it after the grace period", info.ID, r.ListenerIDs)
+			r.pending = append(r.pending, pendingCertificate{id: info.ID, deleteAt: time.Now().Add(r.gracePeriod())})
+		}
+	}
+	return nil
+}
+
+func (r *CertificateRotator) gracePeriod() time.Duration {
+	if r.GracePeriod == 0 {
+		return DefaultCertificateGracePeriod
+	}
+	return r.GracePeriod
+}
+
+func (r *CertificateRotator) servedCertificates(listener *common.ELBListenerInfoObject) []string {
+	if r.SNI {
+		return listener.SniContainerRefs
+	}
+	if listener.DefaultTlsContainerRef == "" {
+		return nil
+	}
+	return []string{listener.DefaultTlsContainerRef}
+}
+
+func servesCertificate(served []string, id string) bool {
+	for _, ref := range served {
+		if ref == id {
+			return true
+		}
+	}
+	return false
+}
+
+// updateListener changes the listener once its load balancer is ACTIVE
+func (r *CertificateRotator) updateListener(ctx context.Context, listener *common.ELBListenerInfoObject, update *common.ELBListenerUpdateRequest) error {
+	return r.Client.changeWhenActive(ctx, listenerLoadBalancerID(listener), func(ctx context.Context) error {
+		_, err := r.Client.UpdateListener(ctx, listener.ID, update)
+		return err
+	})
+}
+
+func (r *CertificateRotator) rotate(ctx context.Context, cert, key string, details *CertificateDetails, result *RotationResult) error {
+	name := r.Name
+	if name == "" {
+		name = "rotated"
+	}
+	created, err := r.Client.CreateCertificate(ctx, &common.ELBCertificateRequest{
+		UpdatableELBCertificateAttribute: common.UpdatableELBCertificateAttribute{
+			Name:        fmt.Sprintf("%s-%s", name, details.Fingerprint[:12]),
+			Description: fmt.Sprintf("expires at %s", details.NotAfter.UTC().Format(time.RFC3339)),
+		},
+		ELBCertificateCommon: common.ELBCertificateCommon{
+			Type:        CertificateTypeServer,
+			Certificate: cert,
+			PrivateKey:  key,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var previous []common.ELBListenerInfoObject
+	for _, listenerID := range r.ListenerIDs {
+		listener, err := r.Client.GetListener(ctx, listenerID)
+		if err == nil {
+			err = r.updateListener(ctx, &listener.Listener, r.listenerUpdate(&listener.Listener, created.ID))
+		}
+		if err != nil {
+			r.rollback(ctx, previous, created.ID)
+			return fmt.Errorf("error moving listener %s to certificate %s: %v", listenerID, created.ID, err)
+		}
+		previous = append(previous, listener.Listener)
+	}
+
+	// only the certificate which was served by the rotator is deleted, other
+	// certificates replaced on the listeners may still be used elsewhere
+	if r.certificateID != "" {
+		r.pending = append(r.pending, pendingCertificate{id: r.certificateID, deleteAt: time.Now().Add(r.gracePeriod())})
+		result.ReplacedIDs = append(result.ReplacedIDs, r.certificateID)
+	}
+	logrus.Infof("listeners %v are moved to elb certificate %s", r.ListenerIDs, created.ID)
+	r.certificateID = created.ID
+	r.fingerprint = details.Fingerprint
+	result.Rotated = true
+	return nil
+}
+
+// listenerUpdate replaces the served certificate of the rotator with id. For
+// SNI the current certificate of the rotator is replaced and id is appended
+// when the rotator has none yet.
+func (r *CertificateRotator) listenerUpdate(listener *common.ELBListenerInfoObject, id string) *common.ELBListenerUpdateRequest {
+	if !r.SNI {
+		return &common.ELBListenerUpdateRequest{Listener: common.ELBListenerUpdateObject{DefaultTlsContainerRef: id}}
+	}
+	refs := []string{}
+	found := false
+	for _, ref := range listener.SniContainerRefs {
+		if ref == r.certificateID && r.certificateID != "" {
+			ref = id
+			found = true
+		}
+		refs = append(refs, ref)
+	}
+	if !found {
+		refs = append(refs, id)
+	}
+	return &common.ELBListenerUpdateRequest{Listener: common.ELBListenerUpdateObject{SniContainerRefs: refs}}
+}
+
+// rollback moves the listeners back to the certificates they served before,
+// an empty reference is sent as null or an empty list so that it is cleared
+func (r *CertificateRotator) rollback(ctx context.Context, previous []common.ELBListenerInfoObject, createdID string) {
+	for i := range previous {
+		listener := &previous[i]
+		update := &common.ELBListenerUpdateRequest{Listener: common.ELBListenerUpdateObject{
+			DefaultTlsContainerRef:      listener.DefaultTlsContainerRef,
+			ClearDefaultTlsContainerRef: listener.DefaultTlsContainerRef == "",
+		}}
+		if r.SNI {
+			update = &common.ELBListenerUpdateRequest{Listener: common.ELBListenerUpdateObject{
+				SniContainerRefs:      listener.SniContainerRefs,
+				ClearSniContainerRefs: len(listener.SniContainerRefs) == 0,
+			}}
+		}
+		if err := r.updateListener(ctx, listener, update); err != nil {
+			logrus.Errorf("error rolling back certificate of listener %s: %v", listener.ID, err)
+		}
+	}
+	if err := r.Client.DeleteCertificate(ctx, createdID); err != nil {
+		logrus.Errorf("error deleting elb certificate %s after failed rotation: %v", createdID, err)
+	}
+}
+
+// deleteExpiredPending deletes the replaced certificates whose grace period
+// is over, a certificate which is still used by other listeners is kept for
+// another grace period
+func (r *CertificateRotator) deleteExpiredPending(ctx context.Context) []string {
+	var deleted []string
+	var pending []pendingCertificate
+	for _, p := range r.pending {
+		if time.Now().Before(p.deleteAt) {
+			pending = append(pending, p)
+			continue
+		}
+		err := r.Client.DeleteCertificate(ctx, p.id)
+		if IsNotFound(err) {
+			err = nil
+		}
+		if isConflict(err) {
+			logrus.Warnf("replaced elb certificate %s is used by other listeners, will retry after the grace period: %v", p.id, err)
+			p.deleteAt = time.Now().Add(r.gracePeriod())
+			pending = append(pending, p)
+			continue
+		}
+		if err != nil {
+			logrus.Warnf("error deleting replaced elb certificate %s, will retry: %v", p.id, err)
+			pending = append(pending, p)
+			continue
+		}
+		deleted = append(deleted, p.id)
+	}
+	r.pending = pending
+	return deleted
+}
+
+// ExpiringCertificate is returned by ListExpiringCertificates
+type ExpiringCertificate struct {
+	Certificate common.ELBCertificateInfo
+	NotAfter    time.Time
+}
+
+// ListExpiringCertificates returns the certificates which expire within the duration
+func (c *Client) ListExpiringCertificates(ctx context.Context, within time.Duration) ([]ExpiringCertificate, error) {
+	certificates, err := c.ListCertificates(ctx, &common.ELBCertificateListRequest{Limit: 100})
+	if err != nil {
+		return nil, err
+	}
+	var rtn []ExpiringCertificate
+	for _, info := range certificates {
+		details, err := parseLeafCertificate(info.Certificate)
+		if err != nil {
+			logrus.Warnf("error parsing elb certificate %s: %v", info.ID, err)
+			continue
+		}
+		if time.Until(details.NotAfter) < within {
+			rtn = append(rtn, ExpiringCertificate{Certificate: info, NotAfter: details.NotAfter})
+		}
+	}
+	return rtn, nil
+}
@@ -0,0 +1,230 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+)
+
+type staticSource struct {
+	cert, key string
+}
+
+func (s *staticSource) Load(ctx context.Context) (string, string, error) {
+	return s.cert, s.key, nil
+}
+
+// newFakeListeners creates a load balancer with a listener on every port
+func newFakeListeners(t *testing.T, ports ...int64) (*elbtest.Server, *Client, []string) {
+	server, client, _ := newFakeClients()
+	root := context.Background()
+	lb, err := client.CreateLoadBalancer(root, &common.LoadBalancerRequest{Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, port := range ports {
+		listener, err := client.CreateListener(root, &common.ELBListenerRequest{Listener: common.ELBListenerRequestObject{
+			LoadbalancerId: lb.Loadbalancer.ID,
+			Protocol:       "TERMINATED_HTTPS",
+			ProtocolPort:   port,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, listener.Listener.ID)
+	}
+	return server, client, ids
+}
+
+func TestCertificateRotator(t *testing.T) {
+	server, client, ids := newFakeListeners(t, 443, 8443, 9443)
+	server.PendingPolls = 1
+	l1, l2, l3 := ids[0], ids[1], ids[2]
+	source := &staticSource{}
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(10*24*time.Hour))
+	var expiring string
+	rotator := &CertificateRotator{
+		Client:      client,
+		Source:      source,
+		ListenerIDs: []string{l1, l2},
+		GracePeriod: time.Nanosecond,
+		OnExpiring:  func(id string, details *CertificateDetails) { expiring = id },
+	}
+	ctx := context.Background()
+
+	result, err := rotator.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := result.CertificateID
+	if !result.Rotated || server.Listeners[l1].DefaultTlsContainerRef != first || server.Listeners[l2].DefaultTlsContainerRef != first {
+		t.Fatal("listeners are not moved to the new certificate", result)
+	}
+	if !result.Expiring || expiring != first {
+		t.Fatal("certificate close to expiry is not reported")
+	}
+
+	if result, err = rotator.Sync(ctx); err != nil || result.Rotated {
+		t.Fatal("unchanged source should not rotate", err)
+	}
+
+	// a new rotator adopts the certificate which is served by any of its
+	// listeners and moves the other listeners to it
+	adopted := &CertificateRotator{Client: client, Source: source, ListenerIDs: []string{l3, l1}}
+	if result, err = adopted.Sync(ctx); err != nil || result.Rotated || result.CertificateID != first {
+		t.Fatal("served certificate is not adopted", err)
+	}
+	if server.Listeners[l3].DefaultTlsContainerRef != first {
+		t.Fatal("listener is not moved to the adopted certificate")
+	}
+
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	server.FailNext(http.MethodPut, "/v2.0/lbaas/listeners/"+l2, http.StatusBadRequest)
+	if _, err = rotator.Sync(ctx); err == nil {
+		t.Fatal("failed listener update should fail the rotation")
+	}
+	if server.Listeners[l1].DefaultTlsContainerRef != first || len(server.Certificates) != 1 {
+		t.Fatal("failed rotation is not rolled back")
+	}
+
+	server.Listeners[l3].DefaultTlsContainerRef = ""
+	if result, err = rotator.Sync(ctx); err != nil || !result.Rotated || result.Expiring {
+		t.Fatal("rotation failed", err)
+	}
+	second := result.CertificateID
+	if server.Listeners[l2].DefaultTlsContainerRef != second || len(result.ReplacedIDs) != 1 || result.ReplacedIDs[0] != first {
+		t.Fatal("wrong rotation result", result)
+	}
+	if result.DeletedIDs == nil {
+		result, _ = rotator.Sync(ctx)
+	}
+	if _, ok := server.Certificates[first]; ok || len(result.DeletedIDs) != 1 {
+		t.Fatal("replaced certificate is not deleted after the grace period")
+	}
+}
+
+func TestCertificateRotatorSNIRollback(t *testing.T) {
+	server, client, ids := newFakeListeners(t, 443, 8443)
+	source := &staticSource{}
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	rotator := &CertificateRotator{Client: client, Source: source, ListenerIDs: ids, SNI: true}
+
+	server.FailNext(http.MethodPut, "/v2.0/lbaas/listeners/"+ids[1], http.StatusBadRequest)
+	if _, err := rotator.Sync(context.Background()); err == nil {
+		t.Fatal("failed listener update should fail the rotation")
+	}
+	// the listener had no SNI certificates, they have to be cleared again
+	// before the new certificate can be deleted
+	if refs := server.Listeners[ids[0]].SniContainerRefs; len(refs) != 0 {
+		t.Fatalf("sni certificates are not rolled back: %v", refs)
+	}
+	if len(server.Certificates) != 0 {
+		t.Fatal("certificate of the failed rotation is not deleted")
+	}
+}
+
+func TestCertificateRotatorDiscoversPending(t *testing.T) {
+	server, client, ids := newFakeListeners(t, 443)
+	source := &staticSource{}
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	ctx := context.Background()
+	rotator := &CertificateRotator{Client: client, Source: source, ListenerIDs: ids, Name: "web", GracePeriod: time.Hour}
+	result, err := rotator.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := result.CertificateID
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	if result, err = rotator.Sync(ctx); err != nil || !result.Rotated {
+		t.Fatal("rotation failed", err)
+	}
+	other, err := client.CreateCertificate(ctx, &common.ELBCertificateRequest{
+		UpdatableELBCertificateAttribute: common.UpdatableELBCertificateAttribute{Name: "other"},
+		ELBCertificateCommon:             common.ELBCertificateCommon{Certificate: source.cert, PrivateKey: source.key},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the replaced certificate is only pending in the memory of the first
+	// rotator, a restarted one finds it by its name
+	restarted := &CertificateRotator{Client: client, Source: source, ListenerIDs: ids, Name: "web", GracePeriod: time.Nanosecond}
+	if result, err = restarted.Sync(ctx); err != nil || result.Rotated {
+		t.Fatal("restarted rotator should adopt the served certificate", err)
+	}
+	if len(result.DeletedIDs) != 1 || result.DeletedIDs[0] != first {
+		t.Fatalf("replaced certificate of the earlier rotator is not deleted: %v", result.DeletedIDs)
+	}
+	if _, ok := server.Certificates[other.ID]; !ok || len(server.Certificates) != 2 {
+		t.Fatal("certificates of others must be kept")
+	}
+}
+
+func TestCertificateRotatorExpiredSource(t *testing.T) {
+	server, client, ids := newFakeListeners(t, 443)
+	source := &staticSource{}
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(-time.Hour))
+	var expiring []string
+	rotator := &CertificateRotator{
+		Client:      client,
+		Source:      source,
+		ListenerIDs: ids,
+		OnExpiring:  func(id string, details *CertificateDetails) { expiring = append(expiring, id) },
+	}
+	ctx := context.Background()
+
+	// an expired source cannot be uploaded but is reported
+	if _, err := rotator.Sync(ctx); err == nil {
+		t.Fatal("expired certificate should not be uploaded")
+	}
+	if len(expiring) != 1 || len(server.Certificates) != 0 {
+		t.Fatalf("expired certificate is not reported: %v", expiring)
+	}
+
+	// an expired certificate which is already served is adopted and reported
+	server.Certificates["cert-expired"] = &common.ELBCertificateInfo{ID: "cert-expired", ELBCertificateCommon: common.ELBCertificateCommon{Certificate: source.cert, PrivateKey: source.key}}
+	server.Listeners[ids[0]].DefaultTlsContainerRef = "cert-expired"
+	result, err := rotator.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rotated || !result.Expiring || len(expiring) != 2 || expiring[1] != "cert-expired" {
+		t.Fatalf("served expired certificate is not reported: %#v %v", result, expiring)
+	}
+}
+
+func TestCertificateRotatorKeepsUsedCertificate(t *testing.T) {
+	server, client, ids := newFakeListeners(t, 443, 8443)
+	source := &staticSource{}
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	rotator := &CertificateRotator{Client: client, Source: source, ListenerIDs: ids[:1], GracePeriod: time.Nanosecond}
+	ctx := context.Background()
+	result, err := rotator.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := result.CertificateID
+	// the other listener serves the certificate as well, so it cannot be deleted
+	server.Listeners[ids[1]].DefaultTlsContainerRef = first
+
+	source.cert, source.key = newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+	if result, err = rotator.Sync(ctx); err != nil || !result.Rotated {
+		t.Fatal("rotation failed", err)
+	}
+	if result, err = rotator.Sync(ctx); err != nil || len(result.DeletedIDs) != 0 {
+		t.Fatalf("used certificate should not be deleted: %v %v", result.DeletedIDs, err)
+	}
+	if _, ok := server.Certificates[first]; !ok {
+		t.Fatal("used certificate is deleted")
+	}
+
+	server.Listeners[ids[1]].DefaultTlsContainerRef = ""
+	if result, err = rotator.Sync(ctx); err != nil || len(result.DeletedIDs) != 1 || result.DeletedIDs[0] != first {
+		t.Fatalf("certificate should be deleted once it is not used: %v %v", result, err)
+	}
+}
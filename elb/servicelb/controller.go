@@ -0,0 +1,526 @@
+// Package servicelb gives kubernetes services of type LoadBalancer a shared
+// ELB. The cluster is polled, so it only needs the typed core/v1 client.
+package servicelb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// The annotations use an own prefix so that services are not picked up by
+// the CCE cloud controller, which reads kubernetes.io/elb.*
+const (
+	// AnnotationLoadBalancerID reuses an existing load balancer, only the
+	// listeners of the service are removed from it on cleanup
+	AnnotationLoadBalancerID = "elb.cnrancher.io/id"
+	// AnnotationSubnetID is the vip subnet (neutron subnet id) of a created load balancer
+	AnnotationSubnetID = "elb.cnrancher.io/subnet-id"
+	// AnnotationMemberSubnetID is the subnet of the node addresses, the vip subnet is used by default
+	AnnotationMemberSubnetID = "elb.cnrancher.io/member-subnet-id"
+	// AnnotationLBAlgorithm is ROUND_ROBIN, LEAST_CONNECTIONS or SOURCE_IP
+	AnnotationLBAlgorithm = "elb.cnrancher.io/lb-algorithm"
+
+	Finalizer = "elb.cnrancher.io/load-balancer"
+
+	// LabelExcludeNode keeps a node out of all pools
+	LabelExcludeNode = "node.kubernetes.io/exclude-from-external-load-balancers"
+
+	DefaultLBAlgorithm = "ROUND_ROBIN"
+)
+
+// Controller creates a load balancer per service, named after the cluster,
+// namespace and service, and a listener with a pool per service port. The
+// members of the pools are the internal IPs of the Ready nodes and the node
+// ports of the service. The CoreV1Client of cce.K8sClient can be used as
+// Services and Nodes.
+//
+// Shared load balancers reject changes while they are not ACTIVE, so the
+// controller waits for ACTIVE after every change it makes. Changes which
+// are rejected anyway, e.g. because somebody else changed the load balancer,
+// are returned and the service is synced again on the next poll. Every pass
+// checks the whole chain of listener, pool, healthmonitor and members, so a
+// sync which failed halfway is completed by a later one.
+type Controller struct {
+	Client   *elb.Client
+	Services corev1.ServicesGetter
+	Nodes    corev1.NodesGetter
+	// NetworkClient is used to report the EIP bound to the load balancer as
+	// ingress IP, the VIP is reported without it
+	NetworkClient *network.Client
+	// ClusterName makes the load balancer names unique between clusters
+	ClusterName string
+	// SubnetID is the vip subnet of created load balancers, it is overridden by AnnotationSubnetID
+	SubnetID string
+	// MemberSubnetID is the default of AnnotationMemberSubnetID
+	MemberSubnetID string
+}
+
+// Run syncs every interval until ctx is done, errors are logged and retried
+func (c *Controller) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := c.Sync(ctx); err != nil {
+			logrus.Errorf("error syncing service load balancers: %v", err)
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Sync reconciles all services of the cluster
+func (c *Controller) Sync(ctx context.Context) error {
+	if c.Client == nil || c.Services == nil || c.Nodes == nil {
+		return errors.New("client, services and nodes are required")
+	}
+	services, err := c.Services.Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	nodes, err := c.Nodes.Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if err := c.reconcile(ctx, svc, nodes.Items); err != nil {
+			logrus.Errorf("error syncing load balancer of service %s/%s: %v", svc.Namespace, svc.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d services failed", failed, len(services.Items))
+	}
+	return nil
+}
+
+// Reconcile syncs the load balancer of one service. Services which are not
+// of type LoadBalancer any more or are deleted are cleaned up and their
+// finalizer is removed.
+func (c *Controller) Reconcile(ctx context.Context, svc *v1.Service) error {
+	nodes, err := c.Nodes.Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	return c.reconcile(ctx, svc, nodes.Items)
+}
+
+func (c *Controller) reconcile(ctx context.Context, svc *v1.Service, nodes []v1.Node) error {
+	if svc.DeletionTimestamp != nil || svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+		if !hasFinalizer(svc) {
+			return nil
+		}
+		return c.cleanup(ctx, svc)
+	}
+
+	if !hasFinalizer(svc) {
+		updated := svc.DeepCopy()
+		updated.Finalizers = append(updated.Finalizers, Finalizer)
+		var err error
+		if svc, err = c.Services.Services(svc.Namespace).Update(updated); err != nil {
+			return err
+		}
+	}
+
+	lb, err := c.ensureLoadBalancer(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if err := c.ensureListeners(ctx, svc, lb, nodes); err != nil {
+		return err
+	}
+	ip, err := c.ingressIP(ctx, lb)
+	if err != nil {
+		return err
+	}
+	return c.updateStatus(svc, []v1.LoadBalancerIngress{{IP: ip}})
+}
+
+// loadBalancerName is also the prefix of the listener and pool names,
+// namespaces and service names cannot contain underscores
+func (c *Controller) loadBalancerName(svc *v1.Service) string {
+	if c.ClusterName == "" {
+		return fmt.Sprintf("k8s_%s_%s", svc.Namespace, svc.Name)
+	}
+	return fmt.Sprintf("k8s_%s_%s_%s", c.ClusterName, svc.Namespace, svc.Name)
+}
+
+func (c *Controller) listenerName(svc *v1.Service, port v1.ServicePort) string {
+	return fmt.Sprintf("%s_%s_%d", c.loadBalancerName(svc), port.Protocol, port.Port)
+}
+
+// findLoadBalancer returns the load balancer of the service and whether it
+// was created by the controller, nil when there is none
+func (c *Controller) findLoadBalancer(ctx context.Context, svc *v1.Service) (*common.LoadbalancerObject, bool, error) {
+	if id := svc.Annotations[AnnotationLoadBalancerID]; id != "" {
+		info, err := c.Client.GetLoadBalancer(ctx, id)
+		if err != nil {
+			return nil, false, err
+		}
+		return &info.Loadbalancer, false, nil
+	}
+	name := c.loadBalancerName(svc)
+	list, err := c.Client.ListLoadBalancers(ctx, &common.LoadBalancerListRequest{Name: name})
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range list {
+		if list[i].Name == name {
+			return &list[i], true, nil
+		}
+	}
+	return nil, true, nil
+}
+
+func (c *Controller) ensureLoadBalancer(ctx context.Context, svc *v1.Service) (*common.LoadbalancerObject, error) {
+	lb, _, err := c.findLoadBalancer(ctx, svc)
+	if err != nil || lb != nil {
+		return lb, err
+	}
+	subnetID := svc.Annotations[AnnotationSubnetID]
+	if subnetID == "" {
+		subnetID = c.SubnetID
+	}
+	if subnetID == "" {
+		return nil, fmt.Errorf("%s annotation or a default subnet id is required", AnnotationSubnetID)
+	}
+	created, err := c.Client.CreateLoadBalancer(ctx, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{
+			UpdatableLoadBalancerAttribute: common.UpdatableLoadBalancerAttribute{
+				Name:        c.loadBalancerName(svc),
+				Description: fmt.Sprintf("kubernetes service %s/%s", svc.Namespace, svc.Name),
+			},
+			VipSubnetID: subnetID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("created load balancer %s for service %s/%s", created.Loadbalancer.ID, svc.Namespace, svc.Name)
+	if _, err := c.Client.WaitForLoadBalancerActive(ctx, c.Client.StatusPollInterval, c.Client.ProvisioningTimeout, created.Loadbalancer.ID); err != nil {
+		return nil, err
+	}
+	return &created.Loadbalancer, nil
+}
+
+func (c *Controller) ensureListeners(ctx context.Context, svc *v1.Service, lb *common.LoadbalancerObject, nodes []v1.Node) error {
+	list, err := c.Client.GetListenersByELBID(ctx, lb.ID)
+	if err != nil {
+		return err
+	}
+	existing := map[string]common.ELBListenerInfoObject{}
+	for _, listener := range list.Listeners {
+		existing[fmt.Sprintf("%s/%d", listener.Protocol, listener.ProtocolPort)] = listener
+	}
+
+	memberSubnetID := svc.Annotations[AnnotationMemberSubnetID]
+	if memberSubnetID == "" {
+		memberSubnetID = c.MemberSubnetID
+	}
+	if memberSubnetID == "" {
+		memberSubnetID = lb.VipSubnetID
+	}
+	addresses := nodeAddresses(nodes)
+
+	wanted := map[string]bool{}
+	for _, port := range svc.Spec.Ports {
+		if port.Protocol != v1.ProtocolTCP && port.Protocol != v1.ProtocolUDP {
+			return fmt.Errorf("protocol %s of port %d is not supported", port.Protocol, port.Port)
+		}
+		name := c.listenerName(svc, port)
+		wanted[name] = true
+		listener, ok := existing[fmt.Sprintf("%s/%d", port.Protocol, port.Port)]
+		if ok && listener.Name != name {
+			return fmt.Errorf("port %s/%d of load balancer %s is used by listener %s", port.Protocol, port.Port, lb.ID, listener.ID)
+		}
+		if !ok {
+			created, err := c.Client.CreateListener(ctx, &common.ELBListenerRequest{
+				Listener: common.ELBListenerRequestObject{
+					Name:           name,
+					Protocol:       string(port.Protocol),
+					ProtocolPort:   int64(port.Port),
+					LoadbalancerId: lb.ID,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if err := c.waitActive(ctx, lb.ID); err != nil {
+				return err
+			}
+			listener = created.Listener
+		}
+		poolID, err := c.ensurePool(ctx, svc, lb.ID, &listener)
+		if err != nil {
+			return err
+		}
+		desired := make([]common.ELBBackend, 0, len(addresses))
+		for _, address := range addresses {
+			desired = append(desired, common.ELBBackend{
+				Address:      address,
+				ProtocolPort: int64(port.NodePort),
+				SubnetID:     memberSubnetID,
+			})
+		}
+		if _, err := c.Client.SyncBackends(ctx, poolID, desired, nil); err != nil {
+			return err
+		}
+	}
+
+	prefix := c.loadBalancerName(svc) + "_"
+	for _, listener := range list.Listeners {
+		if strings.HasPrefix(listener.Name, prefix) && !wanted[listener.Name] {
+			if err := c.deleteListener(ctx, lb.ID, &listener); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensurePool creates the default pool of the listener with a healthmonitor,
+// or updates the algorithm and session persistence of the existing one. The
+// healthmonitor of an existing pool is created again when it is missing.
+func (c *Controller) ensurePool(ctx context.Context, svc *v1.Service, loadbalancerID string, listener *common.ELBListenerInfoObject) (string, error) {
+	algorithm := svc.Annotations[AnnotationLBAlgorithm]
+	if algorithm == "" {
+		algorithm = DefaultLBAlgorithm
+	}
+	persistence := common.SessionPersistence{}
+	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
+		persistence.Type = "SOURCE_IP"
+	}
+
+	monitor := common.ELBHealthmonitor{
+		Type:       elb.HealthmonitorTypeTCP,
+		Delay:      5,
+		Timeout:    3,
+		MaxRetries: 3,
+	}
+	if listener.Protocol == string(v1.ProtocolUDP) {
+		monitor.Type = elb.HealthmonitorTypeUDPConnect
+	}
+
+	if listener.DefaultPoolID == "" {
+		// the pool and monitor are created once the load balancer is ACTIVE
+		pool, _, err := c.Client.AddBackendGroupWithHealthmonitor(ctx, common.ELBBackendGroupRequest{
+			Pool: common.ELBBackendGroup{
+				Name:               listener.Name,
+				Protocol:           listener.Protocol,
+				LbAlgorithm:        algorithm,
+				ListenerID:         listener.ID,
+				SessionPersistence: persistence,
+			},
+		}, monitor)
+		if err != nil {
+			return "", err
+		}
+		return pool.Pool.ID, nil
+	}
+
+	pool, err := c.Client.GetBackendGroup(ctx, listener.DefaultPoolID)
+	if err != nil {
+		return "", err
+	}
+	if pool.Pool.HealthmonitorID == "" {
+		monitor.PoolID = pool.Pool.ID
+		created, err := c.Client.CreateHealthmonitor(ctx, &common.ELBHealthmonitorRequest{Healthmonitor: monitor})
+		if err != nil {
+			return "", err
+		}
+		logrus.Infof("created missing healthmonitor %s of pool %s", created.ID, pool.Pool.ID)
+		if err := c.waitActive(ctx, loadbalancerID); err != nil {
+			return "", err
+		}
+	}
+	if pool.Pool.LbAlgorithm == algorithm && reflect.DeepEqual(pool.Pool.SessionPersistence, persistence) {
+		return pool.Pool.ID, nil
+	}
+	update := common.ELBBackendGroupUpdate{LbAlgorithm: algorithm}
+	if persistence.Type == "" {
+		update.ClearSessionPersistence = true
+	} else {
+		update.SessionPersistence = &persistence
+	}
+	if _, err := c.Client.UpdateBackendGroup(ctx, pool.Pool.ID, update); err != nil {
+		return "", err
+	}
+	return pool.Pool.ID, c.waitActive(ctx, loadbalancerID)
+}
+
+// deleteListener removes the members, healthmonitor and pool of the listener
+// before the listener itself, waiting for ACTIVE after every delete
+func (c *Controller) deleteListener(ctx context.Context, loadbalancerID string, listener *common.ELBListenerInfoObject) error {
+	deleteWhenActive := func(del func(ctx context.Context) error) error {
+		if err := del(ctx); err != nil {
+			if elb.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		return c.waitActive(ctx, loadbalancerID)
+	}
+	if listener.DefaultPoolID != "" {
+		pool, err := c.Client.GetBackendGroup(ctx, listener.DefaultPoolID)
+		if err != nil && !elb.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if _, err := c.Client.SyncBackends(ctx, pool.Pool.ID, nil, nil); err != nil {
+				return err
+			}
+			if pool.Pool.HealthmonitorID != "" {
+				if err := deleteWhenActive(func(ctx context.Context) error {
+					return c.Client.DeleteHealthmonitor(ctx, pool.Pool.HealthmonitorID)
+				}); err != nil {
+					return err
+				}
+			}
+			if err := deleteWhenActive(func(ctx context.Context) error {
+				return c.Client.RemoveBackendGroup(ctx, pool.Pool.ID)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := deleteWhenActive(func(ctx context.Context) error {
+		return c.Client.DeleteListener(ctx, listener.ID)
+	}); err != nil {
+		return err
+	}
+	logrus.Infof("deleted listener %s of load balancer %s", listener.ID, loadbalancerID)
+	return nil
+}
+
+// cleanup removes the listeners of the service, and the load balancer when
+// the controller created it and no other listeners are left on it
+func (c *Controller) cleanup(ctx context.Context, svc *v1.Service) error {
+	lb, owned, err := c.findLoadBalancer(ctx, svc)
+	if err != nil && !elb.IsNotFound(err) {
+		return err
+	}
+	if lb != nil {
+		list, err := c.Client.GetListenersByELBID(ctx, lb.ID)
+		if err != nil {
+			return err
+		}
+		prefix := c.loadBalancerName(svc) + "_"
+		remaining := 0
+		for _, listener := range list.Listeners {
+			if !strings.HasPrefix(listener.Name, prefix) {
+				remaining++
+				continue
+			}
+			if err := c.deleteListener(ctx, lb.ID, &listener); err != nil {
+				return err
+			}
+		}
+		if owned && remaining == 0 {
+			if err := c.Client.DeleteLoadBalancer(ctx, lb.ID); err != nil && !elb.IsNotFound(err) {
+				return err
+			}
+			logrus.Infof("deleted load balancer %s of service %s/%s", lb.ID, svc.Namespace, svc.Name)
+		}
+	}
+
+	if svc.DeletionTimestamp == nil {
+		if err := c.updateStatus(svc, nil); err != nil {
+			return err
+		}
+		// the status update returns a new resource version
+		if svc, err = c.Services.Services(svc.Namespace).Get(svc.Name, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+	updated := svc.DeepCopy()
+	updated.Finalizers = nil
+	for _, f := range svc.Finalizers {
+		if f != Finalizer {
+			updated.Finalizers = append(updated.Finalizers, f)
+		}
+	}
+	_, err = c.Services.Services(svc.Namespace).Update(updated)
+	return err
+}
+
+// waitActive waits until the load balancer accepts the next change
+func (c *Controller) waitActive(ctx context.Context, loadbalancerID string) error {
+	_, err := c.Client.WaitForLoadBalancerActive(ctx, c.Client.StatusPollInterval, c.Client.ProvisioningTimeout, loadbalancerID)
+	return err
+}
+
+func (c *Controller) ingressIP(ctx context.Context, lb *common.LoadbalancerObject) (string, error) {
+	if c.NetworkClient == nil || lb.VipPortID == "" {
+		return lb.VIPAddress, nil
+	}
+	eip, err := c.NetworkClient.GetEIPByPort(ctx, lb.VipPortID)
+	if err != nil {
+		return "", err
+	}
+	if eip == nil {
+		return lb.VIPAddress, nil
+	}
+	return eip.Addr, nil
+}
+
+func (c *Controller) updateStatus(svc *v1.Service, ingress []v1.LoadBalancerIngress) error {
+	if reflect.DeepEqual(svc.Status.LoadBalancer.Ingress, ingress) ||
+		len(svc.Status.LoadBalancer.Ingress) == 0 && len(ingress) == 0 {
+		return nil
+	}
+	updated := svc.DeepCopy()
+	updated.Status.LoadBalancer.Ingress = ingress
+	_, err := c.Services.Services(svc.Namespace).UpdateStatus(updated)
+	return err
+}
+
+func hasFinalizer(svc *v1.Service) bool {
+	for _, f := range svc.Finalizers {
+		if f == Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeAddresses returns the internal IPs of the Ready nodes which are not excluded
+func nodeAddresses(nodes []v1.Node) []string {
+	var rtn []string
+	for _, node := range nodes {
+		if _, ok := node.Labels[LabelExcludeNode]; ok || !nodeReady(&node) {
+			continue
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Type == v1.NodeInternalIP {
+				rtn = append(rtn, address.Address)
+				break
+			}
+		}
+	}
+	return rtn
+}
+
+func nodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
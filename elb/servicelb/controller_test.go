@@ -0,0 +1,349 @@
+package servicelb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/cnrancher/huaweicloud-sdk/elb"
+	"github.com/cnrancher/huaweicloud-sdk/elb/elbtest"
+	"github.com/cnrancher/huaweicloud-sdk/network"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newFakeCore returns the core/v1 fake of client-go with the objects. Like
+// the API server, deleting a service with finalizers only sets its deletion
+// timestamp and the service is removed once its last finalizer is gone.
+func newFakeCore(objects ...runtime.Object) *fake.FakeCoreV1 {
+	tracker := k8stesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+	services := v1.SchemeGroupVersion.WithResource("services")
+	core := &fake.FakeCoreV1{Fake: &k8stesting.Fake{}}
+	core.AddReactor("*", "*", k8stesting.ObjectReaction(tracker))
+	core.PrependReactor("delete", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := tracker.Get(services, action.GetNamespace(), action.(k8stesting.DeleteAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		svc := obj.(*v1.Service).DeepCopy()
+		if len(svc.Finalizers) == 0 {
+			return false, nil, nil
+		}
+		now := metav1.Now()
+		svc.DeletionTimestamp = &now
+		return true, nil, tracker.Update(services, svc, svc.Namespace)
+	})
+	core.PrependReactor("update", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		svc := action.(k8stesting.UpdateAction).GetObject().(*v1.Service)
+		if svc.DeletionTimestamp == nil || len(svc.Finalizers) != 0 {
+			return false, nil, nil
+		}
+		return true, svc, tracker.Delete(services, svc.Namespace, svc.Name)
+	})
+	return core
+}
+
+// getService returns the service from the fake, nil when it does not exist
+func getService(t *testing.T, core *fake.FakeCoreV1, name string) *v1.Service {
+	svc, err := core.Services("default").Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func newNode(name, ip string, ready bool, labels map[string]string) v1.Node {
+	status := v1.ConditionTrue
+	if !ready {
+		status = v1.ConditionFalse
+	}
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: v1.NodeStatus{
+			Addresses:  []v1.NodeAddress{{Type: v1.NodeHostName, Address: name}, {Type: v1.NodeInternalIP, Address: ip}},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newService(name string, annotations map[string]string, ports ...v1.ServicePort) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeLoadBalancer,
+			Ports: ports,
+		},
+	}
+}
+
+func newTestController(t *testing.T) (*Controller, *fake.FakeCoreV1, *elbtest.Server) {
+	server := elbtest.NewServer()
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	server.Install(base)
+	client := elb.NewClient(base)
+	client.StatusPollInterval = time.Millisecond
+	node1 := newNode("node-1", "10.0.0.1", true, nil)
+	node2 := newNode("node-2", "10.0.0.2", true, nil)
+	node3 := newNode("node-3", "10.0.0.3", false, nil)
+	node4 := newNode("node-4", "10.0.0.4", true, map[string]string{LabelExcludeNode: ""})
+	core := newFakeCore(&node1, &node2, &node3, &node4)
+	return &Controller{
+		Client:        client,
+		Services:      core,
+		Nodes:         core,
+		NetworkClient: network.NewClient(base),
+		ClusterName:   "test",
+		SubnetID:      "subnet-1",
+	}, core, server
+}
+
+func memberAddresses(server *elbtest.Server, poolID string) map[string]int {
+	rtn := map[string]int{}
+	for _, member := range server.Members[poolID] {
+		rtn[member.Address] = member.ProtocolPort
+	}
+	return rtn
+}
+
+func TestControllerServiceLifecycle(t *testing.T) {
+	c, core, server := newTestController(t)
+	server.PendingPolls = 2
+	root := context.Background()
+	core.Services("default").Create(newService("web", nil,
+		v1.ServicePort{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080},
+		v1.ServicePort{Name: "dns", Protocol: v1.ProtocolUDP, Port: 53, NodePort: 30053},
+	))
+	core.Services("default").Create(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "internal"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP},
+	})
+
+	if err := c.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.LoadBalancers) != 1 {
+		t.Fatalf("expected one load balancer, got %d", len(server.LoadBalancers))
+	}
+	lbs, err := c.Client.ListLoadBalancers(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb := lbs[0]
+	if lb.Name != "k8s_test_default_web" || lb.VipSubnetID != "subnet-1" {
+		t.Fatalf("unexpected load balancer %#v", lb)
+	}
+	if len(server.Listeners) != 2 {
+		t.Fatalf("expected two listeners, got %d", len(server.Listeners))
+	}
+	for _, listener := range server.Listeners {
+		pool := server.Pools[listener.DefaultPoolID]
+		if pool == nil {
+			t.Fatalf("listener %s has no pool", listener.Name)
+		}
+		monitor := server.Healthmonitors[pool.HealthmonitorID]
+		nodePort := 30080
+		monitorType := elb.HealthmonitorTypeTCP
+		if listener.Protocol == "UDP" {
+			nodePort = 30053
+			monitorType = elb.HealthmonitorTypeUDPConnect
+		}
+		if monitor == nil || monitor.Type != monitorType {
+			t.Fatalf("expected %s healthmonitor on pool of %s, got %#v", monitorType, listener.Name, monitor)
+		}
+		members := memberAddresses(server, pool.ID)
+		if len(members) != 2 || members["10.0.0.1"] != nodePort || members["10.0.0.2"] != nodePort {
+			t.Fatalf("unexpected members of %s: %v", listener.Name, members)
+		}
+	}
+	svc := getService(t, core, "web")
+	if !hasFinalizer(svc) {
+		t.Fatal("expected finalizer on service")
+	}
+	if ingress := svc.Status.LoadBalancer.Ingress; len(ingress) != 1 || ingress[0].IP != lb.VIPAddress {
+		t.Fatalf("expected vip %s as ingress, got %v", lb.VIPAddress, ingress)
+	}
+	if hasFinalizer(getService(t, core, "internal")) {
+		t.Fatal("expected no finalizer on cluster ip service")
+	}
+
+	// a bound EIP is reported instead of the VIP
+	server.AddEIP("eip-1", "100.64.1.1", lb.VipPortID)
+	// node-3 becomes ready and the udp port is removed
+	node3 := newNode("node-3", "10.0.0.3", true, nil)
+	core.Nodes().Update(&node3)
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+	core.Services("default").Update(svc)
+	if err := c.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.Listeners) != 1 || len(server.Pools) != 1 || len(server.Healthmonitors) != 1 {
+		t.Fatalf("expected the udp listener to be removed, got %d listeners, %d pools", len(server.Listeners), len(server.Pools))
+	}
+	for _, pool := range server.Pools {
+		if members := memberAddresses(server, pool.ID); len(members) != 3 {
+			t.Fatalf("expected three members, got %v", members)
+		}
+	}
+	if ingress := getService(t, core, "web").Status.LoadBalancer.Ingress; len(ingress) != 1 || ingress[0].IP != "100.64.1.1" {
+		t.Fatalf("expected eip as ingress, got %v", ingress)
+	}
+
+	core.Services("default").Delete("web", nil)
+	if err := c.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.LoadBalancers) != 0 || len(server.Listeners) != 0 || len(server.Pools) != 0 || len(server.Healthmonitors) != 0 {
+		t.Fatalf("expected everything to be removed, got %d load balancers, %d listeners, %d pools",
+			len(server.LoadBalancers), len(server.Listeners), len(server.Pools))
+	}
+	if getService(t, core, "web") != nil {
+		t.Fatal("expected service to be deleted after the finalizer is removed")
+	}
+}
+
+func TestControllerReusedLoadBalancer(t *testing.T) {
+	c, core, server := newTestController(t)
+	root := context.Background()
+	lb, err := c.Client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := c.Client.CreateListener(root, &common.ELBListenerRequest{
+		Listener: common.ELBListenerRequestObject{Name: "manual", Protocol: "TCP", ProtocolPort: 443, LoadbalancerId: lb.Loadbalancer.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.Services("default").Create(newService("web", map[string]string{
+		AnnotationLoadBalancerID: lb.Loadbalancer.ID,
+		AnnotationLBAlgorithm:    "LEAST_CONNECTIONS",
+	}, v1.ServicePort{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}))
+	svc := getService(t, core, "web")
+
+	if err := c.Reconcile(root, svc); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.LoadBalancers) != 1 || len(server.Listeners) != 2 {
+		t.Fatalf("expected a listener on the existing load balancer, got %d load balancers, %d listeners",
+			len(server.LoadBalancers), len(server.Listeners))
+	}
+	for _, pool := range server.Pools {
+		if pool.LbAlgorithm != "LEAST_CONNECTIONS" {
+			t.Fatalf("expected LEAST_CONNECTIONS, got %s", pool.LbAlgorithm)
+		}
+		for _, member := range server.Members[pool.ID] {
+			if member.SubnetID != "subnet-2" {
+				t.Fatalf("expected members in the vip subnet, got %s", member.SubnetID)
+			}
+		}
+	}
+
+	// the session affinity and algorithm are updated on the existing pool
+	svc = getService(t, core, "web")
+	svc.Spec.SessionAffinity = v1.ServiceAffinityClientIP
+	delete(svc.Annotations, AnnotationLBAlgorithm)
+	core.Services("default").Update(svc)
+	if err := c.Reconcile(root, getService(t, core, "web")); err != nil {
+		t.Fatal(err)
+	}
+	for _, pool := range server.Pools {
+		if pool.LbAlgorithm != DefaultLBAlgorithm || pool.SessionPersistence.Type != "SOURCE_IP" {
+			t.Fatalf("unexpected pool %#v", pool)
+		}
+	}
+
+	// changing the type cleans up and clears the status
+	svc = getService(t, core, "web")
+	svc.Spec.Type = v1.ServiceTypeNodePort
+	core.Services("default").Update(svc)
+	if err := c.Reconcile(root, getService(t, core, "web")); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.LoadBalancers) != 1 || len(server.Listeners) != 1 || server.Listeners[foreign.Listener.ID] == nil {
+		t.Fatalf("expected only the listener of the service to be removed, got %d listeners", len(server.Listeners))
+	}
+	svc = getService(t, core, "web")
+	if hasFinalizer(svc) || len(svc.Status.LoadBalancer.Ingress) != 0 {
+		t.Fatalf("expected finalizer and status to be removed, got %v %v", svc.Finalizers, svc.Status.LoadBalancer.Ingress)
+	}
+}
+
+func TestControllerRecreatesHealthmonitor(t *testing.T) {
+	c, core, server := newTestController(t)
+	root := context.Background()
+	core.Services("default").Create(newService("web", nil, v1.ServicePort{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}))
+	// the monitor cannot be created and removing the pool fails as well, the
+	// pool is left without a monitor
+	server.FailNext(http.MethodPost, "/v2.0/lbaas/healthmonitors", http.StatusBadRequest)
+	server.FailNext(http.MethodDelete, "/v2.0/lbaas/pools/*", http.StatusInternalServerError)
+	if err := c.Sync(root); err == nil {
+		t.Fatal("failed healthmonitor should fail the sync")
+	}
+	if len(server.Pools) != 1 || len(server.Healthmonitors) != 0 {
+		t.Fatalf("expected a pool without healthmonitor, got %d pools, %d healthmonitors", len(server.Pools), len(server.Healthmonitors))
+	}
+
+	server.PendingPolls = 2
+	if err := c.Sync(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, pool := range server.Pools {
+		monitor := server.Healthmonitors[pool.HealthmonitorID]
+		if monitor == nil || monitor.Type != elb.HealthmonitorTypeTCP {
+			t.Fatalf("healthmonitor of pool %s is not created again", pool.ID)
+		}
+		if members := memberAddresses(server, pool.ID); len(members) != 2 {
+			t.Fatalf("expected two members, got %v", members)
+		}
+	}
+}
+
+func TestControllerPortConflict(t *testing.T) {
+	c, core, server := newTestController(t)
+	root := context.Background()
+	lb, err := c.Client.CreateLoadBalancer(root, &common.LoadBalancerRequest{
+		Loadbalancer: common.LoadbalancerObject{VipSubnetID: "subnet-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Client.CreateListener(root, &common.ELBListenerRequest{
+		Listener: common.ELBListenerRequestObject{Name: "manual", Protocol: "TCP", ProtocolPort: 80, LoadbalancerId: lb.Loadbalancer.ID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	core.Services("default").Create(newService("web", map[string]string{AnnotationLoadBalancerID: lb.Loadbalancer.ID},
+		v1.ServicePort{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}))
+
+	err = c.Sync(root)
+	if err == nil {
+		t.Fatal("expected port conflict")
+	}
+	if len(server.Pools) != 0 {
+		t.Fatalf("expected no pools, got %d", len(server.Pools))
+	}
+
+	c.SubnetID = ""
+	core.Services("default").Create(newService("other", nil, v1.ServicePort{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30081}))
+	if err := c.Reconcile(root, getService(t, core, "other")); err == nil || !strings.Contains(err.Error(), AnnotationSubnetID) {
+		t.Fatalf("expected missing subnet error, got %v", err)
+	}
+}
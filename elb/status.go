@@ -0,0 +1,121 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultStatusPollInterval and DefaultProvisioningTimeout are used by the
+	// waiters of the status tree when duration or timeout is 0
+	DefaultStatusPollInterval  = common.DefaultDuration
+	DefaultProvisioningTimeout = 5 * time.Minute
+)
+
+func (c *Client) GetLoadBalancerStatuses(ctx context.Context, id string) (*common.ELBLoadBalancerStatus, error) {
+	if id == "" {
+		return nil, errors.New("[GetLoadBalancerStatuses]loadbalancer id is required")
+	}
+	rtn := common.ELBStatuses{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("loadbalancers", id, "statuses"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Statuses.Loadbalancer, nil
+}
+
+// WaitForLoadBalancerStatus polls the status tree of the load balancer every
+// duration until condition returns true or an error
+func (c *Client) WaitForLoadBalancerStatus(ctx context.Context, duration, timeout time.Duration, id string, condition func(*common.ELBLoadBalancerStatus) (bool, error)) (*common.ELBLoadBalancerStatus, error) {
+	duration, timeout = statusWaitDefaults(duration, timeout)
+	var last *common.ELBLoadBalancerStatus
+	err := common.CustomWaitForCompleteUntilTrue(ctx, duration, timeout, func(ictx context.Context) (bool, error) {
+		status, err := c.GetLoadBalancerStatuses(ictx, id)
+		if err != nil {
+			return false, err
+		}
+		last = status
+		return condition(status)
+	})
+	return last, err
+}
+
+// WaitForLoadBalancerActive waits until the load balancer and so all of its
+// children can be changed again
+func (c *Client) WaitForLoadBalancerActive(ctx context.Context, duration, timeout time.Duration, id string) (*common.ELBLoadBalancerStatus, error) {
+	return c.WaitForLoadBalancerStatus(ctx, duration, timeout, id, func(status *common.ELBLoadBalancerStatus) (bool, error) {
+		return provisioned("loadbalancer", id, status.ProvisioningStatus)
+	})
+}
+
+// WaitForListenerActive waits until the listener is created or updated
+func (c *Client) WaitForListenerActive(ctx context.Context, duration, timeout time.Duration, loadbalancerID, listenerID string) (*common.ELBLoadBalancerStatus, error) {
+	return c.WaitForLoadBalancerStatus(ctx, duration, timeout, loadbalancerID, func(status *common.ELBLoadBalancerStatus) (bool, error) {
+		listener := status.Listener(listenerID)
+		if listener == nil {
+			return false, nil
+		}
+		return provisioned("listener", listenerID, listener.ProvisioningStatus)
+	})
+}
+
+// WaitForPoolActive waits until the pool is created or updated
+func (c *Client) WaitForPoolActive(ctx context.Context, duration, timeout time.Duration, loadbalancerID, poolID string) (*common.ELBLoadBalancerStatus, error) {
+	return c.WaitForLoadBalancerStatus(ctx, duration, timeout, loadbalancerID, func(status *common.ELBLoadBalancerStatus) (bool, error) {
+		pool := status.Pool(poolID)
+		if pool == nil {
+			return false, nil
+		}
+		return provisioned("pool", poolID, pool.ProvisioningStatus)
+	})
+}
+
+// WaitForMemberActive waits until the member of the pool is created or updated
+func (c *Client) WaitForMemberActive(ctx context.Context, duration, timeout time.Duration, loadbalancerID, poolID, memberID string) (*common.ELBLoadBalancerStatus, error) {
+	return c.WaitForLoadBalancerStatus(ctx, duration, timeout, loadbalancerID, func(status *common.ELBLoadBalancerStatus) (bool, error) {
+		member := status.Member(poolID, memberID)
+		if member == nil {
+			return false, nil
+		}
+		return provisioned("member", memberID, member.ProvisioningStatus)
+	})
+}
+
+// waitActive waits for the load balancer with the poll interval and the
+// timeout of the client
+func (c *Client) waitActive(ctx context.Context, id string) (*common.ELBLoadBalancerStatus, error) {
+	return c.WaitForLoadBalancerActive(ctx, c.StatusPollInterval, c.ProvisioningTimeout, id)
+}
+
+func statusWaitDefaults(duration, timeout time.Duration) (time.Duration, time.Duration) {
+	if duration <= 0 {
+		duration = DefaultStatusPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultProvisioningTimeout
+	}
+	return duration, timeout
+}
+
+func provisioned(kind, id, status string) (bool, error) {
+	switch status {
+	case common.ProvisioningActive:
+		return true, nil
+	case common.ProvisioningError:
+		return false, fmt.Errorf("%s %s is in %s provisioning status", kind, id, status)
+	default:
+		logrus.Debugf("%s %s is still %s", kind, id, status)
+		return false, nil
+	}
+}
@@ -0,0 +1,61 @@
+package elb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+const statusesTemplate = `{"statuses":{"loadbalancer":{"id":"lb","provisioning_status":"ACTIVE","operating_status":"ONLINE",
+"listeners":[{"id":"listener","provisioning_status":"ACTIVE","pools":[{"id":"pool","provisioning_status":"ACTIVE",
+"healthmonitor":{"id":"hm","type":"TCP","provisioning_status":"ACTIVE"},
+"members":[{"id":"member","address":"192.168.0.1","protocol_port":80,"provisioning_status":"%s"}]}]}],
+"pools":[{"id":"lbpool","provisioning_status":"ACTIVE"}]}}}`
+
+func TestWaitForMemberActive(t *testing.T) {
+	states := []string{"PENDING_CREATE", "PENDING_CREATE", "ACTIVE"}
+	calls := 0
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v2.0/lbaas/loadbalancers/lb/statuses" {
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+		body := bytes.NewBufferString(fmt.Sprintf(statusesTemplate, state))
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(body)}, nil
+	})
+	c := NewClient(base)
+
+	status, err := c.WaitForMemberActive(context.Background(), 10*time.Millisecond, time.Second, "lb", "pool", "member")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 polls, got %d", calls)
+	}
+	if status.Pool("lbpool") == nil || status.Pool("pool").Healthmonitor.ID != "hm" || status.Member("pool", "member").ProtocolPort != 80 {
+		t.Fatalf("unexpected status tree %#v", status)
+	}
+
+	states = []string{"ERROR"}
+	calls = 0
+	if _, err := c.WaitForMemberActive(context.Background(), 10*time.Millisecond, time.Second, "lb", "pool", "member"); err == nil {
+		t.Fatal("ERROR provisioning status should stop waiting")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
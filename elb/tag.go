@@ -0,0 +1,53 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// resource types which support tags
+const (
+	TagResourceLoadBalancer = "loadbalancers"
+	TagResourceListener     = "listeners"
+)
+
+func (c *Client) CreateTag(ctx context.Context, resourceType, id string, tag common.Tag) error {
+	if id == "" {
+		return errors.New("[CreateTag]resource id is required")
+	}
+	return c.CreateResourceTag(ctx, c.getTagURL(resourceType, id), tag)
+}
+
+func (c *Client) DeleteTag(ctx context.Context, resourceType, id, key string) error {
+	if id == "" {
+		return errors.New("[DeleteTag]resource id is required")
+	}
+	return c.DeleteResourceTag(ctx, c.getTagURL(resourceType, id), key)
+}
+
+func (c *Client) BatchUpdateTags(ctx context.Context, resourceType, id, action string, tags []common.Tag) error {
+	if id == "" {
+		return errors.New("[BatchUpdateTags]resource id is required")
+	}
+	return c.BatchUpdateResourceTags(ctx, c.getTagURL(resourceType, id), action, tags)
+}
+
+func (c *Client) GetTags(ctx context.Context, resourceType, id string) ([]common.Tag, error) {
+	if id == "" {
+		return nil, errors.New("[GetTags]resource id is required")
+	}
+	return c.GetResourceTags(ctx, c.getTagURL(resourceType, id))
+}
+
+func (c *Client) ListResourcesByTags(ctx context.Context, resourceType string, filter *common.ResourceTagFilter) (*common.ResourceInstanceList, error) {
+	return c.Client.ListResourcesByTags(ctx, c.getTagURL(resourceType), filter)
+}
+
+// tags live under /v2.0/{project_id} instead of /v2.0/lbaas
+func (c *Client) getTagURL(paths ...string) string {
+	return fmt.Sprintf("%s%s/%s/%s", c.GetAPIEndpointFunc(), c.GetAPIPrefixFunc(), c.ProjectID, strings.Join(paths, "/"))
+}
@@ -0,0 +1,67 @@
+package elb
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestTags(t *testing.T) {
+	var requests []string
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		request := r.Method + " " + r.URL.String()
+		if r.Body != nil {
+			b, _ := ioutil.ReadAll(r.Body)
+			request += " " + string(b)
+		}
+		requests = append(requests, request)
+		body := `{"tags":[{"key":"owner","value":"ci"}],"resources":[],"total_count":0}`
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+	c := NewClient(base)
+	ctx := context.Background()
+
+	if err := c.CreateTag(ctx, TagResourceLoadBalancer, "", common.Tag{Key: "owner"}); err == nil {
+		t.Fatal("expected error without resource id")
+	}
+	if err := c.CreateTag(ctx, TagResourceLoadBalancer, "lb-1", common.Tag{Key: "owner", Value: "ci"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteTag(ctx, TagResourceListener, "listener-1", "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BatchUpdateTags(ctx, TagResourceLoadBalancer, "lb-1", common.TagActionCreate, []common.Tag{{Key: "env", Value: "test"}}); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := c.GetTags(ctx, TagResourceListener, "listener-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Key != "owner" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	if _, err := c.ListResourcesByTags(ctx, TagResourceLoadBalancer, &common.ResourceTagFilter{
+		Matches: []common.TagMatch{{Key: "resource_name", Value: "web"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// tags are served under the project instead of /v2.0/lbaas
+	url := "https://elb.cn-north-1.myhuawei.com/v2.0/test"
+	expected := []string{
+		`POST ` + url + `/loadbalancers/lb-1/tags {"tag":{"key":"owner","value":"ci"}}`,
+		`DELETE ` + url + `/listeners/listener-1/tags/owner`,
+		`POST ` + url + `/loadbalancers/lb-1/tags/action {"action":"create","tags":[{"key":"env","value":"test"}]}`,
+		`GET ` + url + `/listeners/listener-1/tags`,
+		`POST ` + url + `/loadbalancers/resource_instances/action {"action":"filter","matches":[{"key":"resource_name","value":"web"}]}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+}
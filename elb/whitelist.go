@@ -0,0 +1,226 @@
+package elb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func (c *Client) CreateWhitelist(ctx context.Context, input *common.ELBWhitelistRequest) (*common.ELBWhitelist, error) {
+	if input.Whitelist.ListenerID == "" {
+		return nil, errors.New("[CreateWhitelist]listener id is required")
+	}
+	rtn := common.ELBWhitelistDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("whitelists"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Whitelist, nil
+}
+
+func (c *Client) GetWhitelist(ctx context.Context, id string) (*common.ELBWhitelist, error) {
+	if id == "" {
+		return nil, errors.New("[GetWhitelist]whitelist id is required")
+	}
+	rtn := common.ELBWhitelistDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("whitelists", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Whitelist, nil
+}
+
+// ListWhitelists returns the whitelists matching filter, all pages are followed
+func (c *Client) ListWhitelists(ctx context.Context, filter *common.ELBWhitelistListRequest) ([]common.ELBWhitelist, error) {
+	var rtn []common.ELBWhitelist
+	next := common.WithQuery(c.GetURL("whitelists"), filter)
+	for next != "" {
+		page := common.ELBWhitelistList{}
+		if _, err := c.DoRequest(
+			ctx,
+			http.MethodGet,
+			next,
+			nil,
+			&page,
+		); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, page.Whitelists...)
+		next = nextLink(page.WhitelistsLinks)
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateWhitelist(ctx context.Context, id string, update common.ELBWhitelistUpdate) (*common.ELBWhitelist, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateWhitelist]whitelist id is required")
+	}
+	rtn := common.ELBWhitelistDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("whitelists", id),
+		&common.ELBWhitelistUpdateRequest{Whitelist: update},
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Whitelist, nil
+}
+
+func (c *Client) DeleteWhitelist(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteWhitelist]whitelist id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("whitelists", id),
+		nil,
+		nil,
+	)
+	return err
+}
+
+// GetListenerWhitelist returns the whitelist of the listener, nil when it has none
+func (c *Client) GetListenerWhitelist(ctx context.Context, listenerID string) (*common.ELBWhitelist, error) {
+	if listenerID == "" {
+		return nil, errors.New("[GetListenerWhitelist]listener id is required")
+	}
+	list, err := c.ListWhitelists(ctx, &common.ELBWhitelistListRequest{ListenerID: listenerID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].ListenerID == listenerID {
+			return &list[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// maxWhitelistUpdates limits how often AddWhitelistCIDRs and
+// RemoveWhitelistCIDRs update a whitelist which is changed concurrently
+const maxWhitelistUpdates = 5
+
+// AddWhitelistCIDRs adds the CIDRs to the whitelist of the listener and keeps
+// the ones which are already there. A whitelist is created and enabled when
+// the listener has none, an existing one keeps its enable flag. See
+// editWhitelist for concurrent changes.
+func (c *Client) AddWhitelistCIDRs(ctx context.Context, listenerID string, cidrs ...string) (*common.ELBWhitelist, error) {
+	add, err := common.ParseCIDRList(strings.Join(cidrs, ","))
+	if err != nil {
+		return nil, err
+	}
+	loadbalancerID, current, err := c.getWhitelistForEdit(ctx, listenerID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		enable := true
+		var created *common.ELBWhitelist
+		err := c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+			var err error
+			created, err = c.CreateWhitelist(ctx, &common.ELBWhitelistRequest{Whitelist: common.ELBWhitelist{
+				ListenerID:      listenerID,
+				EnableWhitelist: &enable,
+				Whitelist:       add,
+			}})
+			return err
+		})
+		return created, err
+	}
+	return c.editWhitelist(ctx, loadbalancerID, current, func(list common.CIDRList) (common.CIDRList, bool) {
+		changed := false
+		for _, n := range add {
+			if !list.Contains(n) {
+				list = append(list, n)
+				changed = true
+			}
+		}
+		return list, changed
+	})
+}
+
+// RemoveWhitelistCIDRs removes the CIDRs from the whitelist of the listener
+// and keeps the others. The whitelist is not deleted when it becomes empty,
+// an enabled empty whitelist denies all addresses. See editWhitelist for
+// concurrent changes.
+func (c *Client) RemoveWhitelistCIDRs(ctx context.Context, listenerID string, cidrs ...string) (*common.ELBWhitelist, error) {
+	remove, err := common.ParseCIDRList(strings.Join(cidrs, ","))
+	if err != nil {
+		return nil, err
+	}
+	loadbalancerID, current, err := c.getWhitelistForEdit(ctx, listenerID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("listener %s has no whitelist", listenerID)
+	}
+	return c.editWhitelist(ctx, loadbalancerID, current, func(list common.CIDRList) (common.CIDRList, bool) {
+		kept := common.CIDRList{}
+		for _, n := range list {
+			if !remove.Contains(n) {
+				kept = append(kept, n)
+			}
+		}
+		return kept, len(kept) != len(list)
+	})
+}
+
+func (c *Client) getWhitelistForEdit(ctx context.Context, listenerID string) (string, *common.ELBWhitelist, error) {
+	listener, err := c.GetListener(ctx, listenerID)
+	if err != nil {
+		return "", nil, err
+	}
+	current, err := c.GetListenerWhitelist(ctx, listenerID)
+	if err != nil {
+		return "", nil, err
+	}
+	return listenerLoadBalancerID(&listener.Listener), current, nil
+}
+
+// editWhitelist replaces the CIDRs of the whitelist with the edited ones.
+// The api only replaces the whole list, so an update of another client made
+// between reading and updating the list is lost. To detect this the
+// whitelist is read again after the update, and the edit is repeated while
+// it still changes the list. A concurrent update which is made after the
+// whitelist is read again still wins, it can only be detected by the next
+// call.
+func (c *Client) editWhitelist(ctx context.Context, loadbalancerID string, current *common.ELBWhitelist, edit func(common.CIDRList) (common.CIDRList, bool)) (*common.ELBWhitelist, error) {
+	for i := 0; ; i++ {
+		list, changed := edit(current.Whitelist)
+		if !changed {
+			return current, nil
+		}
+		if i == maxWhitelistUpdates {
+			return nil, fmt.Errorf("whitelist %s is changed concurrently, giving up after %d updates", current.ID, i)
+		}
+		if err := c.changeWhenActive(ctx, loadbalancerID, func(ctx context.Context) error {
+			_, err := c.UpdateWhitelist(ctx, current.ID, common.ELBWhitelistUpdate{Whitelist: &list})
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		id := current.ID
+		var err error
+		if current, err = c.GetWhitelist(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+}
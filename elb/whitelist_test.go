@@ -0,0 +1,84 @@
+package elb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestWhitelistCIDRs(t *testing.T) {
+	server, c, ids := newFakeListeners(t, 443)
+	server.PendingPolls = 2
+	listenerID := ids[0]
+	ctx := context.Background()
+
+	created, err := c.AddWhitelistCIDRs(ctx, listenerID, "10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	whitelist := server.Whitelists[created.ID]
+	if whitelist == nil || !*whitelist.EnableWhitelist {
+		t.Fatal("whitelist should be created and enabled")
+	}
+	// another team adds their office
+	office, _ := common.ParseCIDR("172.16.0.0/12")
+	whitelist.Whitelist = append(whitelist.Whitelist, *office)
+
+	if _, err := c.AddWhitelistCIDRs(ctx, listenerID, "192.168.1.10", "10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if s := whitelist.Whitelist.String(); s != "10.0.0.0/8,172.16.0.0/12,192.168.1.10/32" {
+		t.Fatalf("unexpected whitelist %s", s)
+	}
+	if _, err := c.RemoveWhitelistCIDRs(ctx, listenerID, "10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if s := whitelist.Whitelist.String(); s != "172.16.0.0/12,192.168.1.10/32" {
+		t.Fatalf("unexpected whitelist %s", s)
+	}
+	if _, err := c.AddWhitelistCIDRs(ctx, listenerID, "10.0.0.0/33"); err == nil {
+		t.Fatal("invalid cidr should be rejected")
+	}
+}
+
+func TestWhitelistCIDRsConcurrentUpdate(t *testing.T) {
+	server, c, ids := newFakeListeners(t, 443)
+	ctx := context.Background()
+	created, err := c.AddWhitelistCIDRs(ctx, ids[0], "10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another client read the whitelist before our update and writes its
+	// own change after it, which drops the CIDR we added
+	stale, _ := common.ParseCIDRList("10.0.0.0/8,172.16.0.0/12")
+	updates := 0
+	server.Served = func(request string) {
+		if request != http.MethodPut+" /v2.0/lbaas/whitelists/"+created.ID {
+			return
+		}
+		if updates++; updates == 1 {
+			server.Whitelists[created.ID].Whitelist = stale
+		}
+	}
+	whitelist, err := c.AddWhitelistCIDRs(ctx, ids[0], "192.168.1.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := whitelist.Whitelist.String(); s != "10.0.0.0/8,172.16.0.0/12,192.168.1.10/32" || updates != 2 {
+		t.Fatalf("lost update is not repeated: %s after %d updates", s, updates)
+	}
+
+	// an update which is always overwritten gives up
+	server.Served = func(request string) {
+		if strings.HasPrefix(request, http.MethodPut) {
+			server.Whitelists[created.ID].Whitelist = stale
+		}
+	}
+	if _, err := c.RemoveWhitelistCIDRs(ctx, ids[0], "172.16.0.0/12"); err == nil {
+		t.Fatal("whitelist which is changed on every update should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func (c *Client) CreateCertificate(ctx context.Context, input *common.ELBV3CertificateRequest) (*common.ELBV3Certificate, error) {
+	if input.Certificate.Certificate == "" {
+		return nil, errors.New("[CreateCertificate]certificate is required")
+	}
+	rtn := common.ELBV3CertificateDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("certificates"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Certificate, nil
+}
+
+func (c *Client) GetCertificate(ctx context.Context, id string) (*common.ELBV3Certificate, error) {
+	if id == "" {
+		return nil, errors.New("[GetCertificate]certificate id is required")
+	}
+	rtn := common.ELBV3CertificateDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("certificates", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Certificate, nil
+}
+
+// ListCertificates returns the certificates matching filter, all pages are followed
+func (c *Client) ListCertificates(ctx context.Context, filter *common.ELBV3CertificateListRequest) ([]common.ELBV3Certificate, error) {
+	f := common.ELBV3CertificateListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Certificate
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3CertificateList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("certificates"), &f), nil, &page)
+		rtn = append(rtn, page.Certificates...)
+		return page.PageInfo, len(page.Certificates), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateCertificate(ctx context.Context, id string, input *common.ELBV3CertificateRequest) (*common.ELBV3Certificate, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateCertificate]certificate id is required")
+	}
+	rtn := common.ELBV3CertificateDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("certificates", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Certificate, nil
+}
+
+func (c *Client) DeleteCertificate(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteCertificate]certificate id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("certificates", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,52 @@
+package elbv3
+
+import (
+	"fmt"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// Client talks to the dedicated load balancer api, shared load balancers are
+// managed by the elb package
+type Client struct {
+	common.Client
+}
+
+func NewClient(baseClient *common.Client) *Client {
+	c := &Client{
+		Client: *baseClient,
+	}
+	c.GetBaseURLFunc = c.GetBaseURL
+	c.SetServiceNameFunc(serviceName)
+	c.GetAPIPrefixFunc = prefix
+	c.GetAPIEndpointFunc = c.GetAPIEndpoint
+	c.GetAPIHostnameFunc = c.GetAPIHostname
+	return c
+}
+
+func (c *Client) GetBaseURL() string {
+	return fmt.Sprintf("%s%s/%s/elb", c.GetAPIEndpointFunc(), c.GetAPIPrefixFunc(), c.ProjectID)
+}
+
+func serviceName() string {
+	return "elb"
+}
+
+func prefix() string {
+	return "/v3"
+}
+
+// listAll calls fetch with the marker of every page until page_info has no
+// next marker, fetch returns the page info and the number of items of the page
+func listAll(marker string, fetch func(marker string) (common.ELBV3PageInfo, int, error)) error {
+	for {
+		info, count, err := fetch(marker)
+		if err != nil {
+			return err
+		}
+		if info.NextMarker == "" || count == 0 {
+			return nil
+		}
+		marker = info.NextMarker
+	}
+}
@@ -0,0 +1,216 @@
+package elbv3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// collections maps the collections of the fake server to the key of a single
+// item in request and response bodies
+var collections = map[string]string{
+	"loadbalancers":  "loadbalancer",
+	"listeners":      "listener",
+	"pools":          "pool",
+	"members":        "member",
+	"healthmonitors": "healthmonitor",
+	"certificates":   "certificate",
+	"flavors":        "flavor",
+}
+
+// fakeServer keeps the items of every collection path, e.g. "listeners" or
+// "pools/<id>/members", as decoded json in the order they are created. List
+// requests are paged by limit and marker and filtered by the other query
+// parameters.
+type fakeServer struct {
+	items   map[string]map[string]map[string]interface{}
+	order   map[string][]string
+	next    int
+	Queries []string
+	// Prepaid makes load balancers be created by an order, the creation
+	// only returns the ids of the load balancer and the order
+	Prepaid bool
+}
+
+func newFakeServer() (*fakeServer, *Client) {
+	s := &fakeServer{
+		items: map[string]map[string]map[string]interface{}{},
+		order: map[string][]string{},
+	}
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "project")
+	base.GetSigner().NextTransport = s
+	return s, NewClient(base)
+}
+
+func (s *fakeServer) RoundTrip(r *http.Request) (*http.Response, error) {
+	status, out := http.StatusNotFound, interface{}(common.OddErrorInfo{ErrorCode: "ELB.0404", ErrorMassage: "unknown host " + r.URL.Host})
+	if r.URL.Host == "elb.cn-north-1.myhuawei.com" {
+		status, out = s.serve(r)
+	}
+	data, _ := json.Marshal(out)
+	return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(data))}, nil
+}
+
+// Add stores item in the collection path and returns its id
+func (s *fakeServer) Add(path string, item map[string]interface{}) string {
+	id, ok := item["id"].(string)
+	if !ok {
+		s.next++
+		id = fmt.Sprintf("%s-%d", collections[path[strings.LastIndex(path, "/")+1:]], s.next)
+		item["id"] = id
+	}
+	if s.items[path] == nil {
+		s.items[path] = map[string]map[string]interface{}{}
+	}
+	s.items[path][id] = item
+	s.order[path] = append(s.order[path], id)
+	return id
+}
+
+func (s *fakeServer) remove(path, id string) {
+	delete(s.items[path], id)
+	for i, v := range s.order[path] {
+		if v == id {
+			s.order[path] = append(s.order[path][:i:i], s.order[path][i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *fakeServer) serve(r *http.Request) (int, interface{}) {
+	path := strings.TrimPrefix(r.URL.Path, "/v3/project/elb/")
+	id := ""
+	if _, ok := collections[path[strings.LastIndex(path, "/")+1:]]; !ok {
+		i := strings.LastIndex(path, "/")
+		path, id = path[:i], path[i+1:]
+	}
+	key, ok := collections[path[strings.LastIndex(path, "/")+1:]]
+	if !ok {
+		return http.StatusNotFound, common.OddErrorInfo{ErrorCode: "ELB.0404", ErrorMassage: "unknown path " + r.URL.Path}
+	}
+	notFound := common.OddErrorInfo{ErrorCode: "ELB.1001", ErrorMassage: key + " " + id + " not found"}
+	body := map[string]map[string]interface{}{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body[key] == nil {
+			return http.StatusBadRequest, common.OddErrorInfo{ErrorCode: "ELB.1000", ErrorMassage: "invalid body"}
+		}
+	}
+
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		id = s.Add(path, body[key])
+		if s.Prepaid && path == "loadbalancers" {
+			return http.StatusCreated, map[string]interface{}{"loadbalancer_id": id, "order_id": "order-" + id}
+		}
+		return http.StatusCreated, map[string]interface{}{key: body[key]}
+	case r.Method == http.MethodGet && id == "":
+		s.Queries = append(s.Queries, path+"?"+r.URL.RawQuery)
+		return s.list(path, key, r)
+	}
+	item, ok := s.items[path][id]
+	if !ok {
+		return http.StatusNotFound, notFound
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, map[string]interface{}{key: item}
+	case http.MethodPut:
+		for k, v := range body[key] {
+			item[k] = v
+		}
+		return http.StatusOK, map[string]interface{}{key: item}
+	case http.MethodDelete:
+		s.remove(path, id)
+		return http.StatusNoContent, nil
+	}
+	return http.StatusMethodNotAllowed, common.OddErrorInfo{ErrorCode: "ELB.0405", ErrorMassage: r.Method}
+}
+
+func (s *fakeServer) list(path, key string, r *http.Request) (int, interface{}) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	marker := query.Get("marker")
+	started := marker == ""
+	page := []map[string]interface{}{}
+	info := common.ELBV3PageInfo{}
+	for _, id := range s.order[path] {
+		if !started {
+			started = id == marker
+			continue
+		}
+		if !matches(s.items[path][id], query) {
+			continue
+		}
+		if limit > 0 && len(page) == limit {
+			info.NextMarker = page[len(page)-1]["id"].(string)
+			break
+		}
+		page = append(page, s.items[path][id])
+	}
+	if !started {
+		return http.StatusBadRequest, common.OddErrorInfo{ErrorCode: "ELB.1000", ErrorMassage: "unknown marker " + marker}
+	}
+	if len(page) > 0 {
+		info.PreviousMarker = page[0]["id"].(string)
+	}
+	info.CurrentCount = int64(len(page))
+	return http.StatusOK, map[string]interface{}{key + "s": page, "page_info": info}
+}
+
+// matches reports whether item has one of the values of every filter in query
+func matches(item map[string]interface{}, query map[string][]string) bool {
+	for k, values := range query {
+		if k == "limit" || k == "marker" {
+			continue
+		}
+		found := false
+		for _, v := range values {
+			found = found || fmt.Sprint(item[k]) == v
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func isNotFound(err error) bool {
+	eInfo, ok := err.(*common.ErrorInfo)
+	return ok && eInfo.StatusCode == http.StatusNotFound
+}
+
+func TestListAll(t *testing.T) {
+	var markers []string
+	pages := []common.ELBV3PageInfo{
+		{NextMarker: "a", CurrentCount: 2},
+		{PreviousMarker: "b", NextMarker: "c", CurrentCount: 2},
+		// an empty page ends the listing even though it has a next marker
+		{NextMarker: "d"},
+	}
+	err := listAll("start", func(marker string) (common.ELBV3PageInfo, int, error) {
+		markers = append(markers, marker)
+		info := pages[len(markers)-1]
+		return info, int(info.CurrentCount), nil
+	})
+	if err != nil || strings.Join(markers, ",") != "start,a,c" {
+		t.Fatalf("unexpected markers %v: %v", markers, err)
+	}
+
+	markers = nil
+	failed := errors.New("failed")
+	err = listAll("", func(marker string) (common.ELBV3PageInfo, int, error) {
+		markers = append(markers, marker)
+		return common.ELBV3PageInfo{NextMarker: "a"}, 1, failed
+	})
+	if err != failed || len(markers) != 1 {
+		t.Fatalf("listing should stop at the failed page: %v", err)
+	}
+}
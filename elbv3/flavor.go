@@ -0,0 +1,44 @@
+package elbv3
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// ListFlavors returns the flavors matching filter, all pages are followed
+func (c *Client) ListFlavors(ctx context.Context, filter *common.ELBV3FlavorListRequest) ([]common.ELBV3Flavor, error) {
+	f := common.ELBV3FlavorListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Flavor
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3FlavorList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("flavors"), &f), nil, &page)
+		rtn = append(rtn, page.Flavors...)
+		return page.PageInfo, len(page.Flavors), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+// ListAvailabilityZones returns the sets of availability zones a load balancer
+// can be created in, the zones of one load balancer have to be of the same set
+func (c *Client) ListAvailabilityZones(ctx context.Context) ([][]common.ELBV3AvailabilityZone, error) {
+	rtn := common.ELBV3AvailabilityZoneList{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("availability-zones"),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return rtn.AvailabilityZones, nil
+}
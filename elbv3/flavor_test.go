@@ -0,0 +1,37 @@
+package elbv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestListFlavors(t *testing.T) {
+	server, c := newFakeServer()
+	for _, flavor := range []map[string]interface{}{
+		{"id": "l4-small", "name": "L4_flavor.elb.s1.small", "type": "L4", "info": map[string]interface{}{"connection": 500000}},
+		{"id": "l7-small", "name": "L7_flavor.elb.s1.small", "type": "L7", "info": map[string]interface{}{"qps": 2000}},
+		{"id": "l4-medium", "name": "L4_flavor.elb.s1.medium", "type": "L4", "info": map[string]interface{}{"connection": 1000000}},
+		{"id": "l4-large", "name": "L4_flavor.elb.s2.large", "type": "L4", "shared": true},
+	} {
+		server.Add("flavors", flavor)
+	}
+
+	flavors, err := c.ListFlavors(context.Background(), &common.ELBV3FlavorListRequest{Limit: 2, Type: []string{"L4"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flavors) != 3 || flavors[1].Info.Connection != 1000000 || !flavors[2].Shared {
+		t.Fatalf("unexpected flavors %#v", flavors)
+	}
+	expected := []string{"flavors?limit=2&type=L4", "flavors?limit=2&marker=l4-medium&type=L4"}
+	if len(server.Queries) != len(expected) {
+		t.Fatalf("unexpected queries %v", server.Queries)
+	}
+	for i := range expected {
+		if server.Queries[i] != expected[i] {
+			t.Fatalf("got query %s, want %s", server.Queries[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func (c *Client) CreateHealthmonitor(ctx context.Context, input *common.ELBV3HealthmonitorRequest) (*common.ELBV3Healthmonitor, error) {
+	if input.Healthmonitor.PoolID == "" {
+		return nil, errors.New("[CreateHealthmonitor]pool id is required")
+	}
+	rtn := common.ELBV3HealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("healthmonitors"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+func (c *Client) GetHealthmonitor(ctx context.Context, id string) (*common.ELBV3Healthmonitor, error) {
+	if id == "" {
+		return nil, errors.New("[GetHealthmonitor]healthmonitor id is required")
+	}
+	rtn := common.ELBV3HealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("healthmonitors", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+// ListHealthmonitors returns the healthmonitors matching filter, all pages are followed
+func (c *Client) ListHealthmonitors(ctx context.Context, filter *common.ELBV3HealthmonitorListRequest) ([]common.ELBV3Healthmonitor, error) {
+	f := common.ELBV3HealthmonitorListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Healthmonitor
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3HealthmonitorList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("healthmonitors"), &f), nil, &page)
+		rtn = append(rtn, page.Healthmonitors...)
+		return page.PageInfo, len(page.Healthmonitors), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateHealthmonitor(ctx context.Context, id string, input *common.ELBV3HealthmonitorRequest) (*common.ELBV3Healthmonitor, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateHealthmonitor]healthmonitor id is required")
+	}
+	rtn := common.ELBV3HealthmonitorDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("healthmonitors", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Healthmonitor, nil
+}
+
+func (c *Client) DeleteHealthmonitor(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteHealthmonitor]healthmonitor id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("healthmonitors", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,60 @@
+package elbv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestHealthmonitorCRUD(t *testing.T) {
+	server, c := newFakeServer()
+	ctx := context.Background()
+	port := int64(8080)
+	monitor, err := c.CreateHealthmonitor(ctx, &common.ELBV3HealthmonitorRequest{Healthmonitor: common.ELBV3Healthmonitor{
+		PoolID:      "pool",
+		Type:        "HTTP",
+		Delay:       5,
+		Timeout:     3,
+		MaxRetries:  3,
+		MonitorPort: &port,
+		URLPath:     "/healthz",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateHealthmonitor(ctx, &common.ELBV3HealthmonitorRequest{Healthmonitor: common.ELBV3Healthmonitor{PoolID: "other", Type: "TCP"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateHealthmonitor(ctx, monitor.ID, &common.ELBV3HealthmonitorRequest{Healthmonitor: common.ELBV3Healthmonitor{
+		Delay:         10,
+		ExpectedCodes: "200-204",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetHealthmonitor(ctx, monitor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Delay != 10 || got.ExpectedCodes != "200-204" || got.URLPath != "/healthz" || *got.MonitorPort != 8080 {
+		t.Fatalf("healthmonitor is not updated %#v", got)
+	}
+
+	monitors, err := c.ListHealthmonitors(ctx, &common.ELBV3HealthmonitorListRequest{Type: []string{"HTTP"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].ID != monitor.ID || server.Queries[0] != "healthmonitors?type=HTTP" {
+		t.Fatalf("unexpected healthmonitors %#v after %v", monitors, server.Queries)
+	}
+
+	if err := c.DeleteHealthmonitor(ctx, monitor.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetHealthmonitor(ctx, monitor.ID); !isNotFound(err) {
+		t.Fatalf("deleted healthmonitor should not be found: %v", err)
+	}
+	if _, err := c.CreateHealthmonitor(ctx, &common.ELBV3HealthmonitorRequest{}); err == nil {
+		t.Fatal("pool id should be required")
+	}
+}
@@ -0,0 +1,94 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func (c *Client) CreateListener(ctx context.Context, input *common.ELBV3ListenerRequest) (*common.ELBV3Listener, error) {
+	if input.Listener.LoadbalancerID == "" {
+		return nil, errors.New("[CreateListener]loadbalancer id is required")
+	}
+	rtn := common.ELBV3ListenerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("listeners"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Listener, nil
+}
+
+func (c *Client) GetListener(ctx context.Context, id string) (*common.ELBV3Listener, error) {
+	if id == "" {
+		return nil, errors.New("[GetListener]listener id is required")
+	}
+	rtn := common.ELBV3ListenerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("listeners", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Listener, nil
+}
+
+// ListListeners returns the listeners matching filter, all pages are followed
+func (c *Client) ListListeners(ctx context.Context, filter *common.ELBV3ListenerListRequest) ([]common.ELBV3Listener, error) {
+	f := common.ELBV3ListenerListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Listener
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3ListenerList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("listeners"), &f), nil, &page)
+		rtn = append(rtn, page.Listeners...)
+		return page.PageInfo, len(page.Listeners), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateListener(ctx context.Context, id string, input *common.ELBV3ListenerRequest) (*common.ELBV3Listener, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateListener]listener id is required")
+	}
+	rtn := common.ELBV3ListenerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("listeners", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Listener, nil
+}
+
+func (c *Client) DeleteListener(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteListener]listener id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("listeners", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,61 @@
+package elbv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestListenerCRUD(t *testing.T) {
+	server, c := newFakeServer()
+	ctx := context.Background()
+	var ids []string
+	for _, port := range []int64{80, 443, 8080} {
+		listener, err := c.CreateListener(ctx, &common.ELBV3ListenerRequest{Listener: common.ELBV3Listener{
+			LoadbalancerID: "lb",
+			Protocol:       "HTTP",
+			ProtocolPort:   port,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, listener.ID)
+	}
+	timeout := int64(120)
+	if _, err := c.UpdateListener(ctx, ids[1], &common.ELBV3ListenerRequest{Listener: common.ELBV3Listener{
+		Protocol:         "HTTPS",
+		KeepaliveTimeout: &timeout,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := c.GetListener(ctx, ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listener.Protocol != "HTTPS" || listener.ProtocolPort != 443 || *listener.KeepaliveTimeout != 120 {
+		t.Fatalf("listener is not updated %#v", listener)
+	}
+
+	// one listener per page, the filter is sent with every page
+	listeners, err := c.ListListeners(ctx, &common.ELBV3ListenerListRequest{Limit: 1, Protocol: []string{"HTTP"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listeners) != 2 || listeners[0].ID != ids[0] || listeners[1].ID != ids[2] || len(server.Queries) != 2 {
+		t.Fatalf("unexpected listeners %#v after %v", listeners, server.Queries)
+	}
+	if server.Queries[1] != "listeners?limit=1&marker="+ids[0]+"&protocol=HTTP" {
+		t.Fatalf("unexpected query %s", server.Queries[1])
+	}
+
+	if err := c.DeleteListener(ctx, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetListener(ctx, ids[0]); !isNotFound(err) {
+		t.Fatalf("deleted listener should not be found: %v", err)
+	}
+	if _, err := c.CreateListener(ctx, &common.ELBV3ListenerRequest{}); err == nil {
+		t.Fatal("load balancer id should be required")
+	}
+}
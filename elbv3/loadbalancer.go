@@ -0,0 +1,101 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// CreateLoadBalancer creates a dedicated load balancer. It is created
+// synchronously, a private IPv4 VIP needs VipSubnetCidrID, an IPv6 VIP
+// IPv6VipVirsubnetID and a new EIP is bound with Publicip.
+func (c *Client) CreateLoadBalancer(ctx context.Context, input *common.ELBV3LoadBalancerRequest) (*common.ELBV3LoadBalancer, error) {
+	if len(input.Loadbalancer.AvailabilityZones) == 0 {
+		return nil, errors.New("[CreateLoadBalancer]availability zones are required")
+	}
+	rtn := common.ELBV3LoadBalancerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("loadbalancers"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	// prepaid load balancers are created by an order and only return the id
+	if rtn.Loadbalancer.ID == "" && rtn.LoadbalancerID != "" {
+		return c.GetLoadBalancer(ctx, rtn.LoadbalancerID)
+	}
+	return &rtn.Loadbalancer, nil
+}
+
+func (c *Client) GetLoadBalancer(ctx context.Context, id string) (*common.ELBV3LoadBalancer, error) {
+	if id == "" {
+		return nil, errors.New("[GetLoadBalancer]loadbalancer id is required")
+	}
+	rtn := common.ELBV3LoadBalancerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("loadbalancers", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Loadbalancer, nil
+}
+
+// ListLoadBalancers returns the load balancers matching filter, all pages are followed
+func (c *Client) ListLoadBalancers(ctx context.Context, filter *common.ELBV3LoadBalancerListRequest) ([]common.ELBV3LoadBalancer, error) {
+	f := common.ELBV3LoadBalancerListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3LoadBalancer
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3LoadBalancerList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("loadbalancers"), &f), nil, &page)
+		rtn = append(rtn, page.Loadbalancers...)
+		return page.PageInfo, len(page.Loadbalancers), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateLoadBalancer(ctx context.Context, id string, input *common.ELBV3LoadBalancerRequest) (*common.ELBV3LoadBalancer, error) {
+	if id == "" {
+		return nil, errors.New("[UpdateLoadBalancer]loadbalancer id is required")
+	}
+	rtn := common.ELBV3LoadBalancerDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("loadbalancers", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Loadbalancer, nil
+}
+
+func (c *Client) DeleteLoadBalancer(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeleteLoadBalancer]loadbalancer id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("loadbalancers", id),
+		nil,
+		nil,
+	)
+	return err
+}
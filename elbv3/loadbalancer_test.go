@@ -0,0 +1,65 @@
+package elbv3
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestCreateLoadBalancer(t *testing.T) {
+	server, c := newFakeServer()
+	ctx := context.Background()
+	request := &common.ELBV3LoadBalancerRequest{Loadbalancer: common.ELBV3LoadBalancer{
+		AvailabilityZones:  []string{"az1", "az2"},
+		IPv6VipVirsubnetID: "network",
+		Publicip: &common.ELBV3PublicIPSpec{
+			NetworkType: "5_bgp",
+			Bandwidth:   common.ELBV3PublicIPBandwidth{Name: "lb", Size: 10, ChargeMode: "traffic", ShareType: "PER"},
+		},
+	}}
+	lb, err := c.CreateLoadBalancer(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := server.items["loadbalancers"][lb.ID]
+	publicip, _ := created["publicip"].(map[string]interface{})
+	if publicip["network_type"] != "5_bgp" || len(lb.AvailabilityZones) != 2 || lb.AvailabilityZones[1] != "az2" {
+		t.Fatalf("unexpected load balancer %#v", created)
+	}
+
+	// prepaid load balancers are read after the order is created
+	server.Prepaid = true
+	lb, err = c.CreateLoadBalancer(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lb.ID == "" || lb.IPv6VipVirsubnetID != "network" || lb.Publicip == nil {
+		t.Fatalf("unexpected prepaid load balancer %#v", lb)
+	}
+	if _, err := c.CreateLoadBalancer(ctx, &common.ELBV3LoadBalancerRequest{}); err == nil {
+		t.Fatal("availability zones should be required")
+	}
+}
+
+func TestListMembers(t *testing.T) {
+	server, c := newFakeServer()
+	server.Add("pools/pool/members", map[string]interface{}{"id": "m1", "address": "10.0.0.1", "member_type": "ip"})
+	server.Add("pools/pool/members", map[string]interface{}{"id": "m2", "address": "10.0.0.2"})
+	server.Add("pools/pool/members", map[string]interface{}{"id": "m3", "address": "10.0.0.3"})
+	members, err := c.ListMembers(context.Background(), "pool", &common.ELBV3MemberListRequest{Limit: 1, Address: []string{"10.0.0.1", "10.0.0.2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || members[0].MemberType != "ip" || members[1].ID != "m2" {
+		t.Fatalf("unexpected members %#v", members)
+	}
+	expected := []string{
+		"pools/pool/members?address=10.0.0.1&address=10.0.0.2&limit=1",
+		"pools/pool/members?address=10.0.0.1&address=10.0.0.2&limit=1&marker=m1",
+	}
+	if strings.Join(server.Queries, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected queries %v", server.Queries)
+	}
+}
@@ -0,0 +1,102 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// CreateMember adds a member to the pool. Members of IP type pools are added by
+// address without SubnetCidrID, the load balancer needs ip_target_enable for them.
+func (c *Client) CreateMember(ctx context.Context, poolID string, input *common.ELBV3MemberRequest) (*common.ELBV3Member, error) {
+	if poolID == "" {
+		return nil, errors.New("[CreateMember]pool id is required")
+	}
+	if input.Member.Address == "" {
+		return nil, errors.New("[CreateMember]address is required")
+	}
+	rtn := common.ELBV3MemberDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("pools", poolID, "members"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Member, nil
+}
+
+func (c *Client) GetMember(ctx context.Context, poolID, id string) (*common.ELBV3Member, error) {
+	if poolID == "" || id == "" {
+		return nil, errors.New("pool id and member id is both required")
+	}
+	rtn := common.ELBV3MemberDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("pools", poolID, "members", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Member, nil
+}
+
+// ListMembers returns the members matching filter, all pages are followed
+func (c *Client) ListMembers(ctx context.Context, poolID string, filter *common.ELBV3MemberListRequest) ([]common.ELBV3Member, error) {
+	if poolID == "" {
+		return nil, errors.New("[ListMembers]pool id is required")
+	}
+	f := common.ELBV3MemberListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Member
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3MemberList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("pools", poolID, "members"), &f), nil, &page)
+		rtn = append(rtn, page.Members...)
+		return page.PageInfo, len(page.Members), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdateMember(ctx context.Context, poolID, id string, input *common.ELBV3MemberRequest) (*common.ELBV3Member, error) {
+	if poolID == "" || id == "" {
+		return nil, errors.New("pool id and member id is both required")
+	}
+	rtn := common.ELBV3MemberDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("pools", poolID, "members", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Member, nil
+}
+
+func (c *Client) DeleteMember(ctx context.Context, poolID, id string) error {
+	if poolID == "" || id == "" {
+		return errors.New("pool id and member id is both required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("pools", poolID, "members", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,94 @@
+package elbv3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func (c *Client) CreatePool(ctx context.Context, input *common.ELBV3PoolRequest) (*common.ELBV3Pool, error) {
+	if input.Pool.LoadbalancerID == "" && input.Pool.ListenerID == "" && input.Pool.Type == "" {
+		return nil, errors.New("you have to specify one of ListenerID, LoadbalancerID and Type")
+	}
+	rtn := common.ELBV3PoolDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPost,
+		c.GetURL("pools"),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Pool, nil
+}
+
+func (c *Client) GetPool(ctx context.Context, id string) (*common.ELBV3Pool, error) {
+	if id == "" {
+		return nil, errors.New("[GetPool]pool id is required")
+	}
+	rtn := common.ELBV3PoolDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		c.GetURL("pools", id),
+		nil,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Pool, nil
+}
+
+// ListPools returns the pools matching filter, all pages are followed
+func (c *Client) ListPools(ctx context.Context, filter *common.ELBV3PoolListRequest) ([]common.ELBV3Pool, error) {
+	f := common.ELBV3PoolListRequest{}
+	if filter != nil {
+		f = *filter
+	}
+	var rtn []common.ELBV3Pool
+	err := listAll(f.Marker, func(marker string) (common.ELBV3PageInfo, int, error) {
+		f.Marker = marker
+		page := common.ELBV3PoolList{}
+		_, err := c.DoRequest(ctx, http.MethodGet, common.WithQuery(c.GetURL("pools"), &f), nil, &page)
+		rtn = append(rtn, page.Pools...)
+		return page.PageInfo, len(page.Pools), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (c *Client) UpdatePool(ctx context.Context, id string, input *common.ELBV3PoolRequest) (*common.ELBV3Pool, error) {
+	if id == "" {
+		return nil, errors.New("[UpdatePool]pool id is required")
+	}
+	rtn := common.ELBV3PoolDetails{}
+	if _, err := c.DoRequest(
+		ctx,
+		http.MethodPut,
+		c.GetURL("pools", id),
+		input,
+		&rtn,
+	); err != nil {
+		return nil, err
+	}
+	return &rtn.Pool, nil
+}
+
+func (c *Client) DeletePool(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("[DeletePool]pool id is required")
+	}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodDelete,
+		c.GetURL("pools", id),
+		nil,
+		nil,
+	)
+	return err
+}
@@ -0,0 +1,104 @@
+package elbv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+func TestPoolCRUD(t *testing.T) {
+	server, c := newFakeServer()
+	ctx := context.Background()
+	pool, err := c.CreatePool(ctx, &common.ELBV3PoolRequest{Pool: common.ELBV3Pool{
+		LoadbalancerID: "lb",
+		Protocol:       "TCP",
+		LbAlgorithm:    "ROUND_ROBIN",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreatePool(ctx, &common.ELBV3PoolRequest{Pool: common.ELBV3Pool{ListenerID: "listener", Protocol: "HTTP"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdatePool(ctx, pool.ID, &common.ELBV3PoolRequest{Pool: common.ELBV3Pool{
+		LbAlgorithm:        "SOURCE_IP",
+		SessionPersistence: &common.ELBV3SessionPersistence{Type: "SOURCE_IP"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetPool(ctx, pool.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LbAlgorithm != "SOURCE_IP" || got.Protocol != "TCP" || got.SessionPersistence == nil {
+		t.Fatalf("pool is not updated %#v", got)
+	}
+
+	pools, err := c.ListPools(ctx, &common.ELBV3PoolListRequest{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 2 || pools[0].ID != pool.ID || len(server.Queries) != 2 {
+		t.Fatalf("unexpected pools %#v after %v", pools, server.Queries)
+	}
+
+	if err := c.DeletePool(ctx, pool.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetPool(ctx, pool.ID); !isNotFound(err) {
+		t.Fatalf("deleted pool should not be found: %v", err)
+	}
+	if _, err := c.CreatePool(ctx, &common.ELBV3PoolRequest{}); err == nil {
+		t.Fatal("pool without load balancer, listener or type should be rejected")
+	}
+}
+
+func TestMemberCRUD(t *testing.T) {
+	server, c := newFakeServer()
+	ctx := context.Background()
+	member, err := c.CreateMember(ctx, "pool", &common.ELBV3MemberRequest{Member: common.ELBV3Member{
+		Address:      "192.168.0.1",
+		ProtocolPort: 80,
+		SubnetCidrID: "subnet",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateMember(ctx, "other", &common.ELBV3MemberRequest{Member: common.ELBV3Member{Address: "192.168.0.2"}}); err != nil {
+		t.Fatal(err)
+	}
+	// draining a member sends the explicit zero weight
+	drained := int64(0)
+	if _, err := c.UpdateMember(ctx, "pool", member.ID, &common.ELBV3MemberRequest{Member: common.ELBV3Member{Weight: &drained}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetMember(ctx, "pool", member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Weight == nil || *got.Weight != 0 || got.Address != "192.168.0.1" {
+		t.Fatalf("member is not updated %#v", got)
+	}
+	if _, err := c.GetMember(ctx, "other", member.ID); !isNotFound(err) {
+		t.Fatalf("member of another pool should not be found: %v", err)
+	}
+
+	members, err := c.ListMembers(ctx, "pool", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].ID != member.ID || server.Queries[0] != "pools/pool/members?" {
+		t.Fatalf("unexpected members %#v after %v", members, server.Queries)
+	}
+
+	if err := c.DeleteMember(ctx, "pool", member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetMember(ctx, "pool", member.ID); !isNotFound(err) {
+		t.Fatalf("deleted member should not be found: %v", err)
+	}
+	if _, err := c.CreateMember(ctx, "pool", &common.ELBV3MemberRequest{}); err == nil {
+		t.Fatal("address should be required")
+	}
+}
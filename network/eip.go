@@ -3,13 +3,31 @@ package network
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/cnrancher/huaweicloud-sdk/common"
+	"github.com/sirupsen/logrus"
+)
+
+// Statuses of an EIP, a new EIP is PENDING_CREATE until it can be bound
+const (
+	EIPStatusDown      = "DOWN"
+	EIPStatusActive    = "ACTIVE"
+	EIPStatusError     = "ERROR"
+	EIPStatusBindError = "BIND_ERROR"
+)
+
+const (
+	// DefaultEIPPollInterval and DefaultEIPTimeout are used by WaitForEIPReady
+	// when duration or timeout is 0
+	DefaultEIPPollInterval = 2 * time.Second
+	DefaultEIPTimeout      = 5 * time.Minute
 )
 
 func (c *Client) CreateEIP(ctx context.Context, info *common.EipAllocArg) (*common.EipInfo, error) {
-	rtn := common.EipInfo{}
+	rtn := common.EipResp{}
 	_, err := c.DoRequest(
 		ctx,
 		http.MethodPost,
@@ -20,7 +38,7 @@ func (c *Client) CreateEIP(ctx context.Context, info *common.EipAllocArg) (*comm
 	if err != nil {
 		return nil, err
 	}
-	return &rtn, nil
+	return &rtn.Eip, nil
 }
 
 func (c *Client) GetEIP(ctx context.Context, id string) (*common.EipInfo, error) {
@@ -75,3 +93,73 @@ func (c *Client) DeleteEIP(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// WaitForEIPReady polls the EIP every duration until it is DOWN (not bound)
+// or ACTIVE (bound), a created EIP cannot be bound before
+func (c *Client) WaitForEIPReady(ctx context.Context, duration, timeout time.Duration, id string) (*common.EipInfo, error) {
+	if duration <= 0 {
+		duration = DefaultEIPPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultEIPTimeout
+	}
+	var rtn *common.EipInfo
+	err := common.CustomWaitForCompleteUntilTrue(ctx, duration, timeout, func(ictx context.Context) (bool, error) {
+		eip, err := c.GetEIP(ictx, id)
+		if err != nil {
+			return false, err
+		}
+		switch eip.Status {
+		case EIPStatusDown, EIPStatusActive:
+			rtn = eip
+			return true, nil
+		case EIPStatusError, EIPStatusBindError:
+			return false, fmt.Errorf("eip %s is in %s status", id, eip.Status)
+		default:
+			logrus.Debugf("eip %s is still %s", id, eip.Status)
+			return false, nil
+		}
+	})
+	return rtn, err
+}
+
+// ListEIPs returns a page of EIPs, the next page starts after the last EIP of
+// the page when it is used as filter.Marker
+func (c *Client) ListEIPs(ctx context.Context, filter *common.EipListRequest) ([]common.EipInfo, error) {
+	rtn := common.EipListResp{}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		common.WithQuery(c.GetURL("publicips"), filter),
+		nil,
+		&rtn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rtn.Eips, nil
+}
+
+// GetEIPByPort returns the EIP bound to the port, nil when there is none
+func (c *Client) GetEIPByPort(ctx context.Context, portID string) (*common.EipInfo, error) {
+	if portID == "" {
+		return nil, errors.New("[GetEIPByPort]port id is required")
+	}
+	filter := &common.EipListRequest{Limit: 100, PortID: portID}
+	for {
+		eips, err := c.ListEIPs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		// older regions ignore the port_id filter
+		for i := range eips {
+			if eips[i].PortID == portID {
+				return &eips[i], nil
+			}
+		}
+		if int64(len(eips)) < filter.Limit {
+			return nil, nil
+		}
+		filter.Marker = eips[len(eips)-1].ID
+	}
+}
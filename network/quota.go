@@ -0,0 +1,37 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// quota types of vpc
+const (
+	QuotaVPC           = "vpc"
+	QuotaSubnet        = "subnet"
+	QuotaSecurityGroup = "securityGroup"
+	QuotaPublicIP      = "publicIp"
+)
+
+// GetQuotas returns quota and usage of the project, all types are returned when resourceType is empty
+func (c *Client) GetQuotas(ctx context.Context, resourceType string) (*common.VPCQuotaList, error) {
+	path := c.GetURL("quotas")
+	if resourceType != "" {
+		path += "?type=" + url.QueryEscape(resourceType)
+	}
+	rtn := common.VPCQuotaList{}
+	_, err := c.DoRequest(
+		ctx,
+		http.MethodGet,
+		path,
+		nil,
+		&rtn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
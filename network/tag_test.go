@@ -0,0 +1,74 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnrancher/huaweicloud-sdk/common"
+)
+
+// tagRecorder records the requests as "<method> <url> <body>" and answers
+// all of them with the tags of a resource
+type tagRecorder []string
+
+func (t *tagRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	request := r.Method + " " + r.URL.String()
+	if r.Body != nil {
+		b, _ := ioutil.ReadAll(r.Body)
+		request += " " + string(b)
+	}
+	*t = append(*t, request)
+	body := `{"tags":[{"key":"owner","value":"ci"}],"resources":[],"total_count":0}`
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func TestTags(t *testing.T) {
+	recorder := &tagRecorder{}
+	base := common.NewClient("abcd", "def", "myhuawei.com", "cn-north-1", "test")
+	base.GetSigner().NextTransport = recorder
+	c := NewClient(base)
+	ctx := context.Background()
+
+	if err := c.DeleteTag(ctx, TagResourceVPC, "", "owner"); err == nil {
+		t.Fatal("expected error without resource id")
+	}
+	if err := c.CreateTag(ctx, TagResourceSubnet, "subnet-1", common.Tag{Key: "owner", Value: "ci"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteTag(ctx, TagResourceEIP, "eip-1", "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BatchUpdateTags(ctx, TagResourceVPC, "vpc-1", common.TagActionDelete, []common.Tag{{Key: "env"}}); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := c.GetTags(ctx, TagResourceVPC, "vpc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Value != "ci" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	if _, err := c.ListResourcesByTags(ctx, TagResourceEIP, &common.ResourceTagFilter{
+		Action:  common.TagActionCount,
+		NotTags: []common.TagFilter{{Key: "owner"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// tags are only served by the v2.0 api
+	url := "https://vpc.cn-north-1.myhuawei.com/v2.0/test"
+	expected := []string{
+		`POST ` + url + `/subnets/subnet-1/tags {"tag":{"key":"owner","value":"ci"}}`,
+		`DELETE ` + url + `/publicips/eip-1/tags/owner`,
+		`POST ` + url + `/vpcs/vpc-1/tags/action {"action":"delete","tags":[{"key":"env","value":""}]}`,
+		`GET ` + url + `/vpcs/vpc-1/tags`,
+		`POST ` + url + `/publicips/resource_instances/action {"action":"count","not_tags":[{"key":"owner","values":null}]}`,
+	}
+	if strings.Join(*recorder, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(*recorder, "\n"))
+	}
+}
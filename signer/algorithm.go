@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"time"
+
+	"github.com/cnrancher/huaweicloud-sdk/signer/sm3"
+)
+
+// SigningAlgorithm describes the hash function, the name and the key
+// derivation chain used to sign a request
+type SigningAlgorithm struct {
+	// Name is sent as the first field of the Authorization header and the string to sign
+	Name string
+	// Hash is used for the payload, the canonical request and the HMAC
+	Hash func() hash.Hash
+	// DeriveKey returns the key which signs the string to sign
+	DeriveKey func(a *SigningAlgorithm, secretKey, regionName, serviceName string, t time.Time) ([]byte, error)
+	// Scope returns the credential scope. An empty scope is left out of the
+	// string to sign and the access key is sent as "Access=" instead of "Credential="
+	Scope func(t time.Time, regionName, serviceName string) string
+	// ContentHeader carries a precomputed hex encoded payload hash, e.g. for
+	// streamed bodies. It has to be computed with Hash.
+	ContentHeader string
+}
+
+var (
+	// HMACSHA256 is the default SDK-HMAC-SHA256 algorithm with a scoped signing key
+	HMACSHA256 = &SigningAlgorithm{
+		Name:          Algorithm,
+		Hash:          sha256.New,
+		DeriveKey:     DeriveScopedKey,
+		Scope:         CredentialScope,
+		ContentHeader: HeaderContentSha256,
+	}
+	// APIGHMACSHA256 is the SDK-HMAC-SHA256 variant of API Gateway apps which
+	// signs with the app secret directly and has no credential scope
+	APIGHMACSHA256 = &SigningAlgorithm{
+		Name:          Algorithm,
+		Hash:          sha256.New,
+		DeriveKey:     SecretKey,
+		Scope:         NoScope,
+		ContentHeader: HeaderContentSha256,
+	}
+	// APIGHMACSM3 is the SDK-HMAC-SM3 algorithm of the official Huawei Cloud
+	// SDKs: it signs with the secret key directly, has no credential scope and
+	// reads a precomputed payload hash from X-Sdk-Content-Sm3
+	APIGHMACSM3 = &SigningAlgorithm{
+		Name:          AlgorithmSM3,
+		Hash:          sm3.New,
+		DeriveKey:     SecretKey,
+		Scope:         NoScope,
+		ContentHeader: HeaderContentSm3,
+	}
+)
+
+// DeriveScopedKey chains HMACs over date, region, service and TerminationString
+// starting from PreSKString + secretKey
+func DeriveScopedKey(a *SigningAlgorithm, secretKey, regionName, serviceName string, t time.Time) ([]byte, error) {
+	key := []byte(PreSKString + secretKey)
+	var err error
+	data := []string{t.UTC().Format(BasicDateFormatShort), regionName, serviceName, TerminationString}
+	for _, d := range data {
+		if key, err = a.hmac(key, d); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// SecretKey uses the secret key as signing key
+func SecretKey(a *SigningAlgorithm, secretKey, regionName, serviceName string, t time.Time) ([]byte, error) {
+	return []byte(secretKey), nil
+}
+
+// NoScope is the scope of algorithms which do not bind the signature to a region or service
+func NoScope(t time.Time, regionName, serviceName string) string {
+	return ""
+}
+
+func (a *SigningAlgorithm) hmac(key []byte, data string) ([]byte, error) {
+	h := hmac.New(a.Hash, key)
+	if _, err := h.Write([]byte(data)); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// HexEncodeHash returns the hex encoded hash of data
+func (a *SigningAlgorithm) HexEncodeHash(data []byte) (string, error) {
+	h := a.Hash()
+	_, err := h.Write(data)
+	return fmt.Sprintf("%x", h.Sum(nil)), err
+}
+
+// StringToSign creates the string to sign, the scope line is omitted when credentialScope is empty
+func (a *SigningAlgorithm) StringToSign(canonicalRequest, credentialScope string, t time.Time) (string, error) {
+	hashed, err := a.HexEncodeHash([]byte(canonicalRequest))
+	if err != nil {
+		return "", err
+	}
+	if credentialScope == "" {
+		return fmt.Sprintf("%s\n%s\n%s", a.Name, t.UTC().Format(BasicDateFormat), hashed), nil
+	}
+	return fmt.Sprintf("%s\n%s\n%s\n%s", a.Name, t.UTC().Format(BasicDateFormat), credentialScope, hashed), nil
+}
+
+// SignStringToSign returns the hex encoded HMAC of stringToSign
+func (a *SigningAlgorithm) SignStringToSign(stringToSign string, signingKey []byte) (string, error) {
+	hm, err := a.hmac(signingKey, stringToSign)
+	return fmt.Sprintf("%x", hm), err
+}
+
+// AuthHeaderValue returns the value of the Authorization header
+func (a *SigningAlgorithm) AuthHeaderValue(signature, accessKey, credentialScope, signedHeaders string) string {
+	if credentialScope == "" {
+		return fmt.Sprintf("%s Access=%s, SignedHeaders=%s, Signature=%s", a.Name, accessKey, signedHeaders, signature)
+	}
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", a.Name, accessKey, credentialScope, signedHeaders, signature)
+}
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// The scoped SDK-HMAC-SHA256 signature is the one of TestAuthHeader. The
+// unscoped ones are HMAC(secret key, string to sign) and were cross-checked
+// with the hashlib and hmac modules of Python.
+func TestSigningAlgorithms(t *testing.T) {
+	tests := []struct {
+		algorithm *SigningAlgorithm
+		auth      string
+	}{
+		{nil, `SDK-HMAC-SHA256 Credential=AKIDEXAMPLE/20110909/cn-north-1/host/sdk_request, SignedHeaders=date;host, Signature=acc863b58ef92620f9c63ea64c1e01dec2c46a1d8333066767e1f204c71832f2`},
+		{HMACSHA256, `SDK-HMAC-SHA256 Credential=AKIDEXAMPLE/20110909/cn-north-1/host/sdk_request, SignedHeaders=date;host, Signature=acc863b58ef92620f9c63ea64c1e01dec2c46a1d8333066767e1f204c71832f2`},
+		{APIGHMACSHA256, `SDK-HMAC-SHA256 Access=AKIDEXAMPLE, SignedHeaders=date;host, Signature=a6b444c5fceade65f331a3bd0e485208fc91a50d8cb4ed76ca2459e6d346fc64`},
+		{APIGHMACSM3, `SDK-HMAC-SM3 Access=AKIDEXAMPLE, SignedHeaders=date;host, Signature=d9ba1b1020ae1339c858dbfa3a8f50992bd9eb2e19f0d7f6e514b29ff2e8f330`},
+	}
+	for _, tt := range tests {
+		s := Signer{
+			AccessKey:          "AKIDEXAMPLE",
+			SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+			Region:             "cn-north-1",
+			GetServiceNameFunc: func() string { return "host" },
+			SigningAlgorithm:   tt.algorithm,
+		}
+		r, _ := http.NewRequest("GET", "http://host.foo.com/%20/foo", nil)
+		r.Header.Add("date", "Mon, 09 Sep 2011 23:36:00 GMT")
+		if err := s.Sign(r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Header.Get("authorization") != tt.auth {
+			t.Fatal(r.Header.Get("authorization"), "miss match")
+		}
+	}
+}
+
+func TestContentHeader(t *testing.T) {
+	payloadHash := func(a *SigningAlgorithm, header, value string) string {
+		r, _ := http.NewRequest("POST", "http://host.foo.com/", strings.NewReader("abc"))
+		r.Header.Set(header, value)
+		canonical, err := canonicalRequest(r, []string{HeaderHost}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(canonical, "\n")
+		return lines[len(lines)-1]
+	}
+	sm3abc := "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"
+	if got := payloadHash(APIGHMACSM3, HeaderContentSm3, "precomputed"); got != "precomputed" {
+		t.Fatalf("%s is not used by %s, got %s", HeaderContentSm3, AlgorithmSM3, got)
+	}
+	if got := payloadHash(APIGHMACSM3, HeaderContentSha256, "precomputed"); got != sm3abc {
+		t.Fatalf("%s should be ignored by %s, got %s", HeaderContentSha256, AlgorithmSM3, got)
+	}
+	if got := payloadHash(HMACSHA256, HeaderContentSha256, "precomputed"); got != "precomputed" {
+		t.Fatalf("%s is not used by %s, got %s", HeaderContentSha256, Algorithm, got)
+	}
+}
@@ -23,13 +23,35 @@ const (
 	BasicDateFormatShort = "20060102"
 	TerminationString    = "sdk_request"
 	Algorithm            = "SDK-HMAC-SHA256"
+	AlgorithmSM3         = "SDK-HMAC-SM3"
 	PreSKString          = "SDK"
 	HeaderXDate          = "x-sdk-date"
 	HeaderDate           = "date"
 	HeaderHost           = "host"
+	HeaderContentType    = "content-type"
+	HeaderProjectID      = "x-project-id"
+	HeaderDomainID       = "x-domain-id"
+	HeaderSecurityToken  = "x-security-token"
+	HeaderContentSha256  = "x-sdk-content-sha256"
+	HeaderContentSm3     = "x-sdk-content-sm3"
 	HeaderAuthorization  = "Authorization"
 )
 
+// DefaultSignedHeaders is the allow-list of headers included in the signature
+// when Signer.SignedHeaderKeys is empty. Headers outside of the list, such as
+// the ones added by proxies after signing, are left out of the signature.
+var DefaultSignedHeaders = []string{
+	HeaderContentType,
+	HeaderDate,
+	HeaderHost,
+	HeaderProjectID,
+	HeaderDomainID,
+	HeaderContentSha256,
+	HeaderContentSm3,
+	HeaderSecurityToken,
+	HeaderXDate,
+}
+
 func hmacsha256(key []byte, data string) ([]byte, error) {
 	h := hmac.New(sha256.New, []byte(key))
 	if _, err := h.Write([]byte(data)); err != nil {
@@ -49,87 +71,140 @@ func hmacsha256(key []byte, data string) ([]byte, error) {
 //  SignedHeaders + '\n' +
 //  HexEncode(Hash(RequestPayload))
 func CanonicalRequest(r *http.Request) (string, error) {
-	data, err := RequestPayload(r)
-	if err != nil {
-		return "", err
+	return canonicalRequest(r, signedHeaderKeys(r, DefaultSignedHeaders), HMACSHA256)
+}
+
+func canonicalRequest(r *http.Request, signedHeaders []string, a *SigningAlgorithm) (string, error) {
+	var hexencode string
+	if v := r.Header.Get(a.ContentHeader); a.ContentHeader != "" && v != "" {
+		hexencode = v
+	} else {
+		data, err := RequestPayload(r)
+		if err != nil {
+			return "", err
+		}
+		if hexencode, err = a.HexEncodeHash(data); err != nil {
+			return "", err
+		}
 	}
-	hexencode, err := HexEncodeSHA256Hash(data)
-	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", r.Method, CanonicalURI(r), CanonicalQueryString(r), CanonicalHeaders(r), SignedHeaders(r), hexencode), err
+	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", r.Method, CanonicalURI(r), CanonicalQueryString(r), canonicalHeaders(r, signedHeaders), strings.Join(signedHeaders, ";"), hexencode), nil
 }
 
-// CanonicalURI returns request uri
+// CanonicalURI returns request uri. The escaped path which is sent on the wire
+// is used so that pre-encoded segments (e.g. %2F) keep their boundaries, every
+// segment is then normalized to the RFC 3986 encoding. The request is not modified.
 func CanonicalURI(r *http.Request) string {
-	pattens := strings.Split(r.URL.Path, "/")
-	var uri []string
-	for _, v := range pattens {
-		switch v {
-		case "":
-			continue
-		case ".":
-			continue
-		case "..":
-			if len(uri) > 0 {
-				uri = uri[:len(uri)-1]
-			}
-		default:
-			uri = append(uri, url.QueryEscape(v))
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+	uri := make([]string, 0, len(segments))
+	for _, v := range segments {
+		if unescaped, err := url.PathUnescape(v); err == nil {
+			v = unescaped
 		}
+		uri = append(uri, escape(v))
+	}
+	urlpath := strings.Join(uri, "/")
+	if !strings.HasPrefix(urlpath, "/") {
+		urlpath = "/" + urlpath
 	}
-	urlpath := "/" + strings.Join(uri, "/")
-	r.URL.Path = strings.Replace(urlpath, "+", "%20", -1)
-	if ok := strings.HasSuffix(r.URL.Path, "/"); ok {
-		return fmt.Sprintf("%s", r.URL.Path)
+	if !strings.HasSuffix(urlpath, "/") {
+		urlpath += "/"
 	}
-	return fmt.Sprintf("%s", r.URL.Path+"/")
+	return urlpath
 }
 
+// CanonicalQueryString returns the sorted and RFC 3986 encoded query string.
+// Repeated keys produce one pair per value and empty values are kept as "key=".
 func CanonicalQueryString(r *http.Request) string {
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 	var a []string
-	for key, value := range r.URL.Query() {
-		k := url.QueryEscape(key)
-		for _, v := range value {
-			var kv string
-			if v == "" {
-				kv = k
-			} else {
-				kv = fmt.Sprintf("%s=%s", k, url.QueryEscape(v))
-			}
-			a = append(a, strings.Replace(kv, "+", "%20", -1))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		k := escape(key)
+		for _, v := range values {
+			a = append(a, k+"="+escape(v))
 		}
 	}
-	sort.Strings(a)
-	return fmt.Sprintf("%s", strings.Join(a, "&"))
+	return strings.Join(a, "&")
 }
 
+// CanonicalHeaders returns the canonical form of the default signed headers
 func CanonicalHeaders(r *http.Request) string {
+	return canonicalHeaders(r, signedHeaderKeys(r, DefaultSignedHeaders))
+}
+
+func canonicalHeaders(r *http.Request, signedHeaders []string) string {
+	header := make(map[string][]string, len(r.Header))
+	for k, v := range r.Header {
+		k = strings.ToLower(k)
+		header[k] = append(header[k], v...)
+	}
 	var a []string
-	for key, value := range r.Header {
+	for _, key := range signedHeaders {
+		value := header[key]
+		if key == HeaderHost {
+			value = []string{requestHost(r)}
+		}
+		value = append([]string(nil), value...)
 		sort.Strings(value)
-		var q []string
 		for _, v := range value {
-			q = append(q, trimString(v))
+			a = append(a, key+":"+trimString(v))
 		}
-		a = append(a, strings.ToLower(key)+":"+strings.Join(q, ","))
 	}
-	a = append(a, HeaderHost+":"+r.Host)
-	sort.Strings(a)
 	return fmt.Sprintf("%s\n", strings.Join(a, "\n"))
 }
 
+// SignedHeaders returns the default signed header names joined by ";"
 func SignedHeaders(r *http.Request) string {
-	var a []string
+	return strings.Join(signedHeaderKeys(r, DefaultSignedHeaders), ";")
+}
+
+// signedHeaderKeys returns the sorted lower case names of the allowed headers
+// which are present on the request. Host is always signed.
+func signedHeaderKeys(r *http.Request, allowed []string) []string {
+	allow := map[string]bool{HeaderHost: true}
+	for _, k := range allowed {
+		allow[strings.ToLower(k)] = true
+	}
+	seen := map[string]bool{HeaderHost: true}
+	a := []string{HeaderHost}
 	for key := range r.Header {
-		a = append(a, strings.ToLower(key))
+		key = strings.ToLower(key)
+		if allow[key] && !seen[key] {
+			seen[key] = true
+			a = append(a, key)
+		}
 	}
-	a = append(a, HeaderHost)
 	sort.Strings(a)
-	return fmt.Sprintf("%s", strings.Join(a, ";"))
+	return a
 }
 
+func requestHost(r *http.Request) string {
+	if r.Host != "" {
+		return r.Host
+	}
+	return r.URL.Host
+}
+
+// RequestPayload returns the request body. GetBody is preferred so that the
+// body of the request is not consumed, otherwise the body is buffered and reset.
 func RequestPayload(r *http.Request) ([]byte, error) {
-	if r.Body == nil {
+	if r.Body == nil || r.Body == http.NoBody {
 		return []byte(""), nil
 	}
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
 	b, err := ioutil.ReadAll(r.Body)
 	r.Body = ioutil.NopCloser(bytes.NewBuffer(b))
 	return b, err
@@ -150,18 +225,7 @@ func StringToSign(canonicalRequest, credentialScope string, t time.Time) string
 
 //GenerateSigningKey Generate a "signing key" to sign the "String To Sign". See http://docs.aws.amazon.com/general/latest/gr/sigv4-calculate-signature.html
 func GenerateSigningKey(secretKey, regionName, serviceName string, t time.Time) ([]byte, error) {
-
-	key := []byte(PreSKString + secretKey)
-	var err error
-	dateStamp := t.UTC().Format(BasicDateFormatShort)
-	data := []string{dateStamp, regionName, serviceName, TerminationString}
-	for _, d := range data {
-		key, err = hmacsha256(key, d)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return key, nil
+	return DeriveScopedKey(HMACSHA256, secretKey, regionName, serviceName, t)
 }
 
 //SignStringToSign Create the HWS Signature. See http://docs.aws.amazon.com/general/latest/gr/sigv4-calculate-signature.html
@@ -185,6 +249,21 @@ func AuthHeaderValue(signature, accessKey, credentialScope, signedHeaders string
 	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", Algorithm, accessKey, credentialScope, signedHeaders, signature)
 }
 
+// escape encodes everything except the RFC 3986 unreserved characters
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
 func trimString(s string) string {
 	var trimedString []byte
 	inQuote := false
@@ -210,6 +289,17 @@ type Signer struct {
 	Region             string
 	GetServiceNameFunc func() string
 	NextTransport      http.RoundTripper
+	// SignedHeaderKeys overrides DefaultSignedHeaders when it is not empty
+	SignedHeaderKeys []string
+	// SigningAlgorithm defaults to HMACSHA256
+	SigningAlgorithm *SigningAlgorithm
+}
+
+func (s *Signer) algorithm() *SigningAlgorithm {
+	if s.SigningAlgorithm != nil {
+		return s.SigningAlgorithm
+	}
+	return HMACSHA256
 }
 
 // Sign set Authorization header
@@ -231,32 +321,43 @@ func (s *Signer) Sign(r *http.Request) (err error) {
 		t = time.Now()
 		r.Header.Set(HeaderXDate, t.UTC().Format(BasicDateFormat))
 	}
-	canonicalRequest, err := CanonicalRequest(r)
+	allowed := s.SignedHeaderKeys
+	if len(allowed) == 0 {
+		allowed = DefaultSignedHeaders
+	}
+	a := s.algorithm()
+	signedHeaders := signedHeaderKeys(r, allowed)
+	canonicalRequest, err := canonicalRequest(r, signedHeaders, a)
+	if err != nil {
+		return err
+	}
+	credentialScope := a.Scope(t, s.Region, s.GetServiceNameFunc())
+	stringToSign, err := a.StringToSign(canonicalRequest, credentialScope, t)
 	if err != nil {
 		return err
 	}
-	credentialScope := CredentialScope(t, s.Region, s.GetServiceNameFunc())
-	stringToSign := StringToSign(canonicalRequest, credentialScope, t)
-	key, err := GenerateSigningKey(s.SecretKey, s.Region, s.GetServiceNameFunc(), t)
+	key, err := a.DeriveKey(a, s.SecretKey, s.Region, s.GetServiceNameFunc(), t)
 	if err != nil {
 		return err
 	}
-	signature, err := SignStringToSign(stringToSign, key)
+	signature, err := a.SignStringToSign(stringToSign, key)
 	if err != nil {
 		return err
 	}
-	signedHeaders := SignedHeaders(r)
-	authValue := AuthHeaderValue(signature, s.AccessKey, credentialScope, signedHeaders)
+	authValue := a.AuthHeaderValue(signature, s.AccessKey, credentialScope, strings.Join(signedHeaders, ";"))
 	r.Header.Set(HeaderAuthorization, authValue)
 	return nil
 }
 
+// RoundTrip signs a copy of the request, the caller's request is left untouched
+// as required by http.RoundTripper.
 func (s *Signer) RoundTrip(req *http.Request) (*http.Response, error) {
-	if err := s.Sign(req); err != nil {
+	signed := req.Clone(req.Context())
+	if err := s.Sign(signed); err != nil {
 		return nil, err
 	}
 	if s.NextTransport != nil {
-		return s.NextTransport.RoundTrip(req)
+		return s.NextTransport.RoundTrip(signed)
 	}
-	return http.DefaultTransport.RoundTrip(req)
+	return http.DefaultTransport.RoundTrip(signed)
 }
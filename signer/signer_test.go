@@ -11,13 +11,13 @@ import (
 
 func TestGenerateSigningKey(t *testing.T) {
 	s := Signer{
-		AccessKey: "AKIDEXAMPLE",
-		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
-		Region:    "cn-north-1",
-		Service:   "ec2",
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "ec2" },
 	}
 	tt, _ := time.Parse(time.RFC1123, "Mon, 09 Sep 2011 23:36:00 GMT")
-	k, err := GenerateSigningKey(s.SecretKey, s.Region, s.Service, tt)
+	k, err := GenerateSigningKey(s.SecretKey, s.Region, s.GetServiceNameFunc(), tt)
 	if err != nil {
 		t.Fatal("failed to generate signing key", string(k))
 	}
@@ -28,13 +28,13 @@ func TestGenerateSigningKey(t *testing.T) {
 
 func TestCredentailScope(t *testing.T) {
 	s := Signer{
-		AccessKey: "AKIDEXAMPLE",
-		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
-		Region:    "cn-north-1",
-		Service:   "ec2",
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "ec2" },
 	}
 	tt, _ := time.Parse(time.RFC1123, "Mon, 09 Sep 2011 23:36:00 GMT")
-	credentialScope := CredentialScope(tt, s.Region, s.Service)
+	credentialScope := CredentialScope(tt, s.Region, s.GetServiceNameFunc())
 	if credentialScope != "20110909/cn-north-1/ec2/sdk_request" {
 		t.Fatal("wrong credentialscope")
 	}
@@ -59,16 +59,16 @@ e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855`
 
 func TestStringToSign(t *testing.T) {
 	s := Signer{
-		AccessKey: "AKIDEXAMPLE",
-		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
-		Region:    "cn-north-1",
-		Service:   "host",
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "host" },
 	}
 	r, _ := http.NewRequest("GET", "http://host.foo.com/%20/foo", nil)
 	r.Header.Add("date", "Mon, 09 Sep 2011 23:36:00 GMT")
 	canonicalRequest, _ := CanonicalRequest(r)
 	tt, _ := time.Parse(time.RFC1123, "Mon, 09 Sep 2011 23:36:00 GMT")
-	credentialScope := CredentialScope(tt, s.Region, s.Service)
+	credentialScope := CredentialScope(tt, s.Region, s.GetServiceNameFunc())
 	stringToSign := StringToSign(canonicalRequest, credentialScope, tt)
 	if stringToSign != `SDK-HMAC-SHA256
 20110909T233600Z
@@ -80,10 +80,10 @@ func TestStringToSign(t *testing.T) {
 
 func TestAuthHeader(t *testing.T) {
 	s := Signer{
-		AccessKey: "AKIDEXAMPLE",
-		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
-		Region:    "cn-north-1",
-		Service:   "host",
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "host" },
 	}
 	r, _ := http.NewRequest("GET", "http://host.foo.com/%20/foo", nil)
 	r.Header.Add("date", "Mon, 09 Sep 2011 23:36:00 GMT")
@@ -95,10 +95,10 @@ func TestAuthHeader(t *testing.T) {
 
 func TestPostHeader(t *testing.T) {
 	s := Signer{
-		AccessKey: "AKIDEXAMPLE",
-		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
-		Region:    "cn-north-1",
-		Service:   "host",
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "host" },
 	}
 	r, _ := http.NewRequest("POST", "http://host.foo.com/", ioutil.NopCloser(bytes.NewBuffer([]byte("foo=bar"))))
 	r.Header.Add("date", "Mon, 09 Sep 2011 23:36:00 GMT")
@@ -115,3 +115,177 @@ func TestPostHeader(t *testing.T) {
 		t.Fatal("wrong body")
 	}
 }
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestCanonicalRequestVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		path    string
+		headers [][2]string
+		allowed []string
+		uri     string
+		query   string
+		header  string
+		signed  string
+	}{
+		{name: "vanilla", url: "http://host.foo.com", uri: "/", header: "host:host.foo.com", signed: "host"},
+		{name: "trailing slash", url: "http://host.foo.com/foo/", uri: "/foo/", header: "host:host.foo.com", signed: "host"},
+		{name: "encoded space", url: "http://host.foo.com/%20/foo", uri: "/%20/foo/", header: "host:host.foo.com", signed: "host"},
+		{name: "raw space", url: "http://host.foo.com", path: "/my bucket/a b", uri: "/my%20bucket/a%20b/", header: "host:host.foo.com", signed: "host"},
+		{name: "encoded slash", url: "http://host.foo.com/a%2Fb/c", uri: "/a%2Fb/c/", header: "host:host.foo.com", signed: "host"},
+		{name: "encoded percent", url: "http://host.foo.com/v1/100%25", uri: "/v1/100%25/", header: "host:host.foo.com", signed: "host"},
+		{name: "raw percent", url: "http://host.foo.com", path: "/v1/100%", uri: "/v1/100%25/", header: "host:host.foo.com", signed: "host"},
+		{name: "utf8", url: "http://host.foo.com/%E1%88%B4", uri: "/%E1%88%B4/", header: "host:host.foo.com", signed: "host"},
+		{name: "reserved", url: "http://host.foo.com/a+b=c", uri: "/a%2Bb%3Dc/", header: "host:host.foo.com", signed: "host"},
+		{name: "unreserved", url: "http://host.foo.com/a~b-c_d.e", uri: "/a~b-c_d.e/", header: "host:host.foo.com", signed: "host"},
+		{name: "dot segments", url: "http://host.foo.com/a/./b/../c", uri: "/a/./b/../c/", header: "host:host.foo.com", signed: "host"},
+		{name: "query order", url: "http://host.foo.com/?b=2&a=3&c=1", uri: "/", query: "a=3&b=2&c=1", header: "host:host.foo.com", signed: "host"},
+		{name: "query repeated keys", url: "http://host.foo.com/?b=2&a=3&b=1", uri: "/", query: "a=3&b=1&b=2", header: "host:host.foo.com", signed: "host"},
+		{name: "query key case", url: "http://host.foo.com/?param=2&Param=1", uri: "/", query: "Param=1&param=2", header: "host:host.foo.com", signed: "host"},
+		{name: "query empty values", url: "http://host.foo.com/?foo&bar=", uri: "/", query: "bar=&foo=", header: "host:host.foo.com", signed: "host"},
+		{name: "query spaces", url: "http://host.foo.com/?q=a+b&r=a%20b&s=a%2Bb", uri: "/", query: "q=a%20b&r=a%20b&s=a%2Bb", header: "host:host.foo.com", signed: "host"},
+		{name: "query utf8", url: "http://host.foo.com/?%E1%88%B4=bar", uri: "/", query: "%E1%88%B4=bar", header: "host:host.foo.com", signed: "host"},
+		{name: "query reserved", url: "http://host.foo.com/?a=b%2Fc%3Dd&e=*", uri: "/", query: "a=b%2Fc%3Dd&e=%2A", header: "host:host.foo.com", signed: "host"},
+		{
+			name:    "header case",
+			url:     "http://host.foo.com/",
+			headers: [][2]string{{"Content-Type", "application/json"}, {"X-Sdk-Date", "20110909T233600Z"}},
+			uri:     "/",
+			header:  "content-type:application/json\nhost:host.foo.com\nx-sdk-date:20110909T233600Z",
+			signed:  "content-type;host;x-sdk-date",
+		},
+		{
+			name:    "header value whitespace",
+			url:     "http://host.foo.com/",
+			headers: [][2]string{{"X-Project-Id", "  a   b  "}, {"X-Domain-Id", `"a   b"`}},
+			uri:     "/",
+			header:  "host:host.foo.com\nx-domain-id:\"a   b\"\nx-project-id:a b",
+			signed:  "host;x-domain-id;x-project-id",
+		},
+		{
+			name:    "headers outside allow-list",
+			url:     "http://host.foo.com/",
+			headers: [][2]string{{"X-Forwarded-For", "10.0.0.1"}, {"User-Agent", "curl"}, {"Date", "Mon, 09 Sep 2011 23:36:00 GMT"}},
+			uri:     "/",
+			header:  "date:Mon, 09 Sep 2011 23:36:00 GMT\nhost:host.foo.com",
+			signed:  "date;host",
+		},
+		{
+			name:    "multi value header",
+			url:     "http://host.foo.com/",
+			headers: [][2]string{{"My-Header", "value2"}, {"My-Header", "value1"}, {"my-header", "value3"}},
+			allowed: []string{"My-Header"},
+			uri:     "/",
+			header:  "host:host.foo.com\nmy-header:value1\nmy-header:value2\nmy-header:value3",
+			signed:  "host;my-header",
+		},
+		{
+			name:   "method",
+			method: "DELETE",
+			url:    "http://host.foo.com/v2.0/lbaas/pools/1/members/2",
+			uri:    "/v2.0/lbaas/pools/1/members/2/",
+			header: "host:host.foo.com",
+			signed: "host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			r, err := http.NewRequest(method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.path != "" {
+				r.URL.Path = tt.path
+			}
+			for _, h := range tt.headers {
+				r.Header.Add(h[0], h[1])
+			}
+			allowed := tt.allowed
+			if allowed == nil {
+				allowed = DefaultSignedHeaders
+			}
+			path, rawPath, rawQuery := r.URL.Path, r.URL.RawPath, r.URL.RawQuery
+			v, err := canonicalRequest(r, signedHeaderKeys(r, allowed), HMACSHA256)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := fmt.Sprintf("%s\n%s\n%s\n%s\n\n%s\n%s", method, tt.uri, tt.query, tt.header, tt.signed, emptyPayloadHash)
+			if v != expected {
+				t.Fatalf("wrong canonical request\nexpected:\n%s\ngot:\n%s", expected, v)
+			}
+			if r.URL.Path != path || r.URL.RawPath != rawPath || r.URL.RawQuery != rawQuery {
+				t.Fatalf("request url is modified: %s", r.URL.String())
+			}
+		})
+	}
+}
+
+func TestRoundTripDoesNotModifyRequest(t *testing.T) {
+	var signed *http.Request
+	s := Signer{
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "host" },
+		NextTransport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			signed = r
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+		}),
+	}
+	r, _ := http.NewRequest("POST", "http://host.foo.com/a%2Fb/100%25?x=1", bytes.NewBufferString("foo=bar"))
+	if _, err := s.RoundTrip(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Header.Get(HeaderAuthorization) != "" || r.Header.Get(HeaderXDate) != "" {
+		t.Fatal("headers of the original request are modified")
+	}
+	if r.URL.EscapedPath() != "/a%2Fb/100%25" || r.URL.RawQuery != "x=1" {
+		t.Fatal("url of the original request is modified", r.URL.String())
+	}
+	if signed.Header.Get(HeaderAuthorization) == "" {
+		t.Fatal("sent request is not signed")
+	}
+	if signed.URL.EscapedPath() != "/a%2Fb/100%25" {
+		t.Fatal("url of the sent request is modified", signed.URL.String())
+	}
+	b, _ := ioutil.ReadAll(signed.Body)
+	if string(b) != "foo=bar" {
+		t.Fatal("wrong body", string(b))
+	}
+}
+
+func TestProxyHeadersDoNotBreakSignature(t *testing.T) {
+	s := Signer{
+		AccessKey:          "AKIDEXAMPLE",
+		SecretKey:          "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:             "cn-north-1",
+		GetServiceNameFunc: func() string { return "host" },
+	}
+	r, _ := http.NewRequest("GET", "http://host.foo.com/%20/foo", nil)
+	r.Header.Add("date", "Mon, 09 Sep 2011 23:36:00 GMT")
+	if err := s.Sign(r); err != nil {
+		t.Fatal(err)
+	}
+	before := r.Header.Get(HeaderAuthorization)
+	r.Header.Add("X-Forwarded-For", "10.0.0.1")
+	r.Header.Add("Via", "1.1 proxy")
+	if err := s.Sign(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Header.Get(HeaderAuthorization) != before {
+		t.Fatal("signature changes after proxy headers are added")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,46 @@
+package sm3
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		// GB/T 32905-2016 appendix A
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+		{"", "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf("%x", Sum([]byte(tt.in))); got != tt.out {
+			t.Fatalf("sm3(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestWriteInChunks(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 50))
+	want := Sum(data)
+	for _, size := range []int{1, 7, 63, 64, 65, 200} {
+		h := New()
+		for i := 0; i < len(data); i += size {
+			end := i + size
+			if end > len(data) {
+				end = len(data)
+			}
+			h.Write(data[i:end])
+		}
+		if got := h.Sum(nil); fmt.Sprintf("%x", got) != fmt.Sprintf("%x", want) {
+			t.Fatalf("chunk size %d: got %x, want %x", size, got, want)
+		}
+		// Sum must not change the state of the hash
+		if got := h.Sum(nil); fmt.Sprintf("%x", got) != fmt.Sprintf("%x", want) {
+			t.Fatalf("chunk size %d: second sum differs", size)
+		}
+	}
+}